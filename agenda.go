@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// defaultAgendaDays is how many days the agenda covers by default
+const defaultAgendaDays = 7
+
+// AgendaItem is a time block or a due date on one day of the agenda
+type AgendaItem struct {
+	Todo Todo
+	Due  bool      // A due date rather than a time block
+	At   time.Time // When the item falls on its day, for ordering
+}
+
+// AgendaDay is everything happening on one day
+type AgendaDay struct {
+	Date  time.Time
+	Items []AgendaItem
+}
+
+// Agenda lists overdue todos and the coming days in order
+type Agenda struct {
+	Overdue []Todo
+	Days    []AgendaDay
+}
+
+// BuildAgenda collects the blocks and due dates for the given number of
+// days starting today, along with unfinished todos that slipped before today
+func BuildAgenda(db Store, clock Clock, days int) (*Agenda, error) {
+	today := startOfDay(clock.Now())
+	last := today.AddDate(0, 0, days-1)
+
+	overdue, err := db.GetOverdueTodos(today)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduled, err := db.GetRangeTodos(today, last)
+	if err != nil {
+		return nil, err
+	}
+
+	due, err := db.GetDueTodos(today, last)
+	if err != nil {
+		return nil, err
+	}
+
+	agenda := &Agenda{Overdue: overdue}
+	for i := 0; i < days; i++ {
+		date := today.AddDate(0, 0, i)
+		next := date.AddDate(0, 0, 1)
+		day := AgendaDay{Date: date}
+
+		for _, todo := range scheduled {
+			if !blockOverlaps(todo, date, next) {
+				continue
+			}
+			// Blocks carried over from an earlier day sort to the top
+			at := *todo.ScheduledStart
+			if todo.AllDay || at.Before(date) {
+				at = date
+			}
+			day.Items = append(day.Items, AgendaItem{Todo: todo, At: at})
+		}
+
+		for _, todo := range due {
+			if !todo.DueDate.Before(date) && todo.DueDate.Before(next) {
+				day.Items = append(day.Items, AgendaItem{Todo: todo, Due: true, At: *todo.DueDate})
+			}
+		}
+
+		sort.SliceStable(day.Items, func(a, b int) bool {
+			return day.Items[a].At.Before(day.Items[b].At)
+		})
+		agenda.Days = append(agenda.Days, day)
+	}
+
+	return agenda, nil
+}
+
+// isEndOfDay reports whether t is the 23:59:59 used for date-only due dates
+func isEndOfDay(t time.Time) bool {
+	return t.Hour() == 23 && t.Minute() == 59 && t.Second() == 59
+}
+
+// agendaTime is the time column of an agenda line
+func agendaTime(item AgendaItem, day time.Time) string {
+	if item.Due {
+		if isEndOfDay(*item.Todo.DueDate) {
+			return "due"
+		}
+		return "due " + activeLocale.Clock(*item.Todo.DueDate)
+	}
+	return formatBlockOnDay(item.Todo, day)
+}
+
+// Render formats the agenda for display
+func (a *Agenda) Render(clock Clock) string {
+	var s strings.Builder
+
+	title := "📋 Agenda"
+	if len(a.Days) == 1 {
+		title = fmt.Sprintf("📋 Agenda: %s", activeLocale.DateYear(a.Days[0].Date))
+	} else if len(a.Days) > 1 {
+		title = fmt.Sprintf("📋 Agenda: %s - %s",
+			activeLocale.Date(a.Days[0].Date),
+			activeLocale.DateYear(a.Days[len(a.Days)-1].Date))
+	}
+	s.WriteString(titleStyle.Render(title))
+	s.WriteString("\n\n")
+
+	if len(a.Overdue) > 0 {
+		s.WriteString(errorStyle.Render("Overdue"))
+		s.WriteString("\n")
+		for _, todo := range a.Overdue {
+			since := FormatDueDate(overdueSince(todo, clock.Now()), clock)
+			line := fmt.Sprintf("  %s %s", overdueStyle.Render(fmt.Sprintf("%-16s", since)), agendaTitle(todo))
+			s.WriteString(line)
+			s.WriteString("\n")
+		}
+		s.WriteString("\n")
+	}
+
+	today := startOfDay(clock.Now())
+	for _, day := range a.Days {
+		dayTitle := activeLocale.WeekdayDate(day.Date)
+		dayStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color(ColorBlue)).
+			Bold(true)
+		if day.Date.Equal(today) {
+			dayTitle += " (Today)"
+			dayStyle = dayStyle.Foreground(lipgloss.Color(ColorYellow))
+		}
+		s.WriteString(dayStyle.Render(dayTitle))
+		s.WriteString("\n")
+
+		if len(day.Items) == 0 {
+			s.WriteString(descStyle.Render("  Nothing scheduled or due"))
+			s.WriteString("\n\n")
+			continue
+		}
+
+		for _, item := range day.Items {
+			timeStr := fmt.Sprintf("%-16s", agendaTime(item, day.Date))
+			if item.Due {
+				color := GetDueDateColor(item.Todo.DueDate, clock)
+				if item.Todo.Done {
+					color = ColorGray
+				}
+				timeStr = lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Render(timeStr)
+			}
+
+			line := fmt.Sprintf("  %s %s", timeStr, agendaTitle(item.Todo))
+			if item.Todo.Done {
+				line = fmt.Sprintf("  %s %s", timeStr, completedStyle.Render(item.Todo.Title))
+			}
+			s.WriteString(line)
+			s.WriteString("\n")
+		}
+		s.WriteString("\n")
+	}
+
+	return s.String()
+}
+
+// agendaTitle is a todo's title followed by its labels
+func agendaTitle(todo Todo) string {
+	title := todo.Title
+	if labels := FormatLabels(todo); labels != "" {
+		title += " " + descStyle.Render(labels)
+	}
+	return title
+}
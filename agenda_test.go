@@ -0,0 +1,111 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// addAgendaTodos fills store with a week of blocks and due dates around
+// testNow, Monday 19 October 2026
+func addAgendaTodos(t *testing.T, store Store) {
+	t.Helper()
+	clock := NewFixedClock(testNow)
+	addTodos(t, store, clock,
+		"Standup @ 9am-9:15am",
+		"Pay rent due:today 5pm",
+		"Dentist @ tomorrow 2pm-3pm",
+		"Conference @ wed-thu",
+		"Report due:wed",
+		"Taxes due:nov 20",
+	)
+
+	// Due dates that have already passed, one of them finished
+	for _, title := range []string{"Renew passport", "Return library book"} {
+		if err := store.AddTodo(Todo{Title: title, DueDate: ptr(endOfDayUTC(2026, 10, 12))}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.ToggleTodo(8); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuildAgenda(t *testing.T) {
+	for name, store := range map[string]Store{"memory": newTestStore(), "database": newTestDB(t)} {
+		addAgendaTodos(t, store)
+
+		agenda, err := BuildAgenda(store, NewFixedClock(testNow), 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := titles(agenda.Overdue); !slices.Equal(got, []string{"Renew passport"}) {
+			t.Errorf("%s: overdue %q", name, got)
+		}
+
+		want := [][]string{
+			{"Standup", "Pay rent"},
+			{"Dentist"},
+			{"Conference", "Report"},
+		}
+		if len(agenda.Days) != len(want) {
+			t.Fatalf("%s: got %d days, want %d", name, len(agenda.Days), len(want))
+		}
+		for i, day := range agenda.Days {
+			var got []string
+			for _, item := range day.Items {
+				got = append(got, item.Todo.Title)
+			}
+			if !day.Date.Equal(day0(i)) || !slices.Equal(got, want[i]) {
+				t.Errorf("%s: day %d is %v with %q, want %v with %q", name, i, day.Date, got, day0(i), want[i])
+			}
+		}
+	}
+}
+
+// day0 is midnight i days after testNow
+func day0(i int) time.Time {
+	return day(2026, 10, 19+i, 0, 0)
+}
+
+func TestAgendaRender(t *testing.T) {
+	store := newTestStore()
+	addAgendaTodos(t, store)
+	clock := NewFixedClock(testNow)
+
+	agenda, err := BuildAgenda(store, clock, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, agenda.Render(clock),
+		"📋 Agenda: Oct 19 - Oct 21, 2026",
+		"Overdue\n  7 days overdue",
+		"Renew passport",
+		"Monday, Oct 19 (Today)\n  9:00am-9:15am    Standup\n  due 5:00pm       Pay rent",
+		"Tuesday, Oct 20\n  2:00pm-3:00pm    Dentist",
+		"Wednesday, Oct 21\n  all day          Conference\n  due              Report",
+	)
+}
+
+func TestCalendarMarksDueDates(t *testing.T) {
+	store := newTestStore()
+	addAgendaTodos(t, store)
+
+	calendar := NewCalendar(store, NewFixedClock(testNow), DefaultCalendarOptions(), testNow, MonthView)
+	if err := calendar.LoadTodos(); err != nil {
+		t.Fatal(err)
+	}
+	// Blocks are marked with • and unfinished due dates with !
+	assertContains(t, calendar.Render(), "20 •", "21 •!", "22 •", "12 !")
+}
+
+func TestCLIAgenda(t *testing.T) {
+	c, store, out := newTestCLI()
+	addAgendaTodos(t, store)
+
+	output := run(c, out, "agenda")
+	assertContains(t, output, "📋 Agenda: Oct 19 - Oct 25, 2026", "Sunday, Oct 25")
+	assertContains(t, run(c, out, "agenda", "1"), "📋 Agenda: Oct 19, 2026", "Pay rent")
+	assertContains(t, run(c, out, "agenda", "0"), "Error: Invalid number of days '0'")
+}
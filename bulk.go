@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// bulkConfirmThreshold is the most todos a bulk command changes without
+// asking first
+const bulkConfirmThreshold = 10
+
+// maxIDRange stops a typo like 1-100000 from expanding into a huge list
+const maxIDRange = 1000
+
+// idListPattern matches ID lists like "3", "4,9" and "7-12,15"
+var idListPattern = regexp.MustCompile(`^\d+(-\d+)?(,\d+(-\d+)?)*,?$`)
+
+// BulkEdit is one change made to many todos at once
+type BulkEdit struct {
+	Delete     bool
+	Toggle     bool
+	Done       *bool // Mark done or not done, whatever the todo was
+	AddTags    []string
+	RemoveTags []string
+	Project    *string    // Move every todo to this project; "" for none
+	Block      *TimeBlock // Schedule every todo into this block
+}
+
+// apply returns todo with the edit made. Deleting is left to the store.
+func (e BulkEdit) apply(todo Todo) Todo {
+	if e.Toggle {
+		todo.Done = !todo.Done
+	}
+	if e.Done != nil {
+		todo.Done = *e.Done
+	}
+
+	if len(e.AddTags) > 0 || len(e.RemoveTags) > 0 {
+		var tags []string
+		for _, tag := range todo.Tags {
+			if !containsFold(e.RemoveTags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+		for _, tag := range e.AddTags {
+			if !containsFold(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+		todo.Tags = tags
+	}
+
+	if e.Project != nil {
+		todo.Project = *e.Project
+	}
+
+	if e.Block != nil {
+		todo.ScheduledStart = e.Block.Start
+		todo.ScheduledEnd = e.Block.End
+		todo.AllDay = e.Block.AllDay
+	}
+
+	return todo
+}
+
+// changes reports whether the edit does anything to todo
+func (e BulkEdit) changes(todo Todo) bool {
+	return e.Delete || len(changedFieldValues(todo, e.apply(todo))) > 0
+}
+
+// parseIDList reads IDs like "3", "4,9" and "7-12" from the front of args.
+// It returns them in order without repeats, along with the args after them.
+func parseIDList(args []string) ([]int, []string, error) {
+	var ids []int
+	seen := make(map[int]bool)
+
+	i := 0
+	for ; i < len(args) && idListPattern.MatchString(args[i]); i++ {
+		for _, part := range strings.Split(strings.TrimSuffix(args[i], ","), ",") {
+			first, last, isRange := strings.Cut(part, "-")
+			if !isRange {
+				last = first
+			}
+			start, err := strconv.Atoi(first)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid ID %q", part)
+			}
+			end, err := strconv.Atoi(last)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid ID %q", part)
+			}
+			if end < start {
+				return nil, nil, fmt.Errorf("range %s runs backwards", part)
+			}
+			if end-start >= maxIDRange {
+				return nil, nil, fmt.Errorf("range %s covers more than %d todos", part, maxIDRange)
+			}
+
+			for id := start; id <= end; id++ {
+				if !seen[id] {
+					seen[id] = true
+					ids = append(ids, id)
+				}
+			}
+		}
+	}
+
+	return ids, args[i:], nil
+}
+
+// parseTagChanges reads tag changes like "+urgent", "#urgent" or "-later",
+// returning the tags to add and to remove
+func parseTagChanges(changes []string) ([]string, []string, error) {
+	var add, remove []string
+	for _, change := range changes {
+		valid := len(change) > 1 && smartTagPattern.MatchString("#"+change[1:])
+		switch {
+		case valid && (change[0] == '+' || change[0] == '#'):
+			add = append(add, change[1:])
+		case valid && change[0] == '-':
+			remove = append(remove, change[1:])
+		default:
+			return nil, nil, fmt.Errorf("invalid tag change %q; use +tag to add or -tag to remove", change)
+		}
+	}
+	return add, remove, nil
+}
+
+// sharedEstimate is the estimate todos have in common, or 0 if they differ.
+// A block given only a start time lasts this long.
+func sharedEstimate(todos []Todo) time.Duration {
+	if len(todos) == 0 {
+		return 0
+	}
+	for _, todo := range todos[1:] {
+		if todo.Estimate != todos[0].Estimate {
+			return 0
+		}
+	}
+	return todos[0].Estimate
+}
+
+// cutFlag removes a boolean flag from args, reporting whether it was there
+func cutFlag(args []string, names ...string) (bool, []string) {
+	found := false
+	var rest []string
+	for _, arg := range args {
+		if slices.Contains(names, arg) {
+			found = true
+		} else {
+			rest = append(rest, arg)
+		}
+	}
+	return found, rest
+}
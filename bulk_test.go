@@ -0,0 +1,126 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseIDList(t *testing.T) {
+	tests := []struct {
+		args []string
+		ids  []int
+		rest []string
+	}{
+		{args: []string{"3"}, ids: []int{3}},
+		{args: []string{"4,9", "12"}, ids: []int{4, 9, 12}},
+		{args: []string{"7-9,15", "+work"}, ids: []int{7, 8, 9, 15}, rest: []string{"+work"}},
+		{args: []string{"3", "1-4", "3,"}, ids: []int{3, 1, 2, 4}},
+		{args: []string{"friday", "3"}, rest: []string{"friday", "3"}},
+	}
+	for _, tt := range tests {
+		ids, rest, err := parseIDList(tt.args)
+		if err != nil {
+			t.Errorf("parseIDList(%q): %v", tt.args, err)
+			continue
+		}
+		if !slices.Equal(ids, tt.ids) || !slices.Equal(rest, tt.rest) {
+			t.Errorf("parseIDList(%q) = %v, %q; want %v, %q", tt.args, ids, rest, tt.ids, tt.rest)
+		}
+	}
+
+	for _, bad := range []string{"9-3", "1-5000"} {
+		if _, _, err := parseIDList([]string{bad}); err == nil {
+			t.Errorf("parseIDList(%q) should fail", bad)
+		}
+	}
+}
+
+func TestBulkEditApply(t *testing.T) {
+	todo := Todo{ID: 1, Title: "Report", Tags: []string{"work", "Urgent"}}
+	done := true
+
+	edited := BulkEdit{Done: &done, AddTags: []string{"q4", "WORK"}, RemoveTags: []string{"urgent"}}.apply(todo)
+	if !edited.Done || !slices.Equal(edited.Tags, []string{"work", "q4"}) {
+		t.Errorf("apply = done %v, tags %q; want done with tags [work q4]", edited.Done, edited.Tags)
+	}
+	if len(todo.Tags) != 2 {
+		t.Errorf("apply changed the original todo's tags: %q", todo.Tags)
+	}
+
+	if (BulkEdit{AddTags: []string{"Work"}}).changes(todo) {
+		t.Error("adding a tag the todo already has shouldn't count as a change")
+	}
+	if !(BulkEdit{Toggle: true}).changes(todo) || !(BulkEdit{Delete: true}).changes(todo) {
+		t.Error("toggling and deleting always change a todo")
+	}
+
+	block := &TimeBlock{Start: at(2026, 10, 23, 0, 0), End: at(2026, 10, 24, 0, 0), AllDay: true}
+	edited = BulkEdit{Block: block}.apply(todo)
+	if !edited.AllDay || !edited.ScheduledStart.Equal(*block.Start) {
+		t.Errorf("apply didn't schedule the block: %+v", edited)
+	}
+}
+
+func TestApplyBulkEdit(t *testing.T) {
+	for name, store := range map[string]Store{"memory": newTestStore(), "database": newTestDB(t)} {
+		t.Run(name, func(t *testing.T) {
+			addTodos(t, store, NewFixedClock(testNow), "One #a", "Two", "Three")
+
+			if err := store.ApplyBulkEdit([]int{1, 99}, BulkEdit{Toggle: true}); err == nil {
+				t.Fatal("a missing ID should fail the whole edit")
+			}
+			if todo, _ := store.GetTodo(1); todo.Done {
+				t.Error("a failed bulk edit changed todo 1")
+			}
+
+			if err := store.ApplyBulkEdit([]int{1, 2}, BulkEdit{AddTags: []string{"b"}, RemoveTags: []string{"a"}}); err != nil {
+				t.Fatal(err)
+			}
+			for _, id := range []int{1, 2} {
+				if todo, _ := store.GetTodo(id); !slices.Equal(todo.Tags, []string{"b"}) {
+					t.Errorf("todo %d tags = %q, want [b]", id, todo.Tags)
+				}
+			}
+
+			// Repeated IDs are edited once
+			if err := store.ApplyBulkEdit([]int{1, 1}, BulkEdit{Toggle: true}); err != nil {
+				t.Fatal(err)
+			}
+			if todo, _ := store.GetTodo(1); !todo.Done {
+				t.Error("toggling todo 1 listed twice should leave it done")
+			}
+
+			if err := store.ApplyBulkEdit([]int{2, 3, 2}, BulkEdit{Delete: true}); err != nil {
+				t.Fatal(err)
+			}
+			if todos, _ := store.GetAllTodos(); !slices.Equal(titles(todos), []string{"One"}) {
+				t.Errorf("left %q after deleting, want [One]", titles(todos))
+			}
+		})
+	}
+}
+
+func TestCLIBulkCommands(t *testing.T) {
+	c, store, out := newTestCLI()
+	for i := 1; i <= 12; i++ {
+		addScheduled(t, store, "Todo", nil, nil)
+	}
+
+	output := run(c, out, "done", "1-3,20")
+	assertContains(t, output, "Skipping missing todos: 20", "Marked 3 todos done")
+	assertContains(t, run(c, out, "done", "2,4"), "1 of 2 already matched")
+
+	assertContains(t, run(c, out, "tag", "1", "2", "+work", "#q4"), "Tagged 2 todos +work #q4")
+	assertContains(t, run(c, out, "tag", "1", "work"), `invalid tag change "work"`)
+	assertContains(t, run(c, out, "done", "1", "friday"), "Invalid ID 'friday'")
+
+	// More than bulkConfirmThreshold todos asks first
+	c.in = strings.NewReader("n\n")
+	assertContains(t, run(c, out, "delete", "1-12"), "Delete 12 todos? [y/N]", "Nothing changed")
+	if todos, _ := store.GetAllTodos(); len(todos) != 12 {
+		t.Errorf("declining deleted todos, %d left", len(todos))
+	}
+	assertContains(t, run(c, out, "delete", "--filter", "#work", "--yes"), "Deleted 2 todos")
+	assertContains(t, run(c, out, "schedule", "3,5", "friday all day"), "Scheduled 2 todos")
+}
@@ -14,24 +14,47 @@ type CalendarView int
 const (
 	MonthView CalendarView = iota
 	WeekView
+	DayView
+	YearView
 )
 
+// CalendarOptions are the calendar display settings from the config
+type CalendarOptions struct {
+	WorkStart int // First hour shown in time grids
+	WorkEnd   int // Hour time grids run until
+
+	WeekNumbers bool         // Show ISO week numbers beside the month view
+	WeekStart   time.Weekday // First day of each week row
+}
+
+// DefaultCalendarOptions returns the settings used without a config
+func DefaultCalendarOptions() CalendarOptions {
+	return CalendarOptions{WorkStart: defaultWorkStart, WorkEnd: defaultWorkEnd}
+}
+
 // Calendar represents a calendar with todos
 type Calendar struct {
-	db      *DB
+	db      Store
+	clock   Clock
+	options CalendarOptions
 	date    time.Time
 	view    CalendarView
+	grid    bool // Draw the week as a time grid rather than a list
 	todos   []Todo
 	todoMap map[string][]Todo // Key: YYYY-MM-DD, Value: todos for that day
+	dueMap  map[string][]Todo // Key: YYYY-MM-DD, Value: todos due that day
 }
 
 // NewCalendar creates a new calendar instance
-func NewCalendar(db *DB, date time.Time, view CalendarView) *Calendar {
+func NewCalendar(db Store, clock Clock, options CalendarOptions, date time.Time, view CalendarView) *Calendar {
 	return &Calendar{
 		db:      db,
+		clock:   clock,
+		options: options,
 		date:    date,
 		view:    view,
 		todoMap: make(map[string][]Todo),
+		dueMap:  make(map[string][]Todo),
 	}
 }
 
@@ -40,13 +63,32 @@ func (c *Calendar) LoadTodos() error {
 	var todos []Todo
 	var err error
 
+	c.dueMap = make(map[string][]Todo)
+
 	switch c.view {
 	case MonthView:
 		todos, err = c.db.GetMonthTodos(c.date)
+		if err != nil {
+			return err
+		}
+
+		// Month cells also mark the days todos are due
+		firstDay := time.Date(c.date.Year(), c.date.Month(), 1, 0, 0, 0, 0, c.date.Location())
+		var due []Todo
+		due, err = c.db.GetDueTodos(firstDay, firstDay.AddDate(0, 1, -1))
+		for _, todo := range due {
+			dateKey := todo.DueDate.Format("2006-01-02")
+			c.dueMap[dateKey] = append(c.dueMap[dateKey], todo)
+		}
 	case WeekView:
 		startOfWeek := c.getStartOfWeek(c.date)
 		endOfWeek := startOfWeek.AddDate(0, 0, 6)
 		todos, err = c.db.GetRangeTodos(startOfWeek, endOfWeek)
+	case DayView:
+		todos, err = c.db.GetDateTodos(c.date)
+	case YearView:
+		firstDay := time.Date(c.date.Year(), time.January, 1, 0, 0, 0, 0, c.date.Location())
+		todos, err = c.db.GetRangeTodos(firstDay, firstDay.AddDate(1, 0, -1))
 	}
 
 	if err != nil {
@@ -58,17 +100,55 @@ func (c *Calendar) LoadTodos() error {
 	return nil
 }
 
-// buildTodoMap organizes todos by date
+// buildTodoMap organizes todos by date, listing a block on every day it overlaps
 func (c *Calendar) buildTodoMap() {
 	c.todoMap = make(map[string][]Todo)
 	for _, todo := range c.todos {
-		if todo.ScheduledStart != nil {
-			dateKey := todo.ScheduledStart.Format("2006-01-02")
+		if todo.ScheduledStart == nil {
+			continue
+		}
+
+		day := startOfDay(*todo.ScheduledStart)
+		for {
+			dateKey := day.Format("2006-01-02")
 			c.todoMap[dateKey] = append(c.todoMap[dateKey], todo)
+
+			day = day.AddDate(0, 0, 1)
+			if todo.ScheduledEnd == nil || !day.Before(*todo.ScheduledEnd) {
+				break
+			}
 		}
 	}
 }
 
+// formatBlockOnDay describes the part of a todo's block that falls on day,
+// e.g. "2:00pm-3:00pm", "all day" or "from 10:00pm"
+func formatBlockOnDay(todo Todo, day time.Time) string {
+	start, end := todo.ScheduledStart, todo.ScheduledEnd
+	if start == nil {
+		return ""
+	}
+	if todo.AllDay {
+		return activeLocale.AllDay
+	}
+
+	nextDay := day.AddDate(0, 0, 1)
+	startsToday := !start.Before(day)
+	endsToday := end == nil || !end.After(nextDay)
+
+	switch {
+	case end == nil:
+		return activeLocale.Clock(*start)
+	case startsToday && endsToday:
+		return fmt.Sprintf("%s-%s", activeLocale.Clock(*start), activeLocale.Clock(*end))
+	case startsToday:
+		return "from " + activeLocale.Clock(*start)
+	case endsToday:
+		return "until " + activeLocale.Clock(*end)
+	}
+	return activeLocale.AllDay
+}
+
 // GetDate returns the current date of the calendar
 func (c *Calendar) GetDate() time.Time {
 	return c.date
@@ -79,13 +159,45 @@ func (c *Calendar) GetView() CalendarView {
 	return c.view
 }
 
+// SetView switches the view mode, keeping the current date
+func (c *Calendar) SetView(view CalendarView) {
+	c.view = view
+}
+
+// SetGrid chooses between the time grid and the list for the week view
+func (c *Calendar) SetGrid(grid bool) {
+	c.grid = grid
+}
+
+// Grid reports whether the week view is drawn as a time grid
+func (c *Calendar) Grid() bool {
+	return c.grid
+}
+
+// SetWeekNumbers shows or hides ISO week numbers in the month view
+func (c *Calendar) SetWeekNumbers(show bool) {
+	c.options.WeekNumbers = show
+}
+
+// WeekNumbers reports whether the month view shows ISO week numbers
+func (c *Calendar) WeekNumbers() bool {
+	return c.options.WeekNumbers
+}
+
 // Render displays the calendar
 func (c *Calendar) Render() string {
 	switch c.view {
 	case MonthView:
 		return c.renderMonth()
 	case WeekView:
+		if c.grid {
+			return c.renderWeekGrid()
+		}
 		return c.renderWeek()
+	case DayView:
+		return c.renderDayGrid()
+	case YearView:
+		return c.renderYear()
 	default:
 		return "Unknown calendar view"
 	}
@@ -96,7 +208,7 @@ func (c *Calendar) renderMonth() string {
 	var s strings.Builder
 
 	// Calendar title
-	title := fmt.Sprintf("📅 %s", c.date.Format("January 2006"))
+	title := fmt.Sprintf("📅 %s", activeLocale.MonthYear(c.date))
 	s.WriteString(titleStyle.Render(title))
 	s.WriteString("\n\n")
 
@@ -105,13 +217,16 @@ func (c *Calendar) renderMonth() string {
 	startDay := c.getStartOfWeek(firstDay)
 
 	// Days of week header
-	weekdays := []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
+	weekdays := c.weekdayNames(3)
 	headerStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color(ColorBlue)).
 		Bold(true).
 		Width(10).
 		Align(lipgloss.Center)
 
+	if c.options.WeekNumbers {
+		s.WriteString(weekNumberStyle.Render("Wk"))
+	}
 	for _, day := range weekdays {
 		s.WriteString(headerStyle.Render(day))
 	}
@@ -122,6 +237,9 @@ func (c *Calendar) renderMonth() string {
 	for week := 0; week < 6; week++ {
 		weekEmpty := true
 		var weekLine strings.Builder
+		if c.options.WeekNumbers {
+			weekLine.WriteString(weekNumberStyle.Render(fmt.Sprintf("%d", isoWeekOfRow(current))))
+		}
 
 		for day := 0; day < 7; day++ {
 			nextMonth := c.date.AddDate(0, 1, 0).Month()
@@ -148,13 +266,13 @@ func (c *Calendar) renderMonth() string {
 	// Todo list for the month
 	if len(c.todos) > 0 {
 		s.WriteString("\n")
-		s.WriteString(titleStyle.Render("Scheduled for " + c.date.Format("January 2006") + ":"))
+		s.WriteString(titleStyle.Render("Scheduled for " + activeLocale.MonthYear(c.date) + ":"))
 		s.WriteString("\n\n")
 
 		currentDate := ""
 		for _, todo := range c.todos {
 			if todo.ScheduledStart != nil {
-				todoDate := todo.ScheduledStart.Format("Jan 2")
+				todoDate := activeLocale.Date(*todo.ScheduledStart)
 				if todoDate != currentDate {
 					currentDate = todoDate
 					s.WriteString(lipgloss.NewStyle().
@@ -164,7 +282,7 @@ func (c *Calendar) renderMonth() string {
 					s.WriteString("\n")
 				}
 
-				timeBlock := FormatTimeBlock(todo.ScheduledStart, todo.ScheduledEnd)
+				timeBlock := FormatTimeBlock(todo.ScheduledStart, todo.ScheduledEnd, todo.AllDay)
 				timeStr := ""
 				if timeBlock != "" {
 					timeStr = strings.TrimPrefix(timeBlock, "Scheduled: ")
@@ -197,19 +315,20 @@ func (c *Calendar) renderWeek() string {
 
 	// Week title
 	title := fmt.Sprintf("📅 Week of %s - %s",
-		startOfWeek.Format("Jan 2"),
-		endOfWeek.Format("Jan 2, 2006"))
+		activeLocale.Date(startOfWeek),
+		activeLocale.DateYear(endOfWeek))
 	s.WriteString(titleStyle.Render(title))
 	s.WriteString("\n\n")
 
 	// Render each day of the week
 	current := startOfWeek
 	for i := 0; i < 7; i++ {
-		dayName := current.Format("Monday")
-		dayDate := current.Format("Jan 2")
+		dayName := activeLocale.Weekday(current.Weekday())
+		dayDate := activeLocale.Date(current)
 
-		isToday := current.Year() == time.Now().Year() &&
-			current.YearDay() == time.Now().YearDay()
+		now := c.clock.Now()
+		isToday := current.Year() == now.Year() &&
+			current.YearDay() == now.YearDay()
 
 		dayTitle := fmt.Sprintf("%s, %s", dayName, dayDate)
 		if isToday {
@@ -229,17 +348,10 @@ func (c *Calendar) renderWeek() string {
 		// Show todos for this day
 		dateKey := current.Format("2006-01-02")
 		if dayTodos, exists := c.todoMap[dateKey]; exists {
+			overlapping := overlappingIDs(dayTodos)
 			for _, todo := range dayTodos {
-				timeBlock := FormatTimeBlock(todo.ScheduledStart, todo.ScheduledEnd)
-				timeStr := ""
-				if timeBlock != "" {
-					timeStr = strings.TrimPrefix(timeBlock, "Scheduled: ")
-					// Remove date prefix since we're already showing the date
-					parts := strings.Split(timeStr, " ")
-					if len(parts) > 2 {
-						timeStr = strings.Join(parts[2:], " ")
-					}
-				}
+				// The date is already shown, so only show the time on this day
+				timeStr := formatBlockOnDay(todo, current)
 
 				todoText := fmt.Sprintf("  • %s", todo.Title)
 				if timeStr != "" {
@@ -248,6 +360,8 @@ func (c *Calendar) renderWeek() string {
 
 				if todo.Done {
 					todoText = completedStyle.Render(todoText)
+				} else if overlapping[todo.ID] {
+					todoText = overlapStyle.Render(todoText)
 				}
 
 				s.WriteString(todoText)
@@ -271,9 +385,16 @@ func (c *Calendar) renderDay(date time.Time) string {
 	dateKey := date.Format("2006-01-02")
 
 	isCurrentMonth := date.Month() == c.date.Month()
-	isToday := date.Year() == time.Now().Year() &&
-		date.YearDay() == time.Now().YearDay()
+	now := c.clock.Now()
+	isToday := date.Year() == now.Year() &&
+		date.YearDay() == now.YearDay()
 	hasTodos := len(c.todoMap[dateKey]) > 0
+	var firstDue *Todo
+	for i, todo := range c.dueMap[dateKey] {
+		if !todo.Done && firstDue == nil {
+			firstDue = &c.dueMap[dateKey][i]
+		}
+	}
 
 	// Base style
 	cellStyle := lipgloss.NewStyle().
@@ -292,12 +413,24 @@ func (c *Calendar) renderDay(date time.Time) string {
 			Foreground(lipgloss.Color(ColorWhite)).
 			Background(lipgloss.Color(ColorYellow)).
 			Bold(true)
-	} else if hasTodos {
-		// Highlight days with todos
+	} else if hasTodos || firstDue != nil {
+		// Highlight days with todos, marking scheduled blocks with • and
+		// unfinished due dates with !
+		color := ColorBlue
+		markers := ""
+		if hasTodos {
+			markers += "•"
+		}
+		if firstDue != nil {
+			markers += "!"
+			if !hasTodos {
+				color = GetDueDateColor(firstDue.DueDate, c.clock)
+			}
+		}
+		dayText += " " + markers
 		cellStyle = cellStyle.
-			Foreground(lipgloss.Color(ColorBlue)).
+			Foreground(lipgloss.Color(color)).
 			Bold(true)
-		dayText += " •"
 	} else {
 		// Regular day
 		cellStyle = cellStyle.Foreground(lipgloss.Color(ColorWhite))
@@ -306,29 +439,66 @@ func (c *Calendar) renderDay(date time.Time) string {
 	return cellStyle.Render(dayText)
 }
 
-// getStartOfWeek returns the start of the week (Sunday) for a given date
+// isoWeekOfRow returns the ISO week number for a calendar row starting at
+// date, taken from the row's Monday
+func isoWeekOfRow(date time.Time) int {
+	for i := 0; i < 7; i++ {
+		if day := date.AddDate(0, 0, i); day.Weekday() == time.Monday {
+			_, week := day.ISOWeek()
+			return week
+		}
+	}
+	_, week := date.ISOWeek()
+	return week
+}
+
+// getStartOfWeek returns the start of the week for a given date, using
+// the configured first day of the week
 func (c *Calendar) getStartOfWeek(date time.Time) time.Time {
-	weekday := int(date.Weekday())
-	start := date.AddDate(0, 0, -weekday)
-	return time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	return weekStartOf(date, c.options.WeekStart)
+}
+
+// weekStartOf returns midnight on the first day of date's week, for weeks
+// beginning on weekStart
+func weekStartOf(date time.Time, weekStart time.Weekday) time.Time {
+	offset := (int(date.Weekday()) - int(weekStart) + 7) % 7
+	return startOfDay(date.AddDate(0, 0, -offset))
+}
+
+// weekdayNames returns weekday abbreviations of at most n characters in
+// the order the week's columns are drawn
+func (c *Calendar) weekdayNames(n int) []string {
+	names := make([]string, 7)
+	for i := range names {
+		names[i] = activeLocale.ShortWeekday(time.Weekday((int(c.options.WeekStart)+i)%7), n)
+	}
+	return names
 }
 
-// Next moves to the next period (month or week)
+// Next moves to the next period (year, month, week or day)
 func (c *Calendar) Next() {
 	switch c.view {
 	case MonthView:
 		c.date = c.date.AddDate(0, 1, 0)
 	case WeekView:
 		c.date = c.date.AddDate(0, 0, 7)
+	case DayView:
+		c.date = c.date.AddDate(0, 0, 1)
+	case YearView:
+		c.date = c.date.AddDate(1, 0, 0)
 	}
 }
 
-// Previous moves to the previous period (month or week)
+// Previous moves to the previous period (year, month, week or day)
 func (c *Calendar) Previous() {
 	switch c.view {
 	case MonthView:
 		c.date = c.date.AddDate(0, -1, 0)
 	case WeekView:
 		c.date = c.date.AddDate(0, 0, -7)
+	case DayView:
+		c.date = c.date.AddDate(0, 0, -1)
+	case YearView:
+		c.date = c.date.AddDate(-1, 0, 0)
 	}
 }
@@ -0,0 +1,351 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// gridSlot is the length of time covered by one row of a time grid
+const gridSlot = 30 * time.Minute
+
+// Widths of the time grid, in columns
+const (
+	gridLabelWidth   = 8
+	dayGridWidth     = 48
+	weekGridDayWidth = 14
+)
+
+var (
+	gridBlockStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color(ColorWhite)).
+			Background(lipgloss.Color(ColorBlue))
+
+	gridDoneBlockStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color(ColorWhite)).
+				Background(lipgloss.Color(ColorGray))
+
+	gridLabelStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color(ColorGray))
+
+	gridNowStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color(ColorRed)).
+			Bold(true)
+)
+
+// gridBlock is a timed block clipped to a single day, along with the lane
+// it is drawn in so that overlapping blocks sit side by side
+type gridBlock struct {
+	todo  Todo
+	start time.Time
+	end   time.Time
+	lane  int
+	lanes int // Lanes shared by the blocks this one overlaps
+}
+
+// gridDay is one day column of a time grid
+type gridDay struct {
+	date   time.Time
+	blocks []gridBlock
+	allDay []Todo
+}
+
+// layoutDay clips the day's timed blocks to the day and assigns each the
+// first lane that is free when it starts. Blocks that overlap, directly or
+// through each other, split the column between them.
+func layoutDay(todos []Todo, date time.Time) gridDay {
+	day := gridDay{date: date}
+	next := date.AddDate(0, 0, 1)
+
+	for _, todo := range todos {
+		if todo.ScheduledStart == nil {
+			continue
+		}
+		if todo.AllDay {
+			day.allDay = append(day.allDay, todo)
+			continue
+		}
+
+		start := *todo.ScheduledStart
+		end := start.Add(gridSlot)
+		if todo.ScheduledEnd != nil && todo.ScheduledEnd.After(start) {
+			end = *todo.ScheduledEnd
+		}
+		if start.Before(date) {
+			start = date
+		}
+		if end.After(next) {
+			end = next
+		}
+		if end.After(start) {
+			day.blocks = append(day.blocks, gridBlock{todo: todo, start: start, end: end})
+		}
+	}
+
+	sort.SliceStable(day.blocks, func(i, j int) bool {
+		return day.blocks[i].start.Before(day.blocks[j].start)
+	})
+
+	var laneEnds []time.Time
+	var clusterEnd time.Time
+	clusterStart := 0
+	for i := range day.blocks {
+		block := &day.blocks[i]
+		if !block.start.Before(clusterEnd) {
+			setLanes(day.blocks[clusterStart:i], len(laneEnds))
+			laneEnds = nil
+			clusterStart = i
+		}
+
+		lane := len(laneEnds)
+		for l, end := range laneEnds {
+			if !end.After(block.start) {
+				lane = l
+				break
+			}
+		}
+		if lane == len(laneEnds) {
+			laneEnds = append(laneEnds, time.Time{})
+		}
+		laneEnds[lane] = block.end
+		block.lane = lane
+		if block.end.After(clusterEnd) {
+			clusterEnd = block.end
+		}
+	}
+	setLanes(day.blocks[clusterStart:], len(laneEnds))
+
+	return day
+}
+
+// setLanes records how many lanes a cluster of overlapping blocks uses
+func setLanes(blocks []gridBlock, lanes int) {
+	for i := range blocks {
+		blocks[i].lanes = lanes
+	}
+}
+
+// gridHours returns the hours the grid shows: the working hours, widened
+// to fit any blocks that fall outside them
+func (c *Calendar) gridHours(days []gridDay) (int, int) {
+	first, last := c.options.WorkStart, c.options.WorkEnd
+	for _, day := range days {
+		for _, block := range day.blocks {
+			first = min(first, int(block.start.Sub(day.date)/time.Hour))
+			last = max(last, int((block.end.Sub(day.date)+time.Hour-1)/time.Hour))
+		}
+	}
+	return first, min(last, 24)
+}
+
+// slotTime returns the start of the given half-hour slot of a day
+func slotTime(date time.Time, slot int) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, slot*int(gridSlot/time.Minute), 0, 0, date.Location())
+}
+
+// renderGridCell draws one slot of a day column
+func (c *Calendar) renderGridCell(day gridDay, slotStart time.Time, width int, nowRow bool) string {
+	slotEnd := slotStart.Add(gridSlot)
+
+	// The lanes in this slot are those of the cluster passing through it
+	lanes := 0
+	for _, block := range day.blocks {
+		if block.start.Before(slotEnd) && block.end.After(slotStart) {
+			lanes = block.lanes
+			break
+		}
+	}
+	if lanes == 0 {
+		return c.renderGridGap(width, nowRow)
+	}
+
+	var cell strings.Builder
+	used := 0
+	for lane := 0; lane < lanes; lane++ {
+		laneWidth := width / lanes
+		if lane == lanes-1 {
+			laneWidth = width - used
+		}
+		used += laneWidth
+
+		var block *gridBlock
+		for i := range day.blocks {
+			b := &day.blocks[i]
+			if b.lane == lane && b.start.Before(slotEnd) && b.end.After(slotStart) {
+				block = b
+				break
+			}
+		}
+		if block == nil {
+			cell.WriteString(c.renderGridGap(laneWidth, nowRow))
+			continue
+		}
+
+		// The title goes on the block's first row and its times on the second
+		text := ""
+		switch {
+		case !block.start.Before(slotStart):
+			text = block.todo.Title
+		case !block.start.Add(gridSlot).Before(slotStart):
+			end := block.end
+			if block.todo.ScheduledEnd != nil {
+				end = *block.todo.ScheduledEnd
+			}
+			text = fmt.Sprintf("%s-%s", activeLocale.Clock(*block.todo.ScheduledStart), activeLocale.Clock(end))
+		}
+
+		style := gridBlockStyle
+		if block.todo.Done {
+			style = gridDoneBlockStyle
+		}
+		// Leave a gap between lanes so side by side blocks stay distinct
+		cell.WriteString(style.Render(fitCell(" "+text, laneWidth-1)) + " ")
+	}
+
+	return cell.String()
+}
+
+// renderGridGap draws empty grid space, with the now-line if it passes through
+func (c *Calendar) renderGridGap(width int, nowRow bool) string {
+	if nowRow {
+		return gridNowStyle.Render(strings.Repeat("─", width))
+	}
+	return strings.Repeat(" ", width)
+}
+
+// renderGridRows draws the hour label column and each day's column, one
+// line per slot
+func (c *Calendar) renderGridRows(days []gridDay, width int) string {
+	var s strings.Builder
+
+	first, last := c.gridHours(days)
+	now := c.clock.Now()
+
+	for slot := first * 2; slot < last*2; slot++ {
+		nowRow := false
+		label := ""
+		if slot%2 == 0 {
+			label = activeLocale.Clock(slotTime(days[0].date, slot))
+		}
+
+		var row strings.Builder
+		for _, day := range days {
+			slotStart := slotTime(day.date, slot)
+			dayNow := !now.Before(slotStart) && now.Before(slotStart.Add(gridSlot))
+			nowRow = nowRow || dayNow
+			row.WriteString(c.renderGridCell(day, slotStart, width, dayNow))
+		}
+
+		if nowRow {
+			s.WriteString(gridNowStyle.Render(fmt.Sprintf("%*s ", gridLabelWidth-1, activeLocale.Clock(now))))
+		} else {
+			s.WriteString(gridLabelStyle.Render(fmt.Sprintf("%*s ", gridLabelWidth-1, label)))
+		}
+		s.WriteString("│")
+		s.WriteString(row.String())
+		s.WriteString("\n")
+	}
+
+	return s.String()
+}
+
+// renderDayGrid renders a single day as a time grid
+func (c *Calendar) renderDayGrid() string {
+	var s strings.Builder
+
+	date := startOfDay(c.date)
+	now := c.clock.Now()
+	title := fmt.Sprintf("📅 %s, %s", activeLocale.Weekday(date.Weekday()), activeLocale.DateYear(date))
+	if date.Equal(startOfDay(now)) {
+		title += " (Today)"
+	}
+	s.WriteString(titleStyle.Render(title))
+	s.WriteString("\n\n")
+
+	day := layoutDay(c.todoMap[date.Format("2006-01-02")], date)
+	for _, todo := range day.allDay {
+		text := fmt.Sprintf("%*s %s", gridLabelWidth, activeLocale.AllDay, todo.Title)
+		if todo.Done {
+			text = completedStyle.Render(text)
+		}
+		s.WriteString(text)
+		s.WriteString("\n")
+	}
+	if len(day.allDay) > 0 {
+		s.WriteString("\n")
+	}
+
+	s.WriteString(c.renderGridRows([]gridDay{day}, dayGridWidth))
+	return s.String()
+}
+
+// renderWeekGrid renders the week as a time grid with a column per day
+func (c *Calendar) renderWeekGrid() string {
+	var s strings.Builder
+
+	startOfWeek := c.getStartOfWeek(c.date)
+	endOfWeek := startOfWeek.AddDate(0, 0, 6)
+	title := fmt.Sprintf("📅 Week of %s - %s",
+		activeLocale.Date(startOfWeek),
+		activeLocale.DateYear(endOfWeek))
+	s.WriteString(titleStyle.Render(title))
+	s.WriteString("\n\n")
+
+	today := startOfDay(c.clock.Now())
+	days := make([]gridDay, 7)
+	allDayRows := 0
+
+	s.WriteString(strings.Repeat(" ", gridLabelWidth+1))
+	for i := range days {
+		date := startOfWeek.AddDate(0, 0, i)
+		days[i] = layoutDay(c.todoMap[date.Format("2006-01-02")], date)
+		allDayRows = max(allDayRows, len(days[i].allDay))
+
+		style := lipgloss.NewStyle().Foreground(lipgloss.Color(ColorBlue)).Bold(true)
+		if date.Equal(today) {
+			style = style.Foreground(lipgloss.Color(ColorYellow))
+		}
+		s.WriteString(style.Render(fitCell(activeLocale.ShortWeekday(date.Weekday(), 3)+" "+fmt.Sprint(date.Day()), weekGridDayWidth)))
+	}
+	s.WriteString("\n")
+
+	// All-day blocks sit above the grid, one row per block
+	for row := 0; row < allDayRows; row++ {
+		label := ""
+		if row == 0 {
+			label = activeLocale.AllDay
+		}
+		s.WriteString(gridLabelStyle.Render(fmt.Sprintf("%*s ", gridLabelWidth-1, label)))
+		s.WriteString(" ")
+		for _, day := range days {
+			if row < len(day.allDay) {
+				style := gridBlockStyle
+				if day.allDay[row].Done {
+					style = gridDoneBlockStyle
+				}
+				s.WriteString(style.Render(fitCell(" "+day.allDay[row].Title, weekGridDayWidth-1)) + " ")
+			} else {
+				s.WriteString(strings.Repeat(" ", weekGridDayWidth))
+			}
+		}
+		s.WriteString("\n")
+	}
+
+	s.WriteString(c.renderGridRows(days, weekGridDayWidth))
+	return s.String()
+}
+
+// fitCell truncates or pads text to exactly width columns
+func fitCell(text string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	for lipgloss.Width(text) > width {
+		runes := []rune(text)
+		text = string(runes[:len(runes)-1])
+	}
+	return text + strings.Repeat(" ", width-lipgloss.Width(text))
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCalendarRender(t *testing.T) {
+	store := newTestStore()
+	addScheduled(t, store, "Standup", at(2030, 1, 15, 9, 0), at(2030, 1, 15, 9, 15))
+	addScheduled(t, store, "Review", at(2030, 1, 17, 14, 0), at(2030, 1, 17, 15, 0))
+	addScheduled(t, store, "Retro", at(2030, 2, 1, 16, 0), at(2030, 2, 1, 17, 0))
+	addScheduled(t, store, "Inbox item", nil, nil)
+
+	tests := []struct {
+		name    string
+		view    CalendarView
+		want    []string
+		notWant []string
+	}{
+		{
+			name: "month",
+			view: MonthView,
+			want: []string{
+				"📅 January 2030", "Sun       Mon",
+				"15 •", "17 •",
+				"Scheduled for January 2030:",
+				"Jan 15:\n  • Standup (Jan 15 9:00am-9:15am)",
+				"Jan 17:\n  • Review (Jan 17 2:00pm-3:00pm)",
+			},
+			notWant: []string{"Retro", "Inbox item"},
+		},
+		{
+			name: "week",
+			view: WeekView,
+			want: []string{
+				"📅 Week of Jan 13 - Jan 19, 2030",
+				"Sunday, Jan 13\n  No todos scheduled",
+				"Tuesday, Jan 15\n  • Standup (9:00am-9:15am)",
+				"Thursday, Jan 17\n  • Review (2:00pm-3:00pm)",
+			},
+			notWant: []string{"Retro", "Inbox item"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calendar := NewCalendar(store, NewFixedClock(testNow), DefaultCalendarOptions(), *at(2030, 1, 15, 12, 0), tt.view)
+			if err := calendar.LoadTodos(); err != nil {
+				t.Fatal(err)
+			}
+			output := calendar.Render()
+			assertContains(t, output, tt.want...)
+			for _, unwanted := range tt.notWant {
+				if strings.Contains(output, unwanted) {
+					t.Errorf("output has %q:\n%s", unwanted, output)
+				}
+			}
+		})
+	}
+}
+
+func TestCalendarNavigatesAcrossYears(t *testing.T) {
+	store := newTestStore()
+	addScheduled(t, store, "New year brunch", at(2031, 1, 1, 11, 0), at(2031, 1, 1, 13, 0))
+
+	calendar := NewCalendar(store, NewFixedClock(testNow), DefaultCalendarOptions(), *at(2030, 12, 15, 0, 0), MonthView)
+	calendar.Next()
+	if err := calendar.LoadTodos(); err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, calendar.Render(), "📅 January 2031", " 1 •", "New year brunch (Jan 1 11:00am-1:00pm)")
+
+	calendar.Previous()
+	calendar.Previous()
+	calendar.LoadTodos()
+	if output := calendar.Render(); !strings.Contains(output, "📅 November 2030") || strings.Contains(output, "brunch") {
+		t.Errorf("going back two months:\n%s", output)
+	}
+}
+
+func TestCalendarShowsBlocksOnEveryDay(t *testing.T) {
+	store := newTestStore()
+	store.AddTodo(Todo{Title: "Conference", ScheduledStart: at(2030, 1, 15, 0, 0), ScheduledEnd: at(2030, 1, 18, 0, 0), AllDay: true})
+	addScheduled(t, store, "Night shift", at(2030, 1, 18, 22, 0), at(2030, 1, 19, 6, 0))
+
+	week := NewCalendar(store, NewFixedClock(testNow), DefaultCalendarOptions(), *at(2030, 1, 15, 12, 0), WeekView)
+	if err := week.LoadTodos(); err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, week.Render(),
+		"Tuesday, Jan 15\n  • Conference (all day)",
+		"Wednesday, Jan 16\n  • Conference (all day)",
+		"Thursday, Jan 17\n  • Conference (all day)",
+		"Friday, Jan 18\n  • Night shift (from 10:00pm)",
+		"Saturday, Jan 19\n  • Night shift (until 6:00am)",
+	)
+
+	month := NewCalendar(store, NewFixedClock(testNow), DefaultCalendarOptions(), *at(2030, 1, 15, 12, 0), MonthView)
+	if err := month.LoadTodos(); err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, month.Render(), "15 •", "16 •", "17 •", "Jan 15 - Jan 17 (all day)")
+}
+
+func TestLayoutDaySplitsOverlappingBlocks(t *testing.T) {
+	date := *at(2030, 1, 15, 0, 0)
+	todos := []Todo{
+		{Title: "Standup", ScheduledStart: at(2030, 1, 15, 9, 0), ScheduledEnd: at(2030, 1, 15, 10, 0)},
+		{Title: "Review", ScheduledStart: at(2030, 1, 15, 9, 30), ScheduledEnd: at(2030, 1, 15, 11, 0)},
+		{Title: "Coffee", ScheduledStart: at(2030, 1, 15, 10, 0), ScheduledEnd: at(2030, 1, 15, 10, 30)},
+		{Title: "Lunch", ScheduledStart: at(2030, 1, 15, 12, 0), ScheduledEnd: at(2030, 1, 15, 13, 0)},
+		{Title: "Night shift", ScheduledStart: at(2030, 1, 14, 22, 0), ScheduledEnd: at(2030, 1, 15, 2, 0)},
+		{Title: "Holiday", ScheduledStart: at(2030, 1, 15, 0, 0), ScheduledEnd: at(2030, 1, 16, 0, 0), AllDay: true},
+	}
+
+	day := layoutDay(todos, date)
+	if len(day.allDay) != 1 || day.allDay[0].Title != "Holiday" {
+		t.Errorf("all-day todos %q", titles(day.allDay))
+	}
+
+	want := map[string]struct {
+		start, end  time.Time
+		lane, lanes int
+	}{
+		"Night shift": {start: date, end: *at(2030, 1, 15, 2, 0), lane: 0, lanes: 1},
+		"Standup":     {start: *at(2030, 1, 15, 9, 0), end: *at(2030, 1, 15, 10, 0), lane: 0, lanes: 2},
+		"Review":      {start: *at(2030, 1, 15, 9, 30), end: *at(2030, 1, 15, 11, 0), lane: 1, lanes: 2},
+		"Coffee":      {start: *at(2030, 1, 15, 10, 0), end: *at(2030, 1, 15, 10, 30), lane: 0, lanes: 2},
+		"Lunch":       {start: *at(2030, 1, 15, 12, 0), end: *at(2030, 1, 15, 13, 0), lane: 0, lanes: 1},
+	}
+	if len(day.blocks) != len(want) {
+		t.Fatalf("got %d timed blocks, want %d", len(day.blocks), len(want))
+	}
+	for _, block := range day.blocks {
+		w := want[block.todo.Title]
+		if !block.start.Equal(w.start) || !block.end.Equal(w.end) || block.lane != w.lane || block.lanes != w.lanes {
+			t.Errorf("%s: %v-%v lane %d of %d, want %v-%v lane %d of %d", block.todo.Title,
+				block.start, block.end, block.lane, block.lanes, w.start, w.end, w.lane, w.lanes)
+		}
+	}
+}
+
+func TestCalendarTimeGrid(t *testing.T) {
+	store := newTestStore()
+	addScheduled(t, store, "Early run", at(2030, 1, 15, 6, 0), at(2030, 1, 15, 7, 0))
+	addScheduled(t, store, "Standup", at(2030, 1, 15, 9, 0), at(2030, 1, 15, 10, 0))
+	store.AddTodo(Todo{Title: "Holiday", ScheduledStart: at(2030, 1, 15, 0, 0), ScheduledEnd: at(2030, 1, 16, 0, 0), AllDay: true})
+
+	clock := NewFixedClock(*at(2030, 1, 15, 12, 10))
+	calendar := NewCalendar(store, clock, DefaultCalendarOptions(), *at(2030, 1, 15, 12, 0), DayView)
+	if err := calendar.LoadTodos(); err != nil {
+		t.Fatal(err)
+	}
+	output := calendar.Render()
+	assertContains(t, output,
+		"📅 Tuesday, Jan 15, 2030 (Today)",
+		"all day Holiday",
+		// Working hours widen to fit the early block
+		" 6:00am │ Early run",
+		"        │ 6:00am-7:00am",
+		" 9:00am │ Standup",
+		"12:10pm │────",
+		" 5:00pm │",
+	)
+	if strings.Contains(output, " 5:00am") || strings.Contains(output, " 6:00pm") {
+		t.Errorf("grid runs outside the hours in use:\n%s", output)
+	}
+
+	calendar.SetView(WeekView)
+	calendar.SetGrid(true)
+	calendar.LoadTodos()
+	assertContains(t, calendar.Render(), "📅 Week of Jan 13 - Jan 19, 2030", "Sun 13", "Tue 15", "all day", "Holiday", "Stand")
+}
+
+func TestScheduledOnShadesDays(t *testing.T) {
+	store := newTestStore()
+	addScheduled(t, store, "Standup", at(2027, 3, 2, 9, 0), at(2027, 3, 2, 9, 30))
+	addScheduled(t, store, "Workshop", at(2027, 3, 3, 9, 0), at(2027, 3, 3, 12, 0))
+	addScheduled(t, store, "Deep work", at(2027, 3, 3, 13, 0), at(2027, 3, 3, 17, 30))
+	addScheduled(t, store, "Night shift", at(2027, 3, 4, 22, 0), at(2027, 3, 5, 6, 0))
+	store.AddTodo(Todo{Title: "Offsite", ScheduledStart: at(2027, 3, 8, 0, 0), ScheduledEnd: at(2027, 3, 9, 0, 0), AllDay: true})
+
+	calendar := NewCalendar(store, NewFixedClock(testNow), DefaultCalendarOptions(), *at(2027, 3, 1, 0, 0), YearView)
+	if err := calendar.LoadTodos(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		date      time.Time
+		scheduled time.Duration
+		color     string
+	}{
+		{date: *at(2027, 3, 1, 0, 0), scheduled: 0, color: ""},
+		{date: *at(2027, 3, 2, 0, 0), scheduled: 30 * time.Minute, color: densityColors[0]},
+		{date: *at(2027, 3, 3, 0, 0), scheduled: 7*time.Hour + 30*time.Minute, color: densityColors[3]},
+		{date: *at(2027, 3, 4, 0, 0), scheduled: 2 * time.Hour, color: densityColors[0]},
+		{date: *at(2027, 3, 5, 0, 0), scheduled: 6 * time.Hour, color: densityColors[2]},
+		{date: *at(2027, 3, 8, 0, 0), scheduled: 10 * time.Hour, color: densityColors[3]},
+	}
+	for _, tt := range tests {
+		got := calendar.scheduledOn(tt.date)
+		if got != tt.scheduled {
+			t.Errorf("scheduled on %v: %v, want %v", tt.date, got, tt.scheduled)
+		}
+		if color := densityColor(got); color != tt.color {
+			t.Errorf("shade for %v: %q, want %q", tt.date, color, tt.color)
+		}
+	}
+
+	assertContains(t, calendar.Render(), "📅 2027", "January", "December", "Su Mo Tu We Th Fr Sa", "Scheduled:", "6h+")
+}
+
+func TestMonthWeekNumbers(t *testing.T) {
+	calendar := NewCalendar(newTestStore(), NewFixedClock(testNow), DefaultCalendarOptions(), *at(2027, 1, 15, 0, 0), MonthView)
+	calendar.SetWeekNumbers(true)
+	if err := calendar.LoadTodos(); err != nil {
+		t.Fatal(err)
+	}
+
+	// January 1 2027 is a Friday, so the first row is still ISO week 53
+	// of 2026. Rows starting on Sunday carry the week of the Monday after.
+	assertContains(t, calendar.Render(), "Wk    Sun", "53     27", " 1      3", " 5     31")
+}
+
+func TestCalendarWeekAcrossDST(t *testing.T) {
+	ny := loadLocation(t, "America/New_York")
+	clock := NewFixedClock(time.Date(2026, 3, 6, 10, 0, 0, 0, ny))
+	store := newTestStore()
+	store.loc = ny
+	addTodos(t, store, clock, "Early run @ sun 7am-8am", "Late call @ sat 11pm-12:30am")
+
+	calendar := NewCalendar(store, clock, DefaultCalendarOptions(), time.Date(2026, 3, 8, 0, 0, 0, 0, ny), WeekView)
+	if err := calendar.LoadTodos(); err != nil {
+		t.Fatal(err)
+	}
+	// The overnight block carries into the day clocks go forward, and times
+	// after the change keep their wall clock
+	assertContains(t, calendar.Render(),
+		"Sunday, Mar 8\n  • Late call (until 12:30am)\n  • Early run (7:00am-8:00am)",
+	)
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Shades for the year view, from lightly to heavily scheduled days
+var densityColors = []string{"#1F3B57", "#2B5A86", "#3A78B5", ColorBlue}
+
+// densityLevels are the scheduled hours at which a day moves to the next shade
+var densityLevels = []time.Duration{0, 2 * time.Hour, 4 * time.Hour, 6 * time.Hour}
+
+var weekNumberStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color(ColorGray)).
+	Width(4).
+	Align(lipgloss.Right).
+	PaddingRight(1)
+
+// scheduledOn returns how long todos are scheduled for on date. All-day
+// blocks count as a full working day.
+func (c *Calendar) scheduledOn(date time.Time) time.Duration {
+	next := date.AddDate(0, 0, 1)
+	var total time.Duration
+
+	for _, todo := range c.todoMap[date.Format("2006-01-02")] {
+		switch {
+		case todo.AllDay:
+			total += time.Duration(c.options.WorkEnd-c.options.WorkStart) * time.Hour
+		case todo.ScheduledStart != nil && todo.ScheduledEnd != nil:
+			start, end := *todo.ScheduledStart, *todo.ScheduledEnd
+			if start.Before(date) {
+				start = date
+			}
+			if end.After(next) {
+				end = next
+			}
+			if end.After(start) {
+				total += end.Sub(start)
+			}
+		}
+	}
+
+	return total
+}
+
+// densityColor returns the shade for a day with the given scheduled time,
+// or "" for an empty day
+func densityColor(scheduled time.Duration) string {
+	color := ""
+	for i, level := range densityLevels {
+		if scheduled > level {
+			color = densityColors[i]
+		}
+	}
+	return color
+}
+
+// renderYear renders twelve compact months shaded by scheduled hours
+func (c *Calendar) renderYear() string {
+	var s strings.Builder
+
+	title := fmt.Sprintf("📅 %d", c.date.Year())
+	s.WriteString(titleStyle.Render(title))
+	s.WriteString("\n\n")
+
+	var months []string
+	for month := time.January; month <= time.December; month++ {
+		months = append(months, c.renderCompactMonth(month))
+	}
+
+	// Three months side by side, four rows for the year
+	for row := 0; row < 12; row += 3 {
+		s.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, months[row], "   ", months[row+1], "   ", months[row+2]))
+		s.WriteString("\n\n")
+	}
+
+	// Legend
+	s.WriteString(descStyle.Render("Scheduled: "))
+	labels := []string{"< 2h", "2-4h", "4-6h", "6h+"}
+	for i, label := range labels {
+		swatch := lipgloss.NewStyle().Background(lipgloss.Color(densityColors[i])).Render("  ")
+		s.WriteString(swatch + " " + descStyle.Render(label) + "  ")
+	}
+	s.WriteString("\n")
+
+	return s.String()
+}
+
+// renderCompactMonth renders one month of the year view, always six weeks
+// tall so that months line up side by side
+func (c *Calendar) renderCompactMonth(month time.Month) string {
+	var s strings.Builder
+
+	firstDay := time.Date(c.date.Year(), month, 1, 0, 0, 0, 0, c.date.Location())
+	today := startOfDay(c.clock.Now())
+
+	monthTitle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(ColorYellow)).
+		Bold(true).
+		Width(20).
+		Align(lipgloss.Center)
+	s.WriteString(monthTitle.Render(activeLocale.Month(month)))
+	s.WriteString("\n")
+
+	header := lipgloss.NewStyle().Foreground(lipgloss.Color(ColorBlue))
+	var days []string
+	for _, name := range c.weekdayNames(2) {
+		days = append(days, fitCell(name, 2))
+	}
+	s.WriteString(header.Render(strings.Join(days, " ")))
+	s.WriteString("\n")
+
+	current := c.getStartOfWeek(firstDay)
+	for week := 0; week < 6; week++ {
+		var cells []string
+		for day := 0; day < 7; day++ {
+			cell := "  "
+			if current.Month() == month {
+				style := lipgloss.NewStyle().Foreground(lipgloss.Color(ColorWhite))
+				if color := densityColor(c.scheduledOn(current)); color != "" {
+					style = style.Background(lipgloss.Color(color))
+				}
+				if current.Equal(today) {
+					style = style.Foreground(lipgloss.Color(ColorYellow)).Bold(true)
+				}
+				cell = style.Render(fmt.Sprintf("%2d", current.Day()))
+			}
+			cells = append(cells, cell)
+			current = current.AddDate(0, 0, 1)
+		}
+		s.WriteString(strings.Join(cells, " "))
+		if week < 5 {
+			s.WriteString("\n")
+		}
+	}
+
+	return s.String()
+}
@@ -1,7 +1,11 @@
 package main
 
 import (
+	"bufio"
 	"fmt"
+	"io"
+	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -11,12 +15,16 @@ import (
 
 // CLI handles all command-line interface operations
 type CLI struct {
-	db *DB
+	db     Store
+	config *Config
+	clock  Clock
+	out    io.Writer // Where command output is written
+	in     io.Reader // Where confirmations are read from
 }
 
-// NewCLI creates a new CLI instance
-func NewCLI(db *DB) *CLI {
-	return &CLI{db: db}
+// NewCLI creates a new CLI instance that writes to stdout
+func NewCLI(db Store, config *Config, clock Clock) *CLI {
+	return &CLI{db: db, config: config, clock: clock, out: os.Stdout, in: os.Stdin}
 }
 
 // HandleCommand processes the given command and arguments
@@ -25,65 +33,176 @@ func (c *CLI) HandleCommand(command string, args []string) {
 	case "add", "a":
 		c.handleAdd(args)
 	case "list", "ls", "l":
-		c.handleList()
+		c.handleList(args)
+	case "filter", "f":
+		c.handleFilter(args)
 	case "inbox", "i":
 		c.handleInbox()
+	case "overdue", "od":
+		c.handleOverdue()
+	case "upcoming", "up":
+		c.handleUpcoming(args)
+	case "someday":
+		c.handleSomeday()
 	case "today", "tod":
 		c.handleToday()
 	case "day", "date":
 		c.handleDate(args)
 	case "calendar", "cal":
 		c.handleCalendar(args)
+	case "agenda", "ag":
+		c.handleAgenda(args)
 	case "toggle", "t":
 		c.handleToggle(args)
+	case "done":
+		c.handleDone(args)
+	case "tag":
+		c.handleTag(args)
 	case "delete", "del", "d":
 		c.handleDelete(args)
 	case "edit", "e":
 		c.handleEdit(args)
 	case "schedule", "s":
 		c.handleSchedule(args)
+	case "remind", "r":
+		c.handleRemind(args)
+	case "move", "mv":
+		c.handleMove(args)
+	case "postpone":
+		c.handlePostpone(args)
+	case "unschedule":
+		c.handleUnschedule(args)
+	case "overlaps":
+		c.handleOverlaps(args)
+	case "free":
+		c.handleFree(args)
+	case "plan":
+		c.handlePlan(args)
+	case "stats":
+		c.handleStats(args)
+	case "start":
+		c.handleStart(args)
+	case "stop":
+		c.handleStop()
+	case "status":
+		c.handleStatus()
+	case "focus":
+		c.handleFocus(args)
+	case "timesheet":
+		c.handleTimesheet(args)
+	case "sync":
+		c.handleSync()
+	case "conflicts":
+		c.handleConflicts(args)
 	case "ui":
 		c.handleUI()
 	case "help", "h":
 		c.printUsage()
 	default:
-		fmt.Printf("Unknown command: %s\n\n", command)
+		fmt.Fprintf(c.out, "Unknown command: %s\n\n", command)
 		c.printUsage()
 	}
 }
 
 func (c *CLI) handleAdd(args []string) {
 	if len(args) == 0 {
-		fmt.Println(errorStyle.Render("Error: Title is required"))
-		fmt.Println(styleCommand("Usage: li add <title> [description]"))
+		fmt.Fprintln(c.out, errorStyle.Render("Error: Title is required"))
+		fmt.Fprintln(c.out, styleCommand("Usage: li add <title> [description]"))
+		fmt.Fprintln(c.out, "Titles can set everything inline:")
+		fmt.Fprintln(c.out, "  "+styleCommand("li add \"Call dentist #health +personal !high due:fri @ tomorrow 2pm-2:30pm\""))
+		return
+	}
+
+	parsed, err := ParseSmartAdd(args[0], c.clock)
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error parsing title: %v", err)))
+		return
+	}
+	if parsed.Title == "" {
+		fmt.Fprintln(c.out, errorStyle.Render("Error: Title is required"))
 		return
 	}
 
-	title := args[0]
 	description := ""
 	if len(args) > 1 {
 		description = strings.Join(args[1:], " ")
 	}
 
-	err := c.db.AddTodo(title, description, nil, nil, nil)
+	todo := parsed.Todo(description)
+	err = c.db.AddTodo(todo)
 	if err != nil {
-		fmt.Println(errorStyle.Render(fmt.Sprintf("Error adding todo: %v", err)))
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error adding todo: %v", err)))
 		return
 	}
 
-	fmt.Println(successStyle.Render(fmt.Sprintf("✅ Added todo: %s", title)))
+	fmt.Fprintln(c.out, successStyle.Render(fmt.Sprintf("✅ Added todo: %s", todo.Title)))
+	if labels := FormatLabels(todo); labels != "" {
+		fmt.Fprintln(c.out, "  "+descStyle.Render(labels))
+	}
+	if todo.DueDate != nil {
+		fmt.Fprintln(c.out, "  "+descStyle.Render("Due: "+FormatDueDate(todo.DueDate, c.clock)))
+	}
+	if todo.ScheduledStart != nil {
+		fmt.Fprintln(c.out, "  "+descStyle.Render(strings.TrimPrefix(FormatTimeBlock(todo.ScheduledStart, todo.ScheduledEnd, todo.AllDay), "Scheduled: ")))
+	}
 }
 
 func (c *CLI) handleInbox() {
 	todos, err := c.db.GetInboxTodos()
 	if err != nil {
-		fmt.Println(errorStyle.Render(fmt.Sprintf("Error listing inbox: %v", err)))
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error listing inbox: %v", err)))
 		return
 	}
 
 	c.renderTodoList(todos, "📥 Inbox (Unscheduled):", "No unscheduled todos! Everything is planned. ✅")
 }
 
+func (c *CLI) handleOverdue() {
+	todos, err := c.db.GetOverdueTodos(c.clock.Now())
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error listing overdue todos: %v", err)))
+		return
+	}
+
+	c.renderTodoList(todos, "⏰ Overdue:", "Nothing has slipped. ✅")
+	if len(todos) > 0 {
+		fmt.Fprintln(c.out)
+		fmt.Fprintln(c.out, descStyle.Render("Move them all with: ")+styleCommand("li postpone --overdue tomorrow"))
+	}
+}
+
+func (c *CLI) handleUpcoming(args []string) {
+	days := upcomingDays
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 {
+			fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error: Invalid number of days '%s'", args[0])))
+			fmt.Fprintln(c.out, styleCommand("Usage: li upcoming [days]"))
+			return
+		}
+		days = n
+	}
+
+	now := c.clock.Now()
+	todos, err := c.db.GetUpcomingTodos(now, startOfDay(now).AddDate(0, 0, days))
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error listing upcoming todos: %v", err)))
+		return
+	}
+
+	c.renderTodoList(todos, fmt.Sprintf("🔭 Upcoming (next %d days):", days), "Nothing coming up. ✅")
+}
+
+func (c *CLI) handleSomeday() {
+	todos, err := c.db.GetSomedayTodos()
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error listing someday todos: %v", err)))
+		return
+	}
+
+	c.renderTodoList(todos, "💤 Someday (no date or block):", "Every open todo has a date or a block. ✅")
+}
+
 func (c *CLI) handleToday() {
 	c.handleDate([]string{"today"})
 }
@@ -92,42 +211,42 @@ func (c *CLI) handleDate(args []string) {
 	var targetDate time.Time
 	var title string
 	var emptyMessage string
-	
+
 	if len(args) == 0 || args[0] == "today" {
-		targetDate = time.Now()
+		targetDate = c.clock.Now()
 		title = "📅 Today's Schedule:"
 		emptyMessage = "Nothing scheduled for today. Use " + styleCommand("li schedule <id> \"<time>\"") + " to plan your day."
 	} else {
 		// Parse the date argument
 		dateStr := strings.Join(args, " ")
-		
+
 		// Handle relative dates
 		switch strings.ToLower(dateStr) {
 		case "tomorrow":
-			targetDate = time.Now().AddDate(0, 0, 1)
+			targetDate = c.clock.Now().AddDate(0, 0, 1)
 			title = "📅 Tomorrow's Schedule:"
 			emptyMessage = "Nothing scheduled for tomorrow. Use " + styleCommand("li schedule <id> \"<time>\"") + " to plan ahead."
 		case "yesterday":
-			targetDate = time.Now().AddDate(0, 0, -1)
+			targetDate = c.clock.Now().AddDate(0, 0, -1)
 			title = "📅 Yesterday's Schedule:"
 			emptyMessage = "Nothing was scheduled for yesterday."
 		default:
 			// Try to parse as a regular date
-			parsedDate, err := parseScheduleDate(dateStr)
+			parsedDate, err := parseScheduleDate(dateStr, c.clock)
 			if err != nil {
-				fmt.Println(errorStyle.Render(fmt.Sprintf("Error parsing date: %v", err)))
-				fmt.Println("Examples: today, tomorrow, yesterday, Monday, Dec 25, 2024-12-25")
+				fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error parsing date: %v", err)))
+				fmt.Fprintln(c.out, "Examples: today, tomorrow, yesterday, Monday, next friday, end of month, Dec 25, 2024-12-25")
 				return
 			}
 			targetDate = *parsedDate
-			title = fmt.Sprintf("📅 Schedule for %s:", targetDate.Format("Jan 2, 2006"))
-			emptyMessage = fmt.Sprintf("Nothing scheduled for %s.", targetDate.Format("Jan 2, 2006"))
+			title = fmt.Sprintf("📅 Schedule for %s:", activeLocale.DateYear(targetDate))
+			emptyMessage = fmt.Sprintf("Nothing scheduled for %s.", activeLocale.DateYear(targetDate))
 		}
 	}
-	
+
 	todos, err := c.db.GetDateTodos(targetDate)
 	if err != nil {
-		fmt.Println(errorStyle.Render(fmt.Sprintf("Error listing todos for %s: %v", targetDate.Format("Jan 2"), err)))
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error listing todos for %s: %v", activeLocale.Date(targetDate), err)))
 		return
 	}
 
@@ -135,32 +254,8 @@ func (c *CLI) handleDate(args []string) {
 }
 
 // parseScheduleDate parses various date formats for schedule queries
-func parseScheduleDate(dateStr string) (*time.Time, error) {
-	now := time.Now()
-	dateStr = strings.TrimSpace(strings.ToLower(dateStr))
-	
-	// Handle weekdays
-	weekdays := map[string]time.Weekday{
-		"sunday": time.Sunday, "sun": time.Sunday,
-		"monday": time.Monday, "mon": time.Monday,
-		"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
-		"wednesday": time.Wednesday, "wed": time.Wednesday,
-		"thursday": time.Thursday, "thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday,
-		"friday": time.Friday, "fri": time.Friday,
-		"saturday": time.Saturday, "sat": time.Saturday,
-	}
-	
-	if weekday, exists := weekdays[dateStr]; exists {
-		daysUntil := (int(weekday) - int(now.Weekday()) + 7) % 7
-		if daysUntil == 0 {
-			daysUntil = 7 // Next week if it's the same day
-		}
-		date := now.AddDate(0, 0, daysUntil)
-		return &date, nil
-	}
-	
-	// Try standard date parsing from ParseDueDate
-	return ParseDueDate(dateStr)
+func parseScheduleDate(dateStr string, clock Clock) (*time.Time, error) {
+	return ParseDueDate(strings.TrimSpace(dateStr), clock)
 }
 
 func (c *CLI) handleCalendar(args []string) {
@@ -168,9 +263,24 @@ func (c *CLI) handleCalendar(args []string) {
 	var view CalendarView
 
 	// Default to current month
-	targetDate = time.Now()
+	targetDate = c.clock.Now()
 	view = MonthView
 
+	// --grid draws the week as a time grid; --weeks adds ISO week numbers
+	grid, weekNumbers := false, false
+	var rest []string
+	for _, arg := range args {
+		switch arg {
+		case "--grid", "-g":
+			grid = true
+		case "--weeks":
+			weekNumbers = true
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	args = rest
+
 	// Parse arguments
 	if len(args) > 0 {
 		switch strings.ToLower(args[0]) {
@@ -178,7 +288,25 @@ func (c *CLI) handleCalendar(args []string) {
 			view = WeekView
 			if len(args) > 1 {
 				// Parse date for week
-				if parsedDate, err := parseScheduleDate(strings.Join(args[1:], " ")); err == nil {
+				if parsedDate, err := parseScheduleDate(strings.Join(args[1:], " "), c.clock); err == nil {
+					targetDate = *parsedDate
+				}
+			}
+		case "year", "y":
+			view = YearView
+			if len(args) > 1 {
+				// A bare year, or any date within the year
+				if year, err := strconv.Atoi(args[1]); err == nil && len(args) == 2 {
+					targetDate = time.Date(year, time.January, 1, 0, 0, 0, 0, targetDate.Location())
+				} else if parsedDate, err := parseScheduleDate(strings.Join(args[1:], " "), c.clock); err == nil {
+					targetDate = *parsedDate
+				}
+			}
+		case "day", "d":
+			view = DayView
+			if len(args) > 1 {
+				// Parse date for day
+				if parsedDate, err := parseScheduleDate(strings.Join(args[1:], " "), c.clock); err == nil {
 					targetDate = *parsedDate
 				}
 			}
@@ -186,53 +314,98 @@ func (c *CLI) handleCalendar(args []string) {
 			view = MonthView
 			if len(args) > 1 {
 				// Parse date for month
-				if parsedDate, err := parseScheduleDate(strings.Join(args[1:], " ")); err == nil {
+				if parsedDate, err := parseScheduleDate(strings.Join(args[1:], " "), c.clock); err == nil {
 					targetDate = *parsedDate
 				}
 			}
 		default:
 			// Assume it's a date for month view
-			if parsedDate, err := parseScheduleDate(strings.Join(args, " ")); err == nil {
+			if parsedDate, err := parseScheduleDate(strings.Join(args, " "), c.clock); err == nil {
 				targetDate = *parsedDate
 				view = MonthView
 			} else {
-				fmt.Println(errorStyle.Render(fmt.Sprintf("Error parsing date: %v", err)))
-				fmt.Println("Usage:")
-				fmt.Println("  " + styleCommand("li calendar") + " - Show current month")
-				fmt.Println("  " + styleCommand("li calendar week") + " - Show current week")
-				fmt.Println("  " + styleCommand("li calendar month Dec") + " - Show December")
-				fmt.Println("  " + styleCommand("li calendar week Monday") + " - Show week containing Monday")
+				fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error parsing date: %v", err)))
+				fmt.Fprintln(c.out, "Usage:")
+				fmt.Fprintln(c.out, "  "+styleCommand("li calendar")+" - Show current month")
+				fmt.Fprintln(c.out, "  "+styleCommand("li calendar week")+" - Show current week")
+				fmt.Fprintln(c.out, "  "+styleCommand("li calendar month Dec")+" - Show December")
+				fmt.Fprintln(c.out, "  "+styleCommand("li calendar week Monday")+" - Show week containing Monday")
+				fmt.Fprintln(c.out, "  "+styleCommand("li calendar week --grid")+" - Show current week as a time grid")
+				fmt.Fprintln(c.out, "  "+styleCommand("li calendar day tomorrow")+" - Show tomorrow's time grid")
+				fmt.Fprintln(c.out, "  "+styleCommand("li calendar year 2027")+" - Show 2027 shaded by scheduled hours")
+				fmt.Fprintln(c.out, "  "+styleCommand("li calendar --weeks")+" - Show the month with ISO week numbers")
 				return
 			}
 		}
 	}
 
+	options, err := c.config.CalendarOptions()
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error reading config: %v", err)))
+		return
+	}
+
 	// Create and load calendar
-	calendar := NewCalendar(c.db, targetDate, view)
-	err := calendar.LoadTodos()
+	calendar := NewCalendar(c.db, c.clock, options, targetDate, view)
+	calendar.SetGrid(grid)
+	if weekNumbers {
+		calendar.SetWeekNumbers(true)
+	}
+	err = calendar.LoadTodos()
 	if err != nil {
-		fmt.Println(errorStyle.Render(fmt.Sprintf("Error loading calendar: %v", err)))
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error loading calendar: %v", err)))
 		return
 	}
 
 	// Render calendar
-	fmt.Print(calendar.Render())
+	fmt.Fprint(c.out, calendar.Render())
+}
+
+func (c *CLI) handleAgenda(args []string) {
+	days := defaultAgendaDays
+	if len(args) > 0 {
+		parsed, err := strconv.Atoi(args[0])
+		if err != nil || parsed < 1 {
+			fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error: Invalid number of days '%s'", args[0])))
+			fmt.Fprintln(c.out, styleCommand("Usage: li agenda [days]"))
+			return
+		}
+		days = parsed
+	}
+
+	agenda, err := BuildAgenda(c.db, c.clock, days)
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error loading agenda: %v", err)))
+		return
+	}
+
+	fmt.Fprint(c.out, agenda.Render(c.clock))
 }
 
-func (c *CLI) handleList() {
+func (c *CLI) handleList(args []string) {
+	if len(args) > 0 {
+		if (args[0] != "--filter" && args[0] != "-f") || len(args) < 2 {
+			fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error: Unknown option '%s'", args[0])))
+			fmt.Fprintln(c.out, styleCommand("Usage: li list [--filter \"<query>\"]"))
+			return
+		}
+		c.runFilter(strings.Join(args[1:], " "), "🔎 Matching todos:")
+		return
+	}
+
 	todos, err := c.db.GetAllTodos()
 	if err != nil {
-		fmt.Println(errorStyle.Render(fmt.Sprintf("Error listing todos: %v", err)))
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error listing todos: %v", err)))
 		return
 	}
 
 	if len(todos) == 0 {
-		fmt.Println(descStyle.Render("No todos found. Add one with: ") + styleCommand("li add <title>"))
+		fmt.Fprintln(c.out, descStyle.Render("No todos found. Add one with: ")+styleCommand("li add <title>"))
 		return
 	}
 
-	fmt.Println(titleStyle.Render("⚡ Your Todos:"))
-	fmt.Println()
+	fmt.Fprintln(c.out, titleStyle.Render("⚡ Your Todos:"))
+	fmt.Fprintln(c.out)
 
 	for _, todo := range todos {
 		status, statusColor := createStatusStyle(todo.Done)
@@ -257,70 +430,336 @@ func (c *CLI) handleList() {
 			line += fmt.Sprintf(" - %s", descText)
 		}
 
+		if labels := FormatLabels(todo); labels != "" {
+			line += " " + descStyle.Render(labels)
+		}
+
 		// Add time block info if scheduled
-		if timeBlock := FormatTimeBlock(todo.ScheduledStart, todo.ScheduledEnd); timeBlock != "" {
+		if timeBlock := FormatTimeBlock(todo.ScheduledStart, todo.ScheduledEnd, todo.AllDay); timeBlock != "" {
 			timeBlockStyled := lipgloss.NewStyle().Foreground(lipgloss.Color(ColorBlue)).Render(fmt.Sprintf(" [%s]", timeBlock))
 			line += timeBlockStyled
 		}
 
-		fmt.Println(todoStyle.Render(line))
+		fmt.Fprintln(c.out, todoStyle.Render(line))
 	}
 }
 
-func (c *CLI) handleToggle(args []string) {
+func (c *CLI) handleFilter(args []string) {
 	if len(args) == 0 {
-		fmt.Println(errorStyle.Render("Error: Todo ID is required"))
-		fmt.Println(styleCommand("Usage: li toggle <id>"))
-		return
+		args = []string{"list"}
 	}
 
-	id, err := strconv.Atoi(args[0])
+	switch args[0] {
+	case "save":
+		if len(args) < 3 {
+			fmt.Fprintln(c.out, errorStyle.Render("Error: Name and query are required"))
+			fmt.Fprintln(c.out, styleCommand("Usage: li filter save <name> \"<query>\""))
+			return
+		}
+		name, query := args[1], strings.Join(args[2:], " ")
+		if !filterNamePattern.MatchString(name) || isFilterCommand(name) {
+			fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error: Invalid filter name '%s'. Use letters, digits, - and _.", name)))
+			return
+		}
+		if _, err := CompileFilter(query, c.clock.Now()); err != nil {
+			fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error: %v", err)))
+			return
+		}
+		if err := c.db.SaveFilter(name, query); err != nil {
+			fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error saving filter: %v", err)))
+			return
+		}
+		fmt.Fprintln(c.out, successStyle.Render(fmt.Sprintf("✅ Saved filter %s", name)))
+		fmt.Fprintln(c.out, "  "+descStyle.Render("Run it with: ")+styleCommand("li filter "+name))
+
+	case "list", "ls":
+		filters, err := c.db.GetSavedFilters()
+		if err != nil {
+			fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error listing filters: %v", err)))
+			return
+		}
+		if len(filters) == 0 {
+			fmt.Fprintln(c.out, descStyle.Render("No saved filters. Save one with: ")+styleCommand("li filter save <name> \"<query>\""))
+			return
+		}
+		fmt.Fprintln(c.out, titleStyle.Render("🔎 Saved filters:"))
+		fmt.Fprintln(c.out)
+		for _, filter := range filters {
+			fmt.Fprintf(c.out, "  %s  %s\n", idStyle.Render(filter.Name), descStyle.Render(filter.Query))
+		}
+
+	case "delete", "del", "rm":
+		if len(args) < 2 {
+			fmt.Fprintln(c.out, errorStyle.Render("Error: Filter name is required"))
+			fmt.Fprintln(c.out, styleCommand("Usage: li filter delete <name>"))
+			return
+		}
+		if err := c.db.DeleteFilter(args[1]); err != nil {
+			fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error deleting filter: %v", err)))
+			return
+		}
+		fmt.Fprintln(c.out, successStyle.Render(fmt.Sprintf("🗑️  Deleted filter %s", args[1])))
+
+	default:
+		filters, err := c.db.GetSavedFilters()
+		if err != nil {
+			fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error listing filters: %v", err)))
+			return
+		}
+		for _, filter := range filters {
+			if filter.Name == args[0] {
+				c.runFilter(filter.Query, fmt.Sprintf("🔎 %s:", filter.Name))
+				return
+			}
+		}
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error: No filter named '%s'", args[0])))
+		fmt.Fprintln(c.out, descStyle.Render("See saved filters with: ")+styleCommand("li filter list"))
+	}
+}
+
+// isFilterCommand reports whether name is a li filter subcommand, which a
+// saved filter can't be called
+func isFilterCommand(name string) bool {
+	switch name {
+	case "save", "list", "ls", "delete", "del", "rm":
+		return true
+	}
+	return false
+}
+
+// runFilter compiles query and lists the todos that match it
+func (c *CLI) runFilter(query, title string) {
+	filter, err := CompileFilter(query, c.clock.Now())
 	if err != nil {
-		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: Invalid ID '%s'. Must be a number.", args[0])))
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error: %v", err)))
 		return
 	}
 
-	err = c.db.ToggleTodo(id)
+	todos, err := c.db.FilterTodos(filter)
 	if err != nil {
-		fmt.Println(errorStyle.Render(fmt.Sprintf("Error toggling todo: %v", err)))
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error filtering todos: %v", err)))
+		return
+	}
+
+	c.renderTodoList(todos, title, fmt.Sprintf("No todos match %q.", query))
+}
+
+func (c *CLI) handleToggle(args []string) {
+	yes, args := cutFlag(args, "--yes", "-y")
+	todos, ok := c.bulkTargetsOnly(args, "li toggle <ids>|--filter \"<query>\" [--yes]")
+	if !ok {
+		return
+	}
+
+	edit := BulkEdit{Toggle: true}
+	if !c.applyBulk(todos, edit, "Toggle", yes) {
+		return
+	}
+
+	if len(todos) == 1 {
+		fmt.Fprintln(c.out, successStyle.Render(fmt.Sprintf("✅ Toggled todo %d", todos[0].ID)))
+		return
+	}
+	c.printBulkSummary(fmt.Sprintf("✅ Toggled %d todos:", len(todos)), todos, edit)
+}
+
+func (c *CLI) handleDone(args []string) {
+	yes, args := cutFlag(args, "--yes", "-y")
+	todos, ok := c.bulkTargetsOnly(args, "li done <ids>|--filter \"<query>\" [--yes]")
+	if !ok {
+		return
+	}
+
+	done := true
+	edit := BulkEdit{Done: &done}
+	if !c.applyBulk(todos, edit, "Mark done", yes) {
 		return
 	}
 
-	fmt.Println(successStyle.Render(fmt.Sprintf("✅ Toggled todo %d", id)))
+	if len(todos) == 1 {
+		fmt.Fprintln(c.out, successStyle.Render(fmt.Sprintf("✅ Marked todo %d done", todos[0].ID)))
+		return
+	}
+	c.printBulkSummary(fmt.Sprintf("✅ Marked %d todos done:", len(todos)), todos, edit)
 }
 
 func (c *CLI) handleDelete(args []string) {
-	if len(args) == 0 {
-		fmt.Println(errorStyle.Render("Error: Todo ID is required"))
-		fmt.Println(styleCommand("Usage: li delete <id>"))
+	yes, args := cutFlag(args, "--yes", "-y")
+	todos, ok := c.bulkTargetsOnly(args, "li delete <ids>|--filter \"<query>\" [--yes]")
+	if !ok {
 		return
 	}
 
-	id, err := strconv.Atoi(args[0])
-	if err != nil {
-		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: Invalid ID '%s'. Must be a number.", args[0])))
+	edit := BulkEdit{Delete: true}
+	if !c.applyBulk(todos, edit, "Delete", yes) {
+		return
+	}
+
+	if len(todos) == 1 {
+		fmt.Fprintln(c.out, successStyle.Render(fmt.Sprintf("🗑️  Deleted todo %d", todos[0].ID)))
+		return
+	}
+	c.printBulkSummary(fmt.Sprintf("🗑️  Deleted %d todos:", len(todos)), todos, edit)
+}
+
+func (c *CLI) handleTag(args []string) {
+	yes, args := cutFlag(args, "--yes", "-y")
+	usage := "li tag <ids>|--filter \"<query>\" +tag|-tag... [--yes]"
+	todos, rest, ok := c.bulkTargets(args, usage)
+	if !ok {
 		return
 	}
 
-	err = c.db.DeleteTodo(id)
+	// +name and #name add a tag, -name removes it
+	var edit BulkEdit
+	var err error
+	edit.AddTags, edit.RemoveTags, err = parseTagChanges(rest)
 	if err != nil {
-		fmt.Println(errorStyle.Render(fmt.Sprintf("Error deleting todo: %v", err)))
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		fmt.Fprintln(c.out, styleCommand("Usage: "+usage))
+		return
+	}
+	if len(rest) == 0 {
+		fmt.Fprintln(c.out, errorStyle.Render("Error: Tags to add or remove are required"))
+		fmt.Fprintln(c.out, styleCommand("Usage: "+usage))
 		return
 	}
 
-	fmt.Println(successStyle.Render(fmt.Sprintf("🗑️  Deleted todo %d", id)))
+	if !c.applyBulk(todos, edit, "Retag", yes) {
+		return
+	}
+
+	change := strings.Join(rest, " ")
+	if len(todos) == 1 {
+		fmt.Fprintln(c.out, successStyle.Render(fmt.Sprintf("🏷️  Tagged todo %d %s", todos[0].ID, change)))
+		return
+	}
+	c.printBulkSummary(fmt.Sprintf("🏷️  Tagged %d todos %s:", len(todos), change), todos, edit)
+}
+
+// bulkTargets resolves the todos a bulk command acts on, given as IDs like
+// "3 5 7-12" or "4,9" or as --filter "<query>". It returns them along with
+// the args that follow, and reports false once it has printed an error.
+func (c *CLI) bulkTargets(args []string, usage string) ([]Todo, []string, bool) {
+	if len(args) == 0 {
+		fmt.Fprintln(c.out, errorStyle.Render("Error: Todo ID is required"))
+		fmt.Fprintln(c.out, styleCommand("Usage: "+usage))
+		return nil, nil, false
+	}
+
+	if args[0] == "--filter" {
+		if len(args) < 2 {
+			fmt.Fprintln(c.out, errorStyle.Render("Error: --filter needs a query"))
+			fmt.Fprintln(c.out, styleCommand("Usage: "+usage))
+			return nil, nil, false
+		}
+		filter, err := CompileFilter(args[1], c.clock.Now())
+		if err != nil {
+			fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error: %v", err)))
+			return nil, nil, false
+		}
+		todos, err := c.db.FilterTodos(filter)
+		if err != nil {
+			fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error filtering todos: %v", err)))
+			return nil, nil, false
+		}
+		if len(todos) == 0 {
+			fmt.Fprintln(c.out, descStyle.Render(fmt.Sprintf("No todos match %q.", args[1])))
+			return nil, nil, false
+		}
+		return todos, args[2:], true
+	}
+
+	ids, rest, err := parseIDList(args)
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		return nil, nil, false
+	}
+	if len(ids) == 0 {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error: Invalid ID '%s'. Must be a number, list (4,9) or range (7-12).", args[0])))
+		return nil, nil, false
+	}
+
+	// Ranges often span deleted todos, so skip the gaps
+	var todos []Todo
+	var missing []string
+	for _, id := range ids {
+		todo, err := c.db.GetTodo(id)
+		if err != nil {
+			missing = append(missing, strconv.Itoa(id))
+			continue
+		}
+		todos = append(todos, *todo)
+	}
+	if len(todos) == 0 {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error: No todo with ID %s", strings.Join(missing, ", "))))
+		return nil, nil, false
+	}
+	if len(missing) > 0 {
+		fmt.Fprintln(c.out, descStyle.Render(fmt.Sprintf("Skipping missing todos: %s", strings.Join(missing, ", "))))
+	}
+
+	return todos, rest, true
+}
+
+// bulkTargetsOnly is bulkTargets for commands that take nothing else
+func (c *CLI) bulkTargetsOnly(args []string, usage string) ([]Todo, bool) {
+	todos, rest, ok := c.bulkTargets(args, usage)
+	if ok && len(rest) > 0 {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error: Invalid ID '%s'. Must be a number, list (4,9) or range (7-12).", rest[0])))
+		return nil, false
+	}
+	return todos, ok
+}
+
+// applyBulk makes edit to todos in one go, asking first when there are more
+// than bulkConfirmThreshold of them. It reports whether anything changed.
+func (c *CLI) applyBulk(todos []Todo, edit BulkEdit, verb string, yes bool) bool {
+	if len(todos) > bulkConfirmThreshold && !yes && !c.confirm(fmt.Sprintf("%s %d todos? [y/N] ", verb, len(todos))) {
+		fmt.Fprintln(c.out, descStyle.Render("Nothing changed."))
+		return false
+	}
+
+	ids := make([]int, len(todos))
+	for i, todo := range todos {
+		ids[i] = todo.ID
+	}
+	if err := c.db.ApplyBulkEdit(ids, edit); err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		fmt.Fprintln(c.out, descStyle.Render("Nothing changed."))
+		return false
+	}
+	return true
+}
+
+// printBulkSummary lists the todos a bulk command touched, noting the ones
+// it left as they were
+func (c *CLI) printBulkSummary(title string, todos []Todo, edit BulkEdit) {
+	fmt.Fprintln(c.out, successStyle.Render(title))
+	unchanged := 0
+	for _, todo := range todos {
+		line := fmt.Sprintf("  %s %s", idStyle.Render(fmt.Sprintf("[%d]", todo.ID)), todo.Title)
+		if !edit.changes(todo) {
+			line += descStyle.Render(" (unchanged)")
+			unchanged++
+		}
+		fmt.Fprintln(c.out, line)
+	}
+	if unchanged > 0 {
+		fmt.Fprintln(c.out, descStyle.Render(fmt.Sprintf("%d of %d already matched.", unchanged, len(todos))))
+	}
 }
 
 func (c *CLI) handleEdit(args []string) {
 	if len(args) < 2 {
-		fmt.Println(errorStyle.Render("Error: Todo ID and title are required"))
-		fmt.Println(styleCommand("Usage: li edit <id> <title> [description]"))
+		fmt.Fprintln(c.out, errorStyle.Render("Error: Todo ID and title are required"))
+		fmt.Fprintln(c.out, styleCommand("Usage: li edit <id> <title> [description]"))
 		return
 	}
 
 	id, err := strconv.Atoi(args[0])
 	if err != nil {
-		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: Invalid ID '%s'. Must be a number.", args[0])))
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error: Invalid ID '%s'. Must be a number.", args[0])))
 		return
 	}
 
@@ -330,135 +769,1065 @@ func (c *CLI) handleEdit(args []string) {
 		description = strings.Join(args[2:], " ")
 	}
 
-	err = c.db.UpdateTodo(id, title, description, nil, nil, nil)
+	// Keep the dates, block and estimate, which can't be given here
+	existing, err := c.db.GetTodo(id)
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error updating todo: %v", err)))
+		return
+	}
+
+	err = c.db.UpdateTodo(id, title, description, existing.DueDate, existing.ScheduledStart, existing.ScheduledEnd, existing.AllDay, existing.Estimate)
 	if err != nil {
-		fmt.Println(errorStyle.Render(fmt.Sprintf("Error updating todo: %v", err)))
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error updating todo: %v", err)))
 		return
 	}
 
-	fmt.Println(successStyle.Render(fmt.Sprintf("✏️  Updated todo %d: %s", id, title)))
+	fmt.Fprintln(c.out, successStyle.Render(fmt.Sprintf("✏️  Updated todo %d: %s", id, title)))
 }
 
 func (c *CLI) handleSchedule(args []string) {
+	// --force schedules the block even if it overlaps other blocks
+	force, args := cutFlag(args, "--force", "-f")
+	yes, args := cutFlag(args, "--yes", "-y")
+
 	if len(args) < 2 {
-		fmt.Println(errorStyle.Render("Error: Todo ID and time block are required"))
-		fmt.Println(styleCommand("Usage: li schedule <id> \"<time block>\""))
-		fmt.Println("Examples:")
-		fmt.Println("  " + styleCommand("li schedule 1 \"Monday 2pm-4pm\""))
-		fmt.Println("  " + styleCommand("li schedule 1 \"tomorrow 9am for 2 hours\""))
-		fmt.Println("  " + styleCommand("li schedule 1 \"Dec 25 10am-12pm\""))
+		fmt.Fprintln(c.out, errorStyle.Render("Error: Todo ID and time block are required"))
+		fmt.Fprintln(c.out, styleCommand("Usage: li schedule <ids> \"<time block>\" [--force]"))
+		fmt.Fprintln(c.out, "Examples:")
+		fmt.Fprintln(c.out, "  "+styleCommand("li schedule 1 \"Monday 2pm-4pm\""))
+		fmt.Fprintln(c.out, "  "+styleCommand("li schedule 1 \"tomorrow 9am for 2 hours\""))
+		fmt.Fprintln(c.out, "  "+styleCommand("li schedule 1 \"Dec 25 10am-12pm\""))
+		fmt.Fprintln(c.out, "  "+styleCommand("li schedule 1 \"fri 2-4pm\""))
+		fmt.Fprintln(c.out, "  "+styleCommand("li schedule 1 \"next monday noon for 1h30m\""))
+		fmt.Fprintln(c.out, "  "+styleCommand("li schedule 1 \"friday all day\""))
+		fmt.Fprintln(c.out, "  "+styleCommand("li schedule 1 \"dec 24-26\""))
+		fmt.Fprintln(c.out, "  "+styleCommand("li schedule 1 next-free 1h"))
+		fmt.Fprintln(c.out, "  "+styleCommand("li schedule 3,5,7-9 \"friday all day\""))
 		return
 	}
 
-	id, err := strconv.Atoi(args[0])
-	if err != nil {
-		fmt.Println(errorStyle.Render(fmt.Sprintf("Error: Invalid ID '%s'. Must be a number.", args[0])))
+	todos, rest, ok := c.bulkTargets(args, "li schedule <ids>|--filter \"<query>\" \"<time block>\" [--force]")
+	if !ok {
 		return
 	}
+	if len(rest) == 0 {
+		fmt.Fprintln(c.out, errorStyle.Render("Error: Time block is required"))
+		return
+	}
+	// How the todos were picked, for the --force hint
+	targets := strings.Join(args[:len(args)-len(rest)], " ")
+	if args[0] == "--filter" {
+		targets = fmt.Sprintf("--filter %q", args[1])
+	}
 
-	timeBlockStr := strings.Join(args[1:], " ")
-	timeBlock, err := ParseTimeBlock(timeBlockStr)
-	if err != nil {
-		fmt.Println(errorStyle.Render(fmt.Sprintf("Error parsing time block: %v", err)))
-		fmt.Println("Examples of valid time blocks:")
-		fmt.Println("  \"Monday 2pm-4pm\"")
-		fmt.Println("  \"tomorrow 9am for 2 hours\"")
-		fmt.Println("  \"Dec 25 10am-12pm\"")
+	if strings.ToLower(rest[0]) == "next-free" {
+		if len(todos) > 1 {
+			fmt.Fprintln(c.out, errorStyle.Render("Error: next-free schedules one todo at a time"))
+			return
+		}
+		c.scheduleNextFree(&todos[0], rest[1:])
 		return
 	}
 
-	err = c.db.ScheduleTodo(id, timeBlock.Start, timeBlock.End)
+	// A start time alone lasts for the todo's estimate
+	timeBlockStr := strings.Join(rest, " ")
+	timeBlock, err := ParseTimeBlockFor(timeBlockStr, c.clock, sharedEstimate(todos))
 	if err != nil {
-		fmt.Println(errorStyle.Render(fmt.Sprintf("Error scheduling todo: %v", err)))
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error parsing time block: %v", err)))
+		fmt.Fprintln(c.out, "Examples of valid time blocks:")
+		fmt.Fprintln(c.out, "  \"Monday 2pm-4pm\"")
+		fmt.Fprintln(c.out, "  \"tomorrow 9am for 2 hours\"")
+		fmt.Fprintln(c.out, "  \"Dec 25 10am-12pm\"")
+		fmt.Fprintln(c.out, "  \"fri 2-4pm\"")
+		fmt.Fprintln(c.out, "  \"first monday of june 9am for 2h30m\"")
+		fmt.Fprintln(c.out, "  \"friday all day\" or \"dec 24-26\"")
+		return
+	}
+
+	ids := make([]int, len(todos))
+	for i, todo := range todos {
+		ids[i] = todo.ID
+	}
+	if !force && c.reportConflicts(ids, timeBlock, fmt.Sprintf("li schedule %s \"%s\" --force", targets, timeBlockStr)) {
+		return
+	}
+
+	edit := BulkEdit{Block: timeBlock}
+	if !c.applyBulk(todos, edit, "Schedule", yes) {
 		return
 	}
 
-	fmt.Println(successStyle.Render(fmt.Sprintf("📅 Scheduled todo %d: %s", id, FormatTimeBlock(timeBlock.Start, timeBlock.End))))
+	if len(todos) == 1 {
+		fmt.Fprintln(c.out, successStyle.Render(fmt.Sprintf("📅 Scheduled todo %d: %s", ids[0], FormatTimeBlock(timeBlock.Start, timeBlock.End, timeBlock.AllDay))))
+		return
+	}
+	c.printBulkSummary(fmt.Sprintf("📅 Scheduled %d todos: %s", len(todos), strings.TrimPrefix(FormatTimeBlock(timeBlock.Start, timeBlock.End, timeBlock.AllDay), "Scheduled: ")), todos, edit)
 }
 
-func (c *CLI) handleUI() {
-	fmt.Println(titleStyle.Render("🚀 Launching TUI mode..."))
-	err := RunTUI(c.db)
+// reportConflicts prints the blocks that block would overlap, along with
+// the command that books it anyway. It reports whether there were any. The
+// todos being booked into block don't count as overlapping each other.
+func (c *CLI) reportConflicts(ids []int, block *TimeBlock, force string) bool {
+	found, err := ScheduleConflicts(c.db, ids[0], block.Start, block.End, block.AllDay)
 	if err != nil {
-		fmt.Println(errorStyle.Render(fmt.Sprintf("Error running TUI: %v", err)))
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error checking for overlaps: %v", err)))
+		return true
+	}
+	var conflicts []Todo
+	for _, todo := range found {
+		if !slices.Contains(ids, todo.ID) {
+			conflicts = append(conflicts, todo)
+		}
+	}
+	if len(conflicts) == 0 {
+		return false
+	}
+
+	fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("⚠️  %s overlaps:", strings.TrimPrefix(FormatTimeBlock(block.Start, block.End, block.AllDay), "Scheduled: "))))
+	for _, todo := range conflicts {
+		fmt.Fprintf(c.out, "  %s %s %s\n",
+			idStyle.Render(fmt.Sprintf("[%d]", todo.ID)),
+			todo.Title,
+			descStyle.Render(strings.TrimPrefix(FormatTimeBlock(todo.ScheduledStart, todo.ScheduledEnd, todo.AllDay), "Scheduled: ")))
 	}
+	fmt.Fprintln(c.out, descStyle.Render("Schedule it anyway with: ")+styleCommand(force))
+	return true
 }
 
-func (c *CLI) printUsage() {
-	fmt.Println(titleStyle.Render("⚡ Lithium"))
-	fmt.Println()
-	fmt.Println(commandStyle.Render("Usage:"))
+func (c *CLI) handleMove(args []string) {
+	// --force moves the block even if it overlaps other blocks
+	force, args := cutFlag(args, "--force", "-f")
 
-	commands := [][]string{
-		{"li add <title> [description]", "Add a new todo"},
-		{"li list", "List all todos"},
-		{"li inbox", "List unscheduled todos"},
-		{"li today", "List today's scheduled todos"},
-		{"li day <date>", "List todos for a specific date"},
-		{"li calendar [month|week] [date]", "Show calendar view"},
-		{"li toggle <id>", "Toggle todo completion"},
-		{"li delete <id>", "Delete a todo"},
-		{"li edit <id> <title> [description]", "Edit a todo"},
-		{"li schedule <id> \"<time block>\"", "Schedule a time block for a todo"},
-		{"li ui", "Launch interactive TUI mode"},
-		{"li help", "Show this help"},
+	if len(args) < 2 {
+		fmt.Fprintln(c.out, errorStyle.Render("Error: Todo ID and target are required"))
+		fmt.Fprintln(c.out, styleCommand("Usage: li move <id> <target> [--force]"))
+		fmt.Fprintln(c.out, "Examples:")
+		fmt.Fprintln(c.out, "  "+styleCommand("li move 1 +1d"))
+		fmt.Fprintln(c.out, "  "+styleCommand("li move 1 -30m"))
+		fmt.Fprintln(c.out, "  "+styleCommand("li move 1 friday"))
+		fmt.Fprintln(c.out, "  "+styleCommand("li move 1 \"tomorrow 2pm\""))
+		return
 	}
 
-	for _, cmd := range commands {
-		// Style the command with orange variables
-		styledCmd := styleCommand(cmd[0])
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error: Invalid ID '%s'. Must be a number.", args[0])))
+		return
+	}
 
-		// Calculate spacing based on the original length (without styling)
-		spaces := 40 - len(cmd[0])
-		if spaces < 1 {
-			spaces = 1
-		}
+	todo, err := c.db.GetTodo(id)
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error moving todo: %v", err)))
+		return
+	}
+	if todo.ScheduledStart == nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error: Todo %d isn't scheduled. Schedule it with: ", id))+styleCommand(fmt.Sprintf("li schedule %d \"<time block>\"", id)))
+		return
+	}
 
-		fmt.Printf("  %s %s %s\n",
-			styledCmd,
-			strings.Repeat(" ", spaces),
-			descStyle.Render(cmd[1]))
+	target := strings.Join(args[1:], " ")
+	timeBlock, err := MoveBlock(*todo, target, c.clock)
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error moving todo: %v", err)))
+		return
 	}
 
-	fmt.Println()
-	fmt.Println(commandStyle.Render("Aliases:"))
-	aliases := [][]string{
-		{"a, add", "l, ls, list", "i, inbox", "tod, today", "day, date", "cal, calendar", "t, toggle", "d, del, delete", "e, edit", "s, schedule"},
+	if !force && c.reportConflicts([]int{id}, timeBlock, fmt.Sprintf("li move %d \"%s\" --force", id, target)) {
+		return
 	}
 
-	for _, aliasGroup := range aliases {
-		fmt.Print("  ")
-		for i, alias := range aliasGroup {
-			if i > 0 {
-				fmt.Print("     ")
-			}
-			fmt.Print(descStyle.Render(alias))
-		}
-		fmt.Println()
+	err = c.db.ScheduleTodo(id, timeBlock.Start, timeBlock.End, timeBlock.AllDay)
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error moving todo: %v", err)))
+		return
 	}
+
+	fmt.Fprintln(c.out, successStyle.Render(fmt.Sprintf("📅 Moved todo %d to %s", id, strings.TrimPrefix(FormatTimeBlock(timeBlock.Start, timeBlock.End, timeBlock.AllDay), "Scheduled: "))))
 }
 
-func (c *CLI) renderTodoList(todos []Todo, title, emptyMessage string) {
-	if len(todos) == 0 {
-		fmt.Println(descStyle.Render(emptyMessage))
+func (c *CLI) handleUnschedule(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(c.out, errorStyle.Render("Error: Todo ID is required"))
+		fmt.Fprintln(c.out, styleCommand("Usage: li unschedule <id>"))
 		return
 	}
 
-	fmt.Println(titleStyle.Render(title))
-	fmt.Println()
-	
-	for _, todo := range todos {
-		status, statusColor := createStatusStyle(todo.Done)
-
-		id := idStyle.Render(fmt.Sprintf("[%d]", todo.ID))
-		statusStyled := lipgloss.NewStyle().Foreground(statusColor).Render(status)
-		
-		todoText := todo.Title
-		if todo.Done {
-			todoText = completedStyle.Render(todoText)
-		}
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error: Invalid ID '%s'. Must be a number.", args[0])))
+		return
+	}
 
-		line := fmt.Sprintf("%s %s %s", id, statusStyled, todoText)
+	todo, err := c.db.GetTodo(id)
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error unscheduling todo: %v", err)))
+		return
+	}
+	if todo.ScheduledStart == nil {
+		fmt.Fprintln(c.out, descStyle.Render(fmt.Sprintf("Todo %d is already in the inbox.", id)))
+		return
+	}
+
+	if err := c.db.ScheduleTodo(id, nil, nil, false); err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error unscheduling todo: %v", err)))
+		return
+	}
+
+	fmt.Fprintln(c.out, successStyle.Render(fmt.Sprintf("📥 Moved todo %d back to the inbox: %s", id, todo.Title)))
+}
+
+func (c *CLI) handlePostpone(args []string) {
+	// --overdue postpones everything that has slipped instead of one todo
+	overdue, args := cutFlag(args, "--overdue")
+
+	var todos []Todo
+	if overdue {
+		var err error
+		todos, err = c.db.GetOverdueTodos(c.clock.Now())
+		if err != nil {
+			fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error loading overdue todos: %v", err)))
+			return
+		}
+	} else if len(args) > 0 {
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error: Invalid ID '%s'. Must be a number.", args[0])))
+			return
+		}
+		todo, err := c.db.GetTodo(id)
+		if err != nil {
+			fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error postponing todo: %v", err)))
+			return
+		}
+		todos = append(todos, *todo)
+		args = args[1:]
+	}
+
+	if len(args) == 0 {
+		fmt.Fprintln(c.out, errorStyle.Render("Error: A todo ID or --overdue, and a day, are required"))
+		fmt.Fprintln(c.out, styleCommand("Usage: li postpone <id>|--overdue <day>"))
+		fmt.Fprintln(c.out, "Examples:")
+		fmt.Fprintln(c.out, "  "+styleCommand("li postpone --overdue tomorrow"))
+		fmt.Fprintln(c.out, "  "+styleCommand("li postpone 3 monday"))
+		return
+	}
+
+	day, err := parseScheduleDate(strings.Join(args, " "), c.clock)
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error parsing day: %v", err)))
+		return
+	}
+
+	now := c.clock.Now()
+	moved := 0
+	for _, todo := range todos {
+		// A single todo moves even if nothing has slipped yet
+		postponed, err := Postpone(c.db, todo, *day, now, !overdue)
+		if err != nil {
+			fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error postponing todo %d: %v", todo.ID, err)))
+			continue
+		}
+		if postponed == nil {
+			continue
+		}
+		moved++
+
+		var changes []string
+		if postponed.Block != nil {
+			changes = append(changes, strings.TrimPrefix(FormatTimeBlock(postponed.Block.Start, postponed.Block.End, postponed.Block.AllDay), "Scheduled: "))
+		}
+		if postponed.Due != nil {
+			changes = append(changes, "due "+FormatDueDate(postponed.Due, c.clock))
+		}
+		fmt.Fprintf(c.out, "  %s %s %s\n",
+			idStyle.Render(fmt.Sprintf("[%d]", todo.ID)),
+			todo.Title,
+			descStyle.Render("→ "+strings.Join(changes, ", ")))
+	}
+
+	switch {
+	case moved == 0 && overdue:
+		fmt.Fprintln(c.out, successStyle.Render("✨ Nothing is overdue."))
+	case moved == 0:
+		fmt.Fprintln(c.out, descStyle.Render(fmt.Sprintf("Todo %d has no block or due date to postpone.", todos[0].ID)))
+	default:
+		fmt.Fprintln(c.out, successStyle.Render(fmt.Sprintf("📅 Postponed %d todos to %s", moved, activeLocale.WeekdayDate(*day))))
+	}
+}
+
+// scheduleNextFree books a todo into the earliest free slot that fits,
+// lasting for the given duration or else the todo's estimate
+func (c *CLI) scheduleNextFree(todo *Todo, args []string) {
+	id := todo.ID
+	length := todo.Estimate
+	if len(args) > 0 {
+		parsed, err := ParseDuration(strings.Join(args, " "))
+		if err != nil || parsed <= 0 {
+			fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error: Invalid duration '%s'", strings.Join(args, " "))))
+			return
+		}
+		length = parsed
+	}
+	if length == 0 {
+		fmt.Fprintln(c.out, errorStyle.Render("Error: A duration is required, since the todo has no estimate"))
+		fmt.Fprintln(c.out, styleCommand("Usage: li schedule <id> next-free [duration]"))
+		return
+	}
+
+	options, err := c.config.CalendarOptions()
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		return
+	}
+
+	timeBlock, err := NextFreeSlot(c.db, id, c.clock.Now(), length, options)
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error finding a free slot: %v", err)))
+		return
+	}
+
+	err = c.db.ScheduleTodo(id, timeBlock.Start, timeBlock.End, false)
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error scheduling todo: %v", err)))
+		return
+	}
+
+	fmt.Fprintln(c.out, successStyle.Render(fmt.Sprintf("📅 Scheduled todo %d: %s", id, FormatTimeBlock(timeBlock.Start, timeBlock.End, false))))
+}
+
+func (c *CLI) handleFree(args []string) {
+	var minLength time.Duration
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		if args[i] != "--min" {
+			rest = append(rest, args[i])
+			continue
+		}
+		if i+1 == len(args) {
+			fmt.Fprintln(c.out, errorStyle.Render("Error: --min needs a duration"))
+			fmt.Fprintln(c.out, styleCommand("Usage: li free [range] [--min <duration>]"))
+			return
+		}
+		i++
+		length, err := ParseDuration(args[i])
+		if err != nil {
+			fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error: Invalid duration '%s'", args[i])))
+			return
+		}
+		minLength = length
+	}
+
+	options, err := c.config.CalendarOptions()
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		return
+	}
+
+	// Without a range, look at the coming days
+	now := c.clock.Now()
+	from, to := now, startOfDay(now).AddDate(0, 0, freeSearchDays)
+	rangeStr := strings.Join(rest, " ")
+	switch strings.ToLower(rangeStr) {
+	case "":
+	case "week":
+		from = weekStartOf(now, options.WeekStart)
+		to = from.AddDate(0, 0, 7)
+	default:
+		from, to, err = parseFreeRange(rangeStr, c.clock)
+		if err != nil {
+			fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error parsing range: %v", err)))
+			fmt.Fprintln(c.out, "Examples: \"tomorrow\", \"week\", \"mon-fri\", \"friday 1pm-5pm\"")
+			return
+		}
+	}
+	if from.Before(now) {
+		from = now
+	}
+
+	todos, err := c.db.GetRangeTodos(startOfDay(from), to)
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error loading todos: %v", err)))
+		return
+	}
+
+	slots := FreeSlots(todos, from, to, options, minLength)
+	if len(slots) == 0 {
+		fmt.Fprintln(c.out, descStyle.Render("No free time in that range."))
+		return
+	}
+
+	title := "🕒 Free time"
+	if minLength > 0 {
+		title += fmt.Sprintf(" (at least %s)", formatDuration(minLength))
+	}
+	fmt.Fprintln(c.out, titleStyle.Render(title+":"))
+
+	var day time.Time
+	for _, slot := range slots {
+		if !startOfDay(slot.Start).Equal(day) {
+			day = startOfDay(slot.Start)
+			fmt.Fprintln(c.out)
+			fmt.Fprintln(c.out, idStyle.Render(activeLocale.WeekdayDate(day)))
+		}
+		fmt.Fprintf(c.out, "  %-16s %s\n",
+			activeLocale.Clock(slot.Start)+"-"+activeLocale.Clock(slot.End),
+			descStyle.Render(formatDuration(slot.Length())))
+	}
+}
+
+func (c *CLI) handlePlan(args []string) {
+	apply := false
+	span := "day"
+	for _, arg := range args {
+		switch strings.ToLower(arg) {
+		case "--apply", "-y":
+			apply = true
+		case "day", "today":
+			span = "day"
+		case "week":
+			span = "week"
+		default:
+			fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error: Unknown plan option '%s'", arg)))
+			fmt.Fprintln(c.out, styleCommand("Usage: li plan [day|week] [--apply]"))
+			return
+		}
+	}
+
+	options, err := c.config.CalendarOptions()
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		return
+	}
+
+	// Plan from now to the end of today, or of the week
+	now := c.clock.Now()
+	to := startOfDay(now).AddDate(0, 0, 1)
+	if span == "week" {
+		to = weekStartOf(now, options.WeekStart).AddDate(0, 0, 7)
+	}
+
+	plan, err := BuildPlan(c.db, now, to, options)
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error building plan: %v", err)))
+		return
+	}
+
+	if len(plan.Blocks) == 0 && len(plan.Unplaced) == 0 {
+		fmt.Fprintln(c.out, descStyle.Render("Nothing to plan. The inbox is empty!"))
+		return
+	}
+
+	last := to.AddDate(0, 0, -1)
+	rangeText := activeLocale.DateYear(last)
+	if !startOfDay(now).Equal(last) {
+		rangeText = activeLocale.Date(now) + " - " + activeLocale.DateYear(last)
+	}
+	fmt.Fprintln(c.out, titleStyle.Render(fmt.Sprintf("🗓  Plan for %s:", rangeText)))
+	fmt.Fprintln(c.out)
+
+	for _, block := range plan.Blocks {
+		line := fmt.Sprintf("+ [%d] %s  %s", block.Todo.ID, block.Todo.Title,
+			strings.TrimPrefix(FormatTimeBlock(&block.Start, &block.End, false), "Scheduled: "))
+		fmt.Fprint(c.out, successStyle.Render(line))
+		if block.Late() {
+			fmt.Fprint(c.out, " "+errorStyle.Render("(after due "+FormatDueDate(block.Todo.DueDate, c.clock)+")"))
+		}
+		fmt.Fprintln(c.out)
+	}
+	for _, todo := range plan.Unplaced {
+		fmt.Fprintln(c.out, descStyle.Render(fmt.Sprintf("  [%d] %s  (no free slot)", todo.ID, todo.Title)))
+	}
+	fmt.Fprintln(c.out)
+
+	if len(plan.Blocks) == 0 {
+		fmt.Fprintln(c.out, descStyle.Render("No free time left to plan into."))
+		return
+	}
+
+	if !apply && !c.confirm(fmt.Sprintf("Schedule %d todos? [y/N] ", len(plan.Blocks))) {
+		fmt.Fprintln(c.out, descStyle.Render("Plan not applied."))
+		return
+	}
+
+	if err := plan.Apply(c.db); err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error applying plan: %v", err)))
+		return
+	}
+	fmt.Fprintln(c.out, successStyle.Render(fmt.Sprintf("📅 Scheduled %d todos", len(plan.Blocks))))
+}
+
+func (c *CLI) handleStats(args []string) {
+	if len(args) == 0 || strings.ToLower(args[0]) != "estimates" {
+		fmt.Fprintln(c.out, errorStyle.Render("Error: A report is required"))
+		fmt.Fprintln(c.out, styleCommand("Usage: li stats estimates"))
+		return
+	}
+
+	options, err := c.config.CalendarOptions()
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		return
+	}
+
+	report, err := BuildEstimateReport(c.db, options, c.clock.Now())
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error building report: %v", err)))
+		return
+	}
+
+	fmt.Fprint(c.out, report.Render())
+}
+
+func (c *CLI) handleStart(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(c.out, errorStyle.Render("Error: Todo ID is required"))
+		fmt.Fprintln(c.out, styleCommand("Usage: li start <id>"))
+		return
+	}
+
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error: Invalid ID '%s'. Must be a number.", args[0])))
+		return
+	}
+
+	todo, err := c.db.GetTodo(id)
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error starting timer: %v", err)))
+		return
+	}
+
+	now := c.clock.Now()
+	running, err := c.db.GetRunningTimer()
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error starting timer: %v", err)))
+		return
+	}
+	if running != nil && running.TodoID == id {
+		fmt.Fprintln(c.out, descStyle.Render(fmt.Sprintf("⏱  Already tracking todo %d (%s)", id, formatTimer(running.Duration(now)))))
+		return
+	}
+
+	if err := c.db.StartTimer(id, now); err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error starting timer: %v", err)))
+		return
+	}
+
+	if running != nil {
+		fmt.Fprintln(c.out, descStyle.Render(fmt.Sprintf("⏹  Stopped todo %d after %s", running.TodoID, formatDuration(running.Duration(now)))))
+	}
+	fmt.Fprintln(c.out, successStyle.Render(fmt.Sprintf("⏱  Started timer for todo %d: %s", id, todo.Title)))
+}
+
+func (c *CLI) handleStop() {
+	now := c.clock.Now()
+	stopped, err := c.db.StopTimer(now)
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error stopping timer: %v", err)))
+		return
+	}
+	if stopped == nil {
+		fmt.Fprintln(c.out, descStyle.Render("No timer is running."))
+		return
+	}
+
+	title := ""
+	if todo, err := c.db.GetTodo(stopped.TodoID); err == nil {
+		title = ": " + todo.Title
+	}
+	fmt.Fprintln(c.out, successStyle.Render(fmt.Sprintf("⏹  Stopped todo %d%s after %s", stopped.TodoID, title, formatDuration(stopped.Duration(now)))))
+}
+
+func (c *CLI) handleStatus() {
+	running, err := c.db.GetRunningTimer()
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error loading timer: %v", err)))
+		return
+	}
+	if running == nil {
+		fmt.Fprintln(c.out, descStyle.Render("No timer is running. Start one with: ")+styleCommand("li start <id>"))
+		return
+	}
+
+	todo, err := c.db.GetTodo(running.TodoID)
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error loading timer: %v", err)))
+		return
+	}
+
+	fmt.Fprintf(c.out, "⏱  %s %s %s\n",
+		successStyle.Render(formatTimer(running.Duration(c.clock.Now()))),
+		idStyle.Render(fmt.Sprintf("[%d]", todo.ID)),
+		todo.Title)
+	fmt.Fprintln(c.out, descStyle.Render("   Started "+activeLocale.DateTime(running.StartedAt)))
+}
+
+func (c *CLI) handleFocus(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(c.out, errorStyle.Render("Error: Todo ID is required"))
+		fmt.Fprintln(c.out, styleCommand("Usage: li focus <id>"))
+		return
+	}
+
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error: Invalid ID '%s'. Must be a number.", args[0])))
+		return
+	}
+
+	todo, err := c.db.GetTodo(id)
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error starting focus: %v", err)))
+		return
+	}
+
+	syncInterval, err := c.config.GetSyncInterval()
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error reading config: %v", err)))
+		return
+	}
+
+	options, err := c.config.CalendarOptions()
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error reading config: %v", err)))
+		return
+	}
+
+	focusOptions, err := c.config.FocusOptions()
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error reading config: %v", err)))
+		return
+	}
+
+	if err := RunFocus(c.db, c.clock, options, syncInterval, focusOptions, *todo); err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error running focus: %v", err)))
+		return
+	}
+
+	count, err := c.db.CountPomodoros(id)
+	if err == nil && count > 0 {
+		fmt.Fprintln(c.out, successStyle.Render(fmt.Sprintf("🍅 %d pomodoros on todo %d: %s", count, id, todo.Title)))
+	}
+}
+
+func (c *CLI) handleTimesheet(args []string) {
+	date := c.clock.Now()
+	span := "week"
+	asCSV := false
+
+	var rest []string
+	for _, arg := range args {
+		switch arg {
+		case "--day":
+			span = "day"
+		case "--week":
+			span = "week"
+		case "--month":
+			span = "month"
+		case "--csv":
+			asCSV = true
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	if len(rest) > 0 {
+		parsed, err := parseScheduleDate(strings.Join(rest, " "), c.clock)
+		if err != nil {
+			fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error parsing date: %v", err)))
+			fmt.Fprintln(c.out, styleCommand("Usage: li timesheet [--day|--week|--month] [date] [--csv]"))
+			return
+		}
+		date = *parsed
+	}
+
+	var from, to time.Time
+	switch span {
+	case "day":
+		from = startOfDay(date)
+		to = from.AddDate(0, 0, 1)
+	case "week":
+		options, err := c.config.CalendarOptions()
+		if err != nil {
+			fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error: %v", err)))
+			return
+		}
+		from = weekStartOf(date, options.WeekStart)
+		to = from.AddDate(0, 0, 7)
+	case "month":
+		from = time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
+		to = from.AddDate(0, 1, 0)
+	}
+
+	sheet, err := BuildTimesheet(c.db, from, to, c.clock.Now())
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error building timesheet: %v", err)))
+		return
+	}
+
+	if asCSV {
+		if err := sheet.WriteCSV(c.out); err != nil {
+			fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error writing CSV: %v", err)))
+		}
+		return
+	}
+	fmt.Fprint(c.out, sheet.Render())
+}
+
+// confirm asks a yes/no question, defaulting to no
+func (c *CLI) confirm(question string) bool {
+	fmt.Fprint(c.out, question)
+	answer, _ := bufio.NewReader(c.in).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+func (c *CLI) handleOverlaps(args []string) {
+	date := c.clock.Now()
+	span := "week"
+
+	var rest []string
+	for _, arg := range args {
+		switch arg {
+		case "--day":
+			span = "day"
+		case "--week":
+			span = "week"
+		case "--month":
+			span = "month"
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	if len(rest) > 0 {
+		parsed, err := parseScheduleDate(strings.Join(rest, " "), c.clock)
+		if err != nil {
+			fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error parsing date: %v", err)))
+			fmt.Fprintln(c.out, styleCommand("Usage: li overlaps [--day|--week|--month] [date]"))
+			return
+		}
+		date = *parsed
+	}
+
+	var first, last time.Time
+	switch span {
+	case "day":
+		first = startOfDay(date)
+		last = first
+	case "week":
+		options, err := c.config.CalendarOptions()
+		if err != nil {
+			fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error: %v", err)))
+			return
+		}
+		first = weekStartOf(date, options.WeekStart)
+		last = first.AddDate(0, 0, 6)
+	case "month":
+		first = time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
+		last = first.AddDate(0, 1, -1)
+	}
+
+	todos, err := c.db.GetRangeTodos(first, last)
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error loading todos: %v", err)))
+		return
+	}
+
+	rangeText := activeLocale.DateYear(first)
+	if !last.Equal(first) {
+		rangeText = activeLocale.Date(first) + " - " + activeLocale.DateYear(last)
+	}
+
+	overlaps := FindOverlaps(todos)
+	if len(overlaps) == 0 {
+		fmt.Fprintln(c.out, successStyle.Render(fmt.Sprintf("✅ No overlapping blocks for %s", rangeText)))
+		return
+	}
+
+	fmt.Fprintln(c.out, titleStyle.Render(fmt.Sprintf("⚠️  Overlapping blocks for %s:", rangeText)))
+	fmt.Fprintln(c.out)
+	for _, overlap := range overlaps {
+		for _, todo := range []Todo{overlap.First, overlap.Second} {
+			fmt.Fprintf(c.out, "  %s %s %s\n",
+				idStyle.Render(fmt.Sprintf("[%d]", todo.ID)),
+				todo.Title,
+				overlapStyle.Render(strings.TrimPrefix(FormatTimeBlock(todo.ScheduledStart, todo.ScheduledEnd, todo.AllDay), "Scheduled: ")))
+		}
+		fmt.Fprintln(c.out)
+	}
+}
+
+func (c *CLI) handleRemind(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(c.out, errorStyle.Render("Error: Todo ID is required"))
+		fmt.Fprintln(c.out, styleCommand("Usage: li remind <id> [\"<reminder>\"]"))
+		fmt.Fprintln(c.out, "Examples:")
+		fmt.Fprintln(c.out, "  "+styleCommand("li remind 1 \"15m before start\""))
+		fmt.Fprintln(c.out, "  "+styleCommand("li remind 1 \"1 day before due\""))
+		fmt.Fprintln(c.out, "  "+styleCommand("li remind 1 \"tomorrow 8am\""))
+		return
+	}
+
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error: Invalid ID '%s'. Must be a number.", args[0])))
+		return
+	}
+
+	// Without a reminder expression, list the todo's reminders
+	if len(args) == 1 {
+		reminders, err := c.db.GetTodoReminders(id)
+		if err != nil {
+			fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error listing reminders: %v", err)))
+			return
+		}
+
+		if len(reminders) == 0 {
+			fmt.Fprintln(c.out, descStyle.Render(fmt.Sprintf("No reminders for todo %d. Add one with: ", id))+styleCommand("li remind <id> \"15m before start\""))
+			return
+		}
+
+		fmt.Fprintln(c.out, titleStyle.Render(fmt.Sprintf("⏰ Reminders for todo %d:", id)))
+		fmt.Fprintln(c.out)
+		for _, reminder := range reminders {
+			fmt.Fprintln(c.out, todoStyle.Render("• "+FormatReminder(reminder)))
+		}
+		return
+	}
+
+	reminderStr := strings.Join(args[1:], " ")
+	reminder, err := ParseReminder(reminderStr, c.clock)
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error parsing reminder: %v", err)))
+		fmt.Fprintln(c.out, "Examples of valid reminders:")
+		fmt.Fprintln(c.out, "  \"15m before start\"")
+		fmt.Fprintln(c.out, "  \"1 day before due\"")
+		fmt.Fprintln(c.out, "  \"tomorrow 8am\"")
+		return
+	}
+
+	err = c.db.AddReminder(id, *reminder)
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error adding reminder: %v", err)))
+		return
+	}
+
+	fmt.Fprintln(c.out, successStyle.Render(fmt.Sprintf("⏰ Added reminder for todo %d: %s", id, FormatReminder(*reminder))))
+}
+
+func (c *CLI) handleSync() {
+	if !c.db.SyncEnabled() {
+		fmt.Fprintln(c.out, errorStyle.Render("Error: Sync is not configured"))
+		fmt.Fprintln(c.out, "Set syncUrl (and authToken if required) in ~/.config/lithium/config.yaml")
+		return
+	}
+
+	result, err := c.db.Sync()
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error syncing: %v", err)))
+		return
+	}
+
+	if c.config.OfflineSync() {
+		fmt.Fprintln(c.out, successStyle.Render(fmt.Sprintf("🔄 Synced: %d changes sent, %d received", result.OpsPushed, result.OpsPulled)))
+		if result.Conflicts > 0 {
+			fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("⚠️  %d conflicting edits. Review them with: ", result.Conflicts))+styleCommand("li conflicts"))
+		}
+		return
+	}
+
+	fmt.Fprintln(c.out, successStyle.Render(fmt.Sprintf("🔄 Synced: %d frames applied (now at frame %d)", result.FramesSynced, result.FrameNo)))
+}
+
+func (c *CLI) handleConflicts(args []string) {
+	if len(args) > 0 {
+		if args[0] != "resolve" || len(args) < 3 {
+			fmt.Fprintln(c.out, errorStyle.Render("Error: Conflict ID and choice are required"))
+			fmt.Fprintln(c.out, styleCommand("Usage: li conflicts resolve <id> local|remote"))
+			return
+		}
+
+		id, err := strconv.Atoi(args[1])
+		if err != nil {
+			fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error: Invalid ID '%s'. Must be a number.", args[1])))
+			return
+		}
+
+		keep := strings.ToLower(args[2])
+		err = c.db.ResolveConflict(id, keep)
+		if err != nil {
+			fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error resolving conflict: %v", err)))
+			return
+		}
+
+		fmt.Fprintln(c.out, successStyle.Render(fmt.Sprintf("✅ Resolved conflict %d: kept %s value", id, keep)))
+		return
+	}
+
+	conflicts, err := c.db.GetConflicts()
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error listing conflicts: %v", err)))
+		return
+	}
+
+	if len(conflicts) == 0 {
+		fmt.Fprintln(c.out, descStyle.Render("No sync conflicts. ✅"))
+		return
+	}
+
+	fmt.Fprintln(c.out, titleStyle.Render("⚠️  Sync Conflicts:"))
+	fmt.Fprintln(c.out)
+
+	for _, conflict := range conflicts {
+		id := idStyle.Render(fmt.Sprintf("[%d]", conflict.ID))
+		title := conflict.TodoTitle
+		if conflict.TodoID == 0 {
+			title = completedStyle.Render("(deleted todo)")
+		}
+		fmt.Fprintln(c.out, todoStyle.Render(fmt.Sprintf("%s %s · %s", id, title, conflict.Field)))
+
+		local := "  local:  " + FormatFieldValue(conflict.Field, conflict.LocalValue, c.clock.Now().Location())
+		remote := "  remote: " + FormatFieldValue(conflict.Field, conflict.RemoteValue, c.clock.Now().Location())
+		if conflict.Winner == conflictLocal {
+			local += " (kept)"
+		} else {
+			remote += " (kept)"
+		}
+		fmt.Fprintln(c.out, todoStyle.Render(descStyle.Render(local)))
+		fmt.Fprintln(c.out, todoStyle.Render(descStyle.Render(remote)))
+	}
+
+	fmt.Fprintln(c.out)
+	fmt.Fprintln(c.out, "Resolve with: "+styleCommand("li conflicts resolve <id> local|remote"))
+}
+
+func (c *CLI) handleUI() {
+	syncInterval, err := c.config.GetSyncInterval()
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error reading config: %v", err)))
+		return
+	}
+
+	options, err := c.config.CalendarOptions()
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error reading config: %v", err)))
+		return
+	}
+
+	focusOptions, err := c.config.FocusOptions()
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error reading config: %v", err)))
+		return
+	}
+
+	fmt.Fprintln(c.out, titleStyle.Render("🚀 Launching TUI mode..."))
+	err = RunTUI(c.db, c.clock, options, syncInterval, focusOptions)
+	if err != nil {
+		fmt.Fprintln(c.out, errorStyle.Render(fmt.Sprintf("Error running TUI: %v", err)))
+	}
+}
+
+func (c *CLI) printUsage() {
+	fmt.Fprintln(c.out, titleStyle.Render("⚡ Lithium"))
+	fmt.Fprintln(c.out)
+	fmt.Fprintln(c.out, commandStyle.Render("Usage:"))
+
+	commands := [][]string{
+		{"li add <title> [description]", "Add a new todo"},
+		{"li list [--filter \"<query>\"]", "List all todos, or those matching a filter"},
+		{"li filter save <name> \"<query>\"", "Save a filter, e.g. \"done:false due<+3d tag:work\""},
+		{"li filter [list|delete <name>|<name>]", "List, delete or run saved filters"},
+		{"li inbox", "List unscheduled todos"},
+		{"li today", "List today's scheduled todos"},
+		{"li overdue", "List todos whose due date or block has passed"},
+		{"li upcoming [days]", "List blocks and due dates in the next 7 days"},
+		{"li someday", "List open todos with no date or block"},
+		{"li day <date>", "List todos for a specific date"},
+		{"li calendar [year|month|week|day] [date]", "Show calendar view (--grid, --weeks)"},
+		{"li agenda [days]", "List blocks and due dates for the coming days"},
+		{"li toggle <ids>", "Toggle todos, e.g. 3 5 7-12 or 4,9"},
+		{"li done <ids>|--filter \"<query>\"", "Mark todos done"},
+		{"li delete <ids>|--filter \"<query>\"", "Delete todos"},
+		{"li tag <ids>|--filter \"<query>\" +tag -tag", "Add or remove tags"},
+		{"li edit <id> <title> [description]", "Edit a todo"},
+		{"li schedule <ids> \"<time block>\"", "Schedule a time block for todos"},
+		{"li schedule <id> next-free [duration]", "Schedule the earliest free slot that fits"},
+		{"li move <id> <target>", "Move a block, e.g. +1d, -30m, friday, \"tomorrow 2pm\""},
+		{"li postpone <id>|--overdue <day>", "Move slipped blocks and due dates to a day"},
+		{"li unschedule <id>", "Send a todo back to the inbox"},
+		{"li remind <id> [\"<reminder>\"]", "Add or list reminders for a todo"},
+		{"li overlaps [--day|--week|--month] [date]", "List overlapping time blocks"},
+		{"li free [range] [--min <duration>]", "List free time within working hours"},
+		{"li plan [day|week] [--apply]", "Time-block the inbox into free time"},
+		{"li stats estimates", "Compare estimates with scheduled and tracked time"},
+		{"li start <id>", "Start tracking time on a todo"},
+		{"li stop", "Stop the running timer"},
+		{"li status", "Show the running timer"},
+		{"li timesheet [--day|--week|--month] [date] [--csv]", "Report tracked time by day and project"},
+		{"li focus <id>", "Work on a todo in pomodoros"},
+		{"li sync", "Sync with the remote database"},
+		{"li conflicts [resolve <id> local|remote]", "List or resolve sync conflicts"},
+		{"li ui", "Launch interactive TUI mode"},
+		{"li help", "Show this help"},
+	}
+
+	for _, cmd := range commands {
+		// Style the command with orange variables
+		styledCmd := styleCommand(cmd[0])
+
+		// Calculate spacing based on the original length (without styling)
+		spaces := 40 - len(cmd[0])
+		if spaces < 1 {
+			spaces = 1
+		}
+
+		fmt.Fprintf(c.out, "  %s %s %s\n",
+			styledCmd,
+			strings.Repeat(" ", spaces),
+			descStyle.Render(cmd[1]))
+	}
+
+	fmt.Fprintln(c.out)
+	fmt.Fprintln(c.out, commandStyle.Render("Aliases:"))
+	aliases := [][]string{
+		{"a, add", "l, ls, list", "f, filter", "i, inbox", "tod, today", "day, date", "cal, calendar", "ag, agenda", "t, toggle", "d, del, delete", "e, edit", "s, schedule", "r, remind"},
+	}
+
+	for _, aliasGroup := range aliases {
+		fmt.Fprint(c.out, "  ")
+		for i, alias := range aliasGroup {
+			if i > 0 {
+				fmt.Fprint(c.out, "     ")
+			}
+			fmt.Fprint(c.out, descStyle.Render(alias))
+		}
+		fmt.Fprintln(c.out)
+	}
+}
+
+func (c *CLI) renderTodoList(todos []Todo, title, emptyMessage string) {
+	if len(todos) == 0 {
+		fmt.Fprintln(c.out, descStyle.Render(emptyMessage))
+		return
+	}
+
+	fmt.Fprintln(c.out, titleStyle.Render(title))
+	fmt.Fprintln(c.out)
+
+	for _, todo := range todos {
+		status, statusColor := createStatusStyle(todo.Done)
+
+		id := idStyle.Render(fmt.Sprintf("[%d]", todo.ID))
+		statusStyled := lipgloss.NewStyle().Foreground(statusColor).Render(status)
+
+		todoText := todo.Title
+		if todo.Done {
+			todoText = completedStyle.Render(todoText)
+		}
+
+		line := fmt.Sprintf("%s %s %s", id, statusStyled, todoText)
 
 		if todo.Description != "" {
 			descText := todo.Description
@@ -470,12 +1839,21 @@ func (c *CLI) renderTodoList(todos []Todo, title, emptyMessage string) {
 			line += fmt.Sprintf(" - %s", descText)
 		}
 
+		if labels := FormatLabels(todo); labels != "" {
+			line += " " + descStyle.Render(labels)
+		}
+
+		if todo.DueDate != nil {
+			dueStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(GetDueDateColor(todo.DueDate, c.clock)))
+			line += dueStyle.Render(fmt.Sprintf(" [Due: %s]", FormatDueDate(todo.DueDate, c.clock)))
+		}
+
 		// Add time block info if scheduled
-		if timeBlock := FormatTimeBlock(todo.ScheduledStart, todo.ScheduledEnd); timeBlock != "" {
+		if timeBlock := FormatTimeBlock(todo.ScheduledStart, todo.ScheduledEnd, todo.AllDay); timeBlock != "" {
 			timeBlockStyled := lipgloss.NewStyle().Foreground(lipgloss.Color(ColorBlue)).Render(fmt.Sprintf(" [%s]", timeBlock))
 			line += timeBlockStyled
 		}
 
-		fmt.Println(todoStyle.Render(line))
+		fmt.Fprintln(c.out, todoStyle.Render(line))
 	}
 }
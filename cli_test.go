@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestCLI returns a CLI over a fresh MemoryStore, writing to the
+// returned buffer
+func newTestCLI() (*CLI, *MemoryStore, *bytes.Buffer) {
+	store := newTestStore()
+	out := &bytes.Buffer{}
+	c := NewCLI(store, &Config{}, NewFixedClock(testNow))
+	c.out = out
+	return c, store, out
+}
+
+// run runs one command and returns what it printed
+func run(c *CLI, out *bytes.Buffer, command string, args ...string) string {
+	out.Reset()
+	c.HandleCommand(command, args)
+	return out.String()
+}
+
+// assertContains fails the test unless output has every one of want
+func assertContains(t *testing.T, output string, want ...string) {
+	t.Helper()
+	for _, w := range want {
+		if !strings.Contains(output, w) {
+			t.Errorf("output missing %q:\n%s", w, output)
+		}
+	}
+}
+
+func TestCLIAdd(t *testing.T) {
+	c, store, out := newTestCLI()
+
+	assertContains(t, run(c, out, "add", "Call dentist", "Ask", "about", "the", "crown"), "Added todo: Call dentist")
+	todo, err := store.GetTodo(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if todo.Title != "Call dentist" || todo.Description != "Ask about the crown" {
+		t.Errorf("stored %+v", todo)
+	}
+
+	assertContains(t, run(c, out, "add", "Pay rent #home +flat !high due:fri"),
+		"Added todo: Pay rent", "+flat #home !high", "Due: In 4 days")
+	if todo, _ := store.GetTodo(2); todo.Title != "Pay rent" || todo.Project != "flat" || !todo.DueDate.Equal(endOfDayUTC(2026, 10, 23)) {
+		t.Errorf("stored %+v", todo)
+	}
+
+	assertContains(t, run(c, out, "add"), "Error: Title is required", "Usage: li add")
+	assertContains(t, run(c, out, "add", "#home"), "Error: Title is required")
+	assertContains(t, run(c, out, "add", "Pay rent due:someday"), "Error parsing title")
+	if todos, _ := store.GetAllTodos(); len(todos) != 2 {
+		t.Errorf("got %d todos, want only the valid ones added", len(todos))
+	}
+}
+
+func TestCLIListsViews(t *testing.T) {
+	c, store, out := newTestCLI()
+	assertContains(t, run(c, out, "list"), "No todos found")
+
+	addScheduled(t, store, "Buy milk", nil, nil)
+	addScheduled(t, store, "Standup", at(2030, 1, 15, 9, 0), at(2030, 1, 15, 9, 15))
+	addScheduled(t, store, "Write report", nil, nil)
+
+	assertContains(t, run(c, out, "list"), "Your Todos", "[1] [ ] Buy milk", "[2] [ ] Standup [Scheduled: Jan 15 9:00am-9:15am]", "[3] [ ] Write report")
+
+	inbox := run(c, out, "inbox")
+	if strings.Index(inbox, "Write report") > strings.Index(inbox, "Buy milk") || strings.Contains(inbox, "Standup") {
+		t.Errorf("inbox should list unscheduled todos newest first:\n%s", inbox)
+	}
+
+	assertContains(t, run(c, out, "day", "2030-01-15"), "Standup")
+	if output := run(c, out, "day", "2030-01-16"); strings.Contains(output, "Standup") {
+		t.Errorf("listed a todo on the wrong day:\n%s", output)
+	}
+}
+
+func TestCLIEditsTodos(t *testing.T) {
+	c, store, out := newTestCLI()
+	addScheduled(t, store, "Buy milk", nil, nil)
+
+	assertContains(t, run(c, out, "toggle", "1"), "Toggled todo 1")
+	assertContains(t, run(c, out, "edit", "1", "Buy oat milk", "two", "litres"), "Updated todo 1: Buy oat milk")
+	assertContains(t, run(c, out, "schedule", "1", "2030-01-15", "2pm-4pm"), "Scheduled todo 1: Scheduled: Jan 15 2:00pm-4:00pm")
+
+	todo, _ := store.GetTodo(1)
+	if !todo.Done || todo.Title != "Buy oat milk" || todo.Description != "two litres" ||
+		!todo.ScheduledStart.Equal(*at(2030, 1, 15, 14, 0)) || !todo.ScheduledEnd.Equal(*at(2030, 1, 15, 16, 0)) {
+		t.Errorf("stored %+v", todo)
+	}
+
+	assertContains(t, run(c, out, "delete", "1"), "Deleted todo 1")
+	if todos, _ := store.GetAllTodos(); len(todos) != 0 {
+		t.Errorf("got %d todos after deleting", len(todos))
+	}
+}
+
+func TestCLIEditKeepsDatesAndEstimate(t *testing.T) {
+	c, store, out := newTestCLI()
+	addTodos(t, store, NewFixedClock(testNow), "Write report ~2h due:fri @ tomorrow 2pm")
+
+	assertContains(t, run(c, out, "edit", "1", "Write the report"), "Updated todo 1")
+
+	todo, _ := store.GetTodo(1)
+	if todo.Title != "Write the report" || todo.Estimate != 2*time.Hour || todo.DueDate == nil ||
+		!todo.ScheduledStart.Equal(*at(2026, 10, 20, 14, 0)) || !todo.ScheduledEnd.Equal(*at(2026, 10, 20, 16, 0)) {
+		t.Errorf("editing the title changed other fields: %+v", todo)
+	}
+
+	output := run(c, out, "stats", "estimates")
+	assertContains(t, output, "Write the report", "2h")
+}
+
+func TestCLIRejectsBadArguments(t *testing.T) {
+	tests := []struct {
+		command string
+		args    []string
+		want    string
+	}{
+		{command: "toggle", args: []string{"abc"}, want: "Error: Invalid ID 'abc'"},
+		{command: "toggle", args: nil, want: "Usage: li toggle"},
+		{command: "delete", args: []string{"99"}, want: "No todo with ID 99"},
+		{command: "edit", args: []string{"1"}, want: "Todo ID and title are required"},
+		{command: "schedule", args: []string{"1", "someday"}, want: "Error parsing time block"},
+		{command: "bogus", args: nil, want: "Unknown command: bogus"},
+	}
+
+	for _, tt := range tests {
+		c, store, out := newTestCLI()
+		addScheduled(t, store, "Buy milk", nil, nil)
+
+		output := run(c, out, tt.command, tt.args...)
+		if !strings.Contains(output, tt.want) {
+			t.Errorf("li %s %s: output missing %q:\n%s", tt.command, strings.Join(tt.args, " "), tt.want, output)
+		}
+		if todo, err := store.GetTodo(1); err != nil || todo.Done || todo.Title != "Buy milk" || todo.ScheduledStart != nil {
+			t.Errorf("li %s %s changed the todo: %+v", tt.command, strings.Join(tt.args, " "), todo)
+		}
+	}
+}
+
+func TestCLISyncWithoutRemote(t *testing.T) {
+	c, _, out := newTestCLI()
+	assertContains(t, run(c, out, "sync"), "Sync is not configured", "syncUrl")
+}
+
+func TestCLICalendarViews(t *testing.T) {
+	c, store, out := newTestCLI()
+	addScheduled(t, store, "Standup", at(2026, 10, 20, 9, 0), at(2026, 10, 20, 9, 30))
+
+	assertContains(t, run(c, out, "calendar"), "📅 October 2026", "Standup")
+	assertContains(t, run(c, out, "calendar", "day", "tomorrow"), "📅 Tuesday, Oct 20, 2026", " 9:00am │ Standup")
+	assertContains(t, run(c, out, "calendar", "week", "--grid"), "📅 Week of Oct 18 - Oct 24, 2026", "Tue 20", "Stand")
+}
+
+func TestCLICalendarYear(t *testing.T) {
+	c, store, out := newTestCLI()
+	addScheduled(t, store, "Offsite", at(2027, 1, 1, 9, 0), at(2027, 1, 1, 17, 0))
+
+	assertContains(t, run(c, out, "calendar", "year", "2027"), "📅 2027", "January", "December")
+	assertContains(t, run(c, out, "calendar", "year"), "📅 2026")
+	assertContains(t, run(c, out, "calendar", "--weeks"), "Wk    Sun", "43     18")
+}
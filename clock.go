@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Clock supplies the current time to date parsing and rendering
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock reads the real wall clock in the configured zone
+type systemClock struct {
+	loc *time.Location
+}
+
+func (c systemClock) Now() time.Time {
+	return time.Now().In(c.loc)
+}
+
+// fixedClock always reports the same instant
+type fixedClock struct {
+	now time.Time
+}
+
+func (c fixedClock) Now() time.Time {
+	return c.now
+}
+
+// NewSystemClock returns the clock used outside of tests and previews.
+// Times it reports, and so everything parsed relative to them, are in loc.
+func NewSystemClock(loc *time.Location) Clock {
+	return systemClock{loc: loc}
+}
+
+// NewFixedClock returns a clock frozen at now
+func NewFixedClock(now time.Time) Clock {
+	return fixedClock{now: now}
+}
+
+// ParseNowFlag parses the value of the hidden --now flag. It accepts
+// RFC3339 timestamps and "YYYY-MM-DD" or "YYYY-MM-DD HH:MM" values in loc.
+func ParseNowFlag(value string, loc *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed.In(loc), nil
+	}
+
+	formats := []string{
+		"2006-01-02T15:04",
+		"2006-01-02 15:04",
+		"2006-01-02",
+	}
+
+	for _, format := range formats {
+		if parsed, err := time.ParseInLocation(format, value, loc); err == nil {
+			return parsed, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unable to parse --now value: %s", value)
+}
+
+// extractNowFlag removes a global --now flag from the arguments, returning
+// the remaining arguments and the flag value ("" if absent)
+func extractNowFlag(args []string) ([]string, string, error) {
+	var rest []string
+	value := ""
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--now":
+			if i+1 >= len(args) {
+				return nil, "", fmt.Errorf("--now requires a value")
+			}
+			value = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--now="):
+			value = strings.TrimPrefix(arg, "--now=")
+		default:
+			rest = append(rest, arg)
+		}
+	}
+
+	return rest, value, nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestExtractNowFlag(t *testing.T) {
+	tests := []struct {
+		args    []string
+		rest    []string
+		value   string
+		wantErr bool
+	}{
+		{args: []string{"list"}, rest: []string{"list"}},
+		{args: []string{"--now", "2026-10-23", "today"}, rest: []string{"today"}, value: "2026-10-23"},
+		{args: []string{"calendar", "--now=2026-10-23 09:00"}, rest: []string{"calendar"}, value: "2026-10-23 09:00"},
+		{args: []string{"today", "--now"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		rest, value, err := extractNowFlag(tt.args)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("extractNowFlag(%q) succeeded, want an error", tt.args)
+			}
+			continue
+		}
+		if err != nil || !slices.Equal(rest, tt.rest) || value != tt.value {
+			t.Errorf("extractNowFlag(%q) = %q, %q, %v; want %q, %q", tt.args, rest, value, err, tt.rest, tt.value)
+		}
+	}
+}
+
+func TestParseNowFlag(t *testing.T) {
+	ny := loadLocation(t, "America/New_York")
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{value: "2026-10-23T09:30:00Z", want: day(2026, 10, 23, 9, 30)},
+		{value: "2026-10-23 09:30", want: time.Date(2026, 10, 23, 9, 30, 0, 0, ny)},
+		{value: "2026-10-23", want: time.Date(2026, 10, 23, 0, 0, 0, 0, ny)},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseNowFlag(tt.value, ny)
+		if err != nil || !got.Equal(tt.want) || got.Location() != ny {
+			t.Errorf("ParseNowFlag(%q) = %v, %v; want %v", tt.value, got, err, tt.want)
+		}
+	}
+
+	if _, err := ParseNowFlag("friday", ny); err == nil {
+		t.Error("ParseNowFlag accepted a relative date")
+	}
+}
+
+func TestSystemClockUsesLocation(t *testing.T) {
+	ny := loadLocation(t, "America/New_York")
+	if loc := NewSystemClock(ny).Now().Location(); loc != ny {
+		t.Errorf("clock reports times in %v, want %v", loc, ny)
+	}
+}
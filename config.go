@@ -4,16 +4,57 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
+// defaultSyncInterval is how often the TUI syncs an embedded replica
+// when no syncInterval is configured
+const defaultSyncInterval = time.Minute
+
+// Sync modes
+const (
+	// SyncModeReplica keeps the database as an embedded replica of syncUrl;
+	// writes go straight to the primary
+	SyncModeReplica = "replica"
+	// SyncModeOffline keeps a fully local database and exchanges field-level
+	// changes with syncUrl on sync, merging edits made while offline
+	SyncModeOffline = "offline"
+	// SyncModeGit keeps a fully local database and mirrors every change into
+	// a git repository of plain files, syncing through the git remote
+	SyncModeGit = "git"
+)
+
 // Config represents the application configuration
 type Config struct {
-	DatabasePath string  `yaml:"databasePath"`
-	SyncUrl      *string `yaml:"syncUrl"`
+	DatabasePath   string  `yaml:"databasePath"`
+	SyncUrl        *string `yaml:"syncUrl"`
+	SyncMode       string  `yaml:"syncMode"` // "replica" (default), "offline" or "git"
+	AuthToken      *string `yaml:"authToken"`
+	SyncInterval   string  `yaml:"syncInterval"`   // e.g. "30s", "5m"; "0" disables periodic sync
+	ReadYourWrites *bool   `yaml:"readYourWrites"` // See writes before the next sync (libsql default: true)
+	GitDir         string  `yaml:"gitDir"`         // Repository for git sync (default: ~/.lithium/git)
+	GitRemote      string  `yaml:"gitRemote"`      // Remote to pull from and push to; empty keeps history local
+	GitBranch      string  `yaml:"gitBranch"`      // Branch to sync (default: main)
+	Timezone       string  `yaml:"timezone"`       // IANA zone for days and display, e.g. "Europe/Berlin" (default: system zone)
+	WorkingHours   string  `yaml:"workingHours"`   // Hours shown in calendar time grids, e.g. "9-17" or "8am-6pm" (default: 8-18)
+	WeekNumbers    bool    `yaml:"weekNumbers"`    // Show ISO week numbers in the month view
+	WeekStart      string  `yaml:"weekStart"`      // First day of the week, e.g. "monday" (default: sunday)
+	Locale         string  `yaml:"locale"`         // Month and weekday names and date order: en, en-GB, de, fr, es (default: en)
+	Pomodoro       string  `yaml:"pomodoro"`       // Length of a focus pomodoro, e.g. "50m" (default: 25m)
+	ShortBreak     string  `yaml:"shortBreak"`     // Break after each pomodoro (default: 5m)
+	LongBreak      string  `yaml:"longBreak"`      // Break after every longBreakEvery pomodoros (default: 15m)
+	LongBreakEvery int     `yaml:"longBreakEvery"` // Pomodoros between long breaks (default: 4)
 }
 
+// Default working hours for calendar time grids
+const (
+	defaultWorkStart = 8
+	defaultWorkEnd   = 18
+)
+
 // DefaultConfig returns a config with default values
 func DefaultConfig() *Config {
 	homeDir, err := os.UserHomeDir()
@@ -25,7 +66,10 @@ func DefaultConfig() *Config {
 
 	fmt.Println(dbPath)
 
-	return &Config{DatabasePath: dbPath}
+	return &Config{
+		DatabasePath: dbPath,
+		GitDir:       filepath.Join(homeDir, ".lithium", "git"),
+	}
 }
 
 // LoadConfig loads configuration from the standard config locations
@@ -48,9 +92,163 @@ func LoadConfig() (*Config, error) {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
 
+	if _, err := config.GetSyncInterval(); err != nil {
+		return nil, err
+	}
+
+	if _, err := config.Location(); err != nil {
+		return nil, err
+	}
+
+	if _, err := config.CalendarOptions(); err != nil {
+		return nil, err
+	}
+
+	if _, err := config.GetLocale(); err != nil {
+		return nil, err
+	}
+
+	if _, err := config.FocusOptions(); err != nil {
+		return nil, err
+	}
+
+	switch config.SyncMode {
+	case "", SyncModeReplica, SyncModeOffline, SyncModeGit:
+	default:
+		return nil, fmt.Errorf("invalid syncMode %q: must be %q, %q or %q", config.SyncMode, SyncModeReplica, SyncModeOffline, SyncModeGit)
+	}
+
 	return config, nil
 }
 
+// GetSyncInterval returns how often a replica should be synced in the background
+func (c *Config) GetSyncInterval() (time.Duration, error) {
+	if c.SyncInterval == "" {
+		return defaultSyncInterval, nil
+	}
+
+	interval, err := time.ParseDuration(c.SyncInterval)
+	if err != nil {
+		return 0, fmt.Errorf("invalid syncInterval %q: %w", c.SyncInterval, err)
+	}
+
+	return interval, nil
+}
+
+// Location returns the zone used for day boundaries and display
+func (c *Config) Location() (*time.Location, error) {
+	if c.Timezone == "" {
+		return time.Local, nil
+	}
+
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", c.Timezone, err)
+	}
+
+	return loc, nil
+}
+
+// CalendarOptions returns the calendar display settings
+func (c *Config) CalendarOptions() (CalendarOptions, error) {
+	options := DefaultCalendarOptions()
+	options.WeekNumbers = c.WeekNumbers
+
+	if c.WeekStart != "" {
+		weekday, ok := weekdayNames[strings.ToLower(c.WeekStart)]
+		if !ok {
+			return options, fmt.Errorf("invalid weekStart %q: must be a day of the week", c.WeekStart)
+		}
+		options.WeekStart = weekday
+	}
+
+	if c.WorkingHours == "" {
+		return options, nil
+	}
+
+	expr, _, err := parseDateExpr(c.WorkingHours, time.Time{})
+	if err != nil || expr.start == nil || expr.end == nil || expr.date != nil || expr.exact != nil {
+		return options, fmt.Errorf("invalid workingHours %q: use a range of hours like \"9-17\"", c.WorkingHours)
+	}
+
+	start, end := expr.start.resolve(), expr.end.resolve()
+	if start.minute != 0 || end.minute != 0 {
+		return options, fmt.Errorf("invalid workingHours %q: must be whole hours", c.WorkingHours)
+	}
+	if end.hour <= start.hour {
+		return options, fmt.Errorf("invalid workingHours %q: must end after it starts", c.WorkingHours)
+	}
+
+	options.WorkStart = start.hour
+	options.WorkEnd = end.hour
+	return options, nil
+}
+
+// GetLocale returns the locale used to display dates
+func (c *Config) GetLocale() (*Locale, error) {
+	locale, err := findLocale(c.Locale)
+	if err != nil {
+		return nil, fmt.Errorf("invalid locale: %w", err)
+	}
+	return locale, nil
+}
+
+// FocusOptions returns the pomodoro and break lengths for focus mode
+func (c *Config) FocusOptions() (FocusOptions, error) {
+	options := DefaultFocusOptions()
+
+	lengths := []struct {
+		name  string
+		value string
+		into  *time.Duration
+	}{
+		{"pomodoro", c.Pomodoro, &options.Pomodoro},
+		{"shortBreak", c.ShortBreak, &options.ShortBreak},
+		{"longBreak", c.LongBreak, &options.LongBreak},
+	}
+	for _, length := range lengths {
+		if length.value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(length.value)
+		if err != nil || d <= 0 {
+			return options, fmt.Errorf("invalid %s %q: use a length like \"25m\"", length.name, length.value)
+		}
+		*length.into = d
+	}
+
+	if c.LongBreakEvery < 0 {
+		return options, fmt.Errorf("invalid longBreakEvery %d: must not be negative", c.LongBreakEvery)
+	}
+	if c.LongBreakEvery > 0 {
+		options.LongBreakEvery = c.LongBreakEvery
+	}
+
+	return options, nil
+}
+
+// ZoneName returns the name recorded on todos as the zone they were
+// scheduled in. Without a timezone setting this is the system zone's
+// IANA name when it can be found.
+func (c *Config) ZoneName() string {
+	if c.Timezone != "" {
+		return c.Timezone
+	}
+
+	if tz := os.Getenv("TZ"); tz != "" {
+		return strings.TrimPrefix(tz, ":")
+	}
+
+	// /etc/localtime is usually a link into the zoneinfo database
+	if target, err := os.Readlink("/etc/localtime"); err == nil {
+		if i := strings.Index(target, "zoneinfo/"); i >= 0 {
+			return target[i+len("zoneinfo/"):]
+		}
+	}
+
+	return time.Local.String()
+}
+
 // findConfigFile looks for config file in standard locations
 func findConfigFile() (string, error) {
 	homeDir, err := os.UserHomeDir()
@@ -73,6 +271,12 @@ func findConfigFile() (string, error) {
 	return "", fmt.Errorf("config file not found")
 }
 
+// OfflineSync reports whether changes are kept locally and merged with
+// other devices on sync, rather than written through to syncUrl
+func (c *Config) OfflineSync() bool {
+	return c.SyncMode == SyncModeGit || (c.SyncUrl != nil && c.SyncMode == SyncModeOffline)
+}
+
 // EnsureDatabaseDir creates the database directory if it doesn't exist
 func (c *Config) EnsureDatabaseDir() error {
 	dbDir := filepath.Dir(c.DatabasePath)
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestGetSyncInterval(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "", want: defaultSyncInterval},
+		{value: "30s", want: 30 * time.Second},
+		{value: "5m", want: 5 * time.Minute},
+		{value: "0", want: 0},
+		{value: "often", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := (&Config{SyncInterval: tt.value}).GetSyncInterval()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%q: got error %v, want error %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%q: got %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestReadYourWritesConfig(t *testing.T) {
+	tests := []struct {
+		yaml string
+		want *bool
+	}{
+		{yaml: "syncUrl: libsql://example.turso.io\n", want: nil},
+		{yaml: "readYourWrites: true\n", want: ptr(true)},
+		{yaml: "readYourWrites: false\n", want: ptr(false)},
+	}
+
+	for _, tt := range tests {
+		var config Config
+		if err := yaml.Unmarshal([]byte(tt.yaml), &config); err != nil {
+			t.Fatal(err)
+		}
+		got := config.ReadYourWrites
+		if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+			t.Errorf("%q: got %v, want %v", tt.yaml, got, tt.want)
+		}
+	}
+}
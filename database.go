@@ -3,7 +3,9 @@ package main
 import (
 	"database/sql"
 	"embed"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 
@@ -24,53 +26,124 @@ func loadSQL(filename string) (string, error) {
 
 type Todo struct {
 	ID             int
+	UUID           string // Stable identity shared across synced devices
 	Title          string
 	Description    string
 	Done           bool
 	DueDate        *time.Time // Optional due date
 	ScheduledStart *time.Time // Time block start
 	ScheduledEnd   *time.Time // Time block end
+	AllDay         bool       // Block covers whole days rather than a time of day
+	Tags           []string
+	Project        string
+	Priority       Priority
+	Estimate       time.Duration // Expected time to finish, zero if unknown
+	TimeZone       string        // Zone the todo was last scheduled from, e.g. "America/New_York"
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 }
 
+// replicaSyncer is the part of the libsql connector used to pull changes
+// from the primary, kept behind an interface so syncing can be exercised
+// against a stand-in instead of a running server
+type replicaSyncer interface {
+	Sync() (libsql.Replicated, error)
+}
+
+// SyncResult reports the outcome of a sync
+type SyncResult struct {
+	FrameNo      int // Replication frame the replica is now at
+	FramesSynced int // Frames applied by this sync
+
+	OpsPushed int // Local field changes sent (offline mode)
+	OpsPulled int // Remote field changes merged (offline mode)
+	Conflicts int // Fields edited on both sides since the last sync (offline mode)
+}
+
+// ErrSyncNotConfigured is returned when syncing a database without a syncUrl
+var ErrSyncNotConfigured = errors.New("sync is not configured: set syncUrl in your config")
+
 type DB struct {
-	conn *sql.DB
+	conn     *sql.DB
+	syncer   replicaSyncer  // nil unless the database is an embedded replica
+	backend  SyncBackend    // nil unless offline sync is configured
+	eager    bool           // Push every change to the backend as it is made
+	deviceID string         // Identifies this database's edits in synced ops
+	loc      *time.Location // Zone for day boundaries and returned times
+	zoneName string         // Recorded on todos as the zone they were written from
 
 	// Prepared statements
 	insertTodo    *sql.Stmt
 	getAllTodos   *sql.Stmt
 	getInboxTodos *sql.Stmt
-	getDateTodos  *sql.Stmt
 	getRangeTodos *sql.Stmt
-	getMonthTodos *sql.Stmt
+	getDueTodos   *sql.Stmt
+	getOverdue    *sql.Stmt
+	getUpcoming   *sql.Stmt
+	getSomeday    *sql.Stmt
 	updateTodo    *sql.Stmt
 	deleteTodo    *sql.Stmt
 	toggleTodo    *sql.Stmt
+	getTodo       *sql.Stmt
+
+	insertReminder       *sql.Stmt
+	getTodoReminders     *sql.Stmt
+	getPendingReminders  *sql.Stmt
+	markReminderNotified *sql.Stmt
+	deleteTodoReminders  *sql.Stmt
+
+	insertTimeEntry       *sql.Stmt
+	stopTimeEntries       *sql.Stmt
+	getRunningTimeEntry   *sql.Stmt
+	getTimeEntries        *sql.Stmt
+	deleteTodoTimeEntries *sql.Stmt
+
+	insertPomodoro      *sql.Stmt
+	countTodoPomodoros  *sql.Stmt
+	deleteTodoPomodoros *sql.Stmt
+
+	bulkUpdateTodo *sql.Stmt
+
+	saveFilter        *sql.Stmt
+	getSavedFilters   *sql.Stmt
+	deleteSavedFilter *sql.Stmt
 }
 
-func NewDB(dbPath string, syncUrl *string) (*DB, error) {
+func NewDB(config *Config) (*DB, error) {
+	loc, err := config.Location()
+	if err != nil {
+		return nil, err
+	}
 
-	var conn *sql.DB
+	db := &DB{loc: loc, zoneName: config.ZoneName()}
 
-	if syncUrl == nil {
-		c, err := sql.Open("libsql", dbPath)
+	if config.SyncUrl == nil || config.OfflineSync() {
+		c, err := sql.Open("libsql", config.DatabasePath)
 		if err != nil {
 			return nil, fmt.Errorf("failed to open database: %w", err)
 		}
 
-		conn = c
+		db.conn = c
 	} else {
-		connector, err := libsql.NewEmbeddedReplicaConnector(dbPath, *syncUrl)
+		var opts []libsql.Option
+		if config.AuthToken != nil {
+			opts = append(opts, libsql.WithAuthToken(*config.AuthToken))
+		}
+		if config.ReadYourWrites != nil {
+			opts = append(opts, libsql.WithReadYourWrites(*config.ReadYourWrites))
+		}
+
+		// Background syncing is driven by the caller (see Sync) so that
+		// results can be reported rather than happening invisibly
+		connector, err := libsql.NewEmbeddedReplicaConnector(config.DatabasePath, *config.SyncUrl, opts...)
 		if err != nil {
 			return nil, fmt.Errorf("failed to open sync url or database: %w", err)
 		}
 
-		conn = sql.OpenDB(connector)
+		db.conn = sql.OpenDB(connector)
+		db.syncer = connector
 	}
 
-	db := &DB{conn: conn}
-
 	if err := db.createTables(); err != nil {
 		return nil, fmt.Errorf("failed to create tables: %w", err)
 	}
@@ -79,6 +152,38 @@ func NewDB(dbPath string, syncUrl *string) (*DB, error) {
 		return nil, fmt.Errorf("failed to prepare statements: %w", err)
 	}
 
+	deviceID, err := db.loadDeviceID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load device id: %w", err)
+	}
+	db.deviceID = deviceID
+
+	if err := db.backfillUUIDs(); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case config.SyncMode == SyncModeGit:
+		backend, err := newGitBackend(config.GitDir, config.GitRemote, config.GitBranch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open git sync repository: %w", err)
+		}
+		db.backend = backend
+		db.eager = true
+	case config.OfflineSync():
+		backend, err := newLibsqlOpsBackend(*config.SyncUrl, config.AuthToken)
+		if err != nil {
+			return nil, err
+		}
+		db.backend = backend
+	}
+
+	if db.backend != nil {
+		if err := db.shareUnsyncedTodos(); err != nil {
+			return nil, err
+		}
+	}
+
 	return db, nil
 }
 
@@ -88,9 +193,17 @@ func (db *DB) createTables() error {
 		return err
 	}
 
-	_, err = db.conn.Exec(query)
-	if err != nil {
-		return err
+	// The driver only runs the first statement of a multi-statement Exec,
+	// so each CREATE TABLE is executed on its own
+	for _, statement := range strings.Split(query, ";") {
+		if strings.TrimSpace(statement) == "" {
+			continue
+		}
+
+		_, err = db.conn.Exec(statement)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Migrate existing tables to add new columns if they don't exist
@@ -125,6 +238,13 @@ func (db *DB) migrateTables() error {
 		"due_date DATETIME",
 		"scheduled_start DATETIME",
 		"scheduled_end DATETIME",
+		"uuid TEXT",
+		"tags TEXT DEFAULT ''",
+		"project TEXT DEFAULT ''",
+		"priority INTEGER DEFAULT 0",
+		"timezone TEXT DEFAULT ''",
+		"all_day BOOLEAN DEFAULT FALSE",
+		"estimate_minutes INTEGER DEFAULT 0",
 	}
 
 	for _, column := range columnsToAdd {
@@ -139,6 +259,71 @@ func (db *DB) migrateTables() error {
 		}
 	}
 
+	return db.migrateTimestampsToUTC()
+}
+
+// timestampColumns lists the columns holding times in each table
+var timestampColumns = map[string][]string{
+	"todos":     {"due_date", "scheduled_start", "scheduled_end", "created_at", "updated_at"},
+	"reminders": {"remind_at", "notified_at", "created_at"},
+}
+
+// migrateTimestampsToUTC rewrites times stored with a zone offset, as they
+// were before everything was kept in UTC, so that range queries comparing
+// the text see them in order. It runs once per database.
+func (db *DB) migrateTimestampsToUTC() error {
+	done, err := getSyncState(db.conn, syncStateUTC)
+	if err != nil || done != "" {
+		return err
+	}
+
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for table, columns := range timestampColumns {
+		for _, column := range columns {
+			if err := convertColumnToUTC(tx, table, column); err != nil {
+				return fmt.Errorf("failed to convert %s.%s to UTC: %w", table, column, err)
+			}
+		}
+	}
+
+	if err := setSyncState(tx, syncStateUTC, "1"); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// convertColumnToUTC rewrites one column's values that aren't already in UTC
+func convertColumnToUTC(tx *sql.Tx, table, column string) error {
+	rows, err := tx.Query(fmt.Sprintf("SELECT id, %s FROM %s WHERE %s IS NOT NULL AND %s NOT LIKE '%%Z'", column, table, column, column))
+	if err != nil {
+		return err
+	}
+
+	times := make(map[int]time.Time)
+	for rows.Next() {
+		var id int
+		var t time.Time
+		if err := rows.Scan(&id, &t); err != nil {
+			rows.Close()
+			return err
+		}
+		times[id] = t
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for id, t := range times {
+		if _, err := tx.Exec(fmt.Sprintf("UPDATE %s SET %s = ? WHERE id = ?", table, column), t.UTC(), id); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -173,29 +358,47 @@ func (db *DB) prepareStatements() error {
 		return err
 	}
 
-	getDateTodosSQL, err := loadSQL("get_date_todos.sql")
+	getRangeTodosSQL, err := loadSQL("get_range_todos.sql")
 	if err != nil {
 		return err
 	}
-	db.getDateTodos, err = db.conn.Prepare(getDateTodosSQL)
+	db.getRangeTodos, err = db.conn.Prepare(getRangeTodosSQL)
 	if err != nil {
 		return err
 	}
 
-	getRangeTodosSQL, err := loadSQL("get_range_todos.sql")
+	getDueTodosSQL, err := loadSQL("get_due_todos.sql")
 	if err != nil {
 		return err
 	}
-	db.getRangeTodos, err = db.conn.Prepare(getRangeTodosSQL)
+	db.getDueTodos, err = db.conn.Prepare(getDueTodosSQL)
+	if err != nil {
+		return err
+	}
+
+	getOverdueSQL, err := loadSQL("get_overdue_todos.sql")
+	if err != nil {
+		return err
+	}
+	db.getOverdue, err = db.conn.Prepare(getOverdueSQL)
+	if err != nil {
+		return err
+	}
+
+	getUpcomingSQL, err := loadSQL("get_upcoming_todos.sql")
+	if err != nil {
+		return err
+	}
+	db.getUpcoming, err = db.conn.Prepare(getUpcomingSQL)
 	if err != nil {
 		return err
 	}
 
-	getMonthTodosSQL, err := loadSQL("get_month_todos.sql")
+	getSomedaySQL, err := loadSQL("get_someday_todos.sql")
 	if err != nil {
 		return err
 	}
-	db.getMonthTodos, err = db.conn.Prepare(getMonthTodosSQL)
+	db.getSomeday, err = db.conn.Prepare(getSomedaySQL)
 	if err != nil {
 		return err
 	}
@@ -227,203 +430,883 @@ func (db *DB) prepareStatements() error {
 		return err
 	}
 
-	return nil
-}
+	getTodoSQL, err := loadSQL("get_todo.sql")
+	if err != nil {
+		return err
+	}
+	db.getTodo, err = db.conn.Prepare(getTodoSQL)
+	if err != nil {
+		return err
+	}
 
-func (db *DB) AddTodo(title, description string, dueDate, scheduledStart, scheduledEnd *time.Time) error {
-	_, err := db.insertTodo.Exec(title, description, dueDate, scheduledStart, scheduledEnd)
-	return err
-}
+	insertReminderSQL, err := loadSQL("insert_reminder.sql")
+	if err != nil {
+		return err
+	}
+	db.insertReminder, err = db.conn.Prepare(insertReminderSQL)
+	if err != nil {
+		return err
+	}
 
-func (db *DB) GetAllTodos() ([]Todo, error) {
-	rows, err := db.getAllTodos.Query()
+	getTodoRemindersSQL, err := loadSQL("get_todo_reminders.sql")
 	if err != nil {
-		return nil, err
+		return err
+	}
+	db.getTodoReminders, err = db.conn.Prepare(getTodoRemindersSQL)
+	if err != nil {
+		return err
 	}
-	defer rows.Close()
 
-	var todos []Todo
-	for rows.Next() {
-		var todo Todo
-		err := rows.Scan(
-			&todo.ID,
-			&todo.Title,
-			&todo.Description,
-			&todo.Done,
-			&todo.DueDate,
-			&todo.ScheduledStart,
-			&todo.ScheduledEnd,
-			&todo.CreatedAt,
-			&todo.UpdatedAt,
+	getPendingRemindersSQL, err := loadSQL("get_pending_reminders.sql")
+	if err != nil {
+		return err
+	}
+	db.getPendingReminders, err = db.conn.Prepare(getPendingRemindersSQL)
+	if err != nil {
+		return err
+	}
+
+	markReminderNotifiedSQL, err := loadSQL("mark_reminder_notified.sql")
+	if err != nil {
+		return err
+	}
+	db.markReminderNotified, err = db.conn.Prepare(markReminderNotifiedSQL)
+	if err != nil {
+		return err
+	}
+
+	deleteTodoRemindersSQL, err := loadSQL("delete_todo_reminders.sql")
+	if err != nil {
+		return err
+	}
+	db.deleteTodoReminders, err = db.conn.Prepare(deleteTodoRemindersSQL)
+	if err != nil {
+		return err
+	}
+
+	insertTimeEntrySQL, err := loadSQL("insert_time_entry.sql")
+	if err != nil {
+		return err
+	}
+	db.insertTimeEntry, err = db.conn.Prepare(insertTimeEntrySQL)
+	if err != nil {
+		return err
+	}
+
+	stopTimeEntriesSQL, err := loadSQL("stop_time_entries.sql")
+	if err != nil {
+		return err
+	}
+	db.stopTimeEntries, err = db.conn.Prepare(stopTimeEntriesSQL)
+	if err != nil {
+		return err
+	}
+
+	getRunningTimeEntrySQL, err := loadSQL("get_running_time_entry.sql")
+	if err != nil {
+		return err
+	}
+	db.getRunningTimeEntry, err = db.conn.Prepare(getRunningTimeEntrySQL)
+	if err != nil {
+		return err
+	}
+
+	getTimeEntriesSQL, err := loadSQL("get_time_entries.sql")
+	if err != nil {
+		return err
+	}
+	db.getTimeEntries, err = db.conn.Prepare(getTimeEntriesSQL)
+	if err != nil {
+		return err
+	}
+
+	deleteTodoTimeEntriesSQL, err := loadSQL("delete_todo_time_entries.sql")
+	if err != nil {
+		return err
+	}
+	db.deleteTodoTimeEntries, err = db.conn.Prepare(deleteTodoTimeEntriesSQL)
+	if err != nil {
+		return err
+	}
+
+	insertPomodoroSQL, err := loadSQL("insert_pomodoro.sql")
+	if err != nil {
+		return err
+	}
+	db.insertPomodoro, err = db.conn.Prepare(insertPomodoroSQL)
+	if err != nil {
+		return err
+	}
+
+	countTodoPomodorosSQL, err := loadSQL("count_todo_pomodoros.sql")
+	if err != nil {
+		return err
+	}
+	db.countTodoPomodoros, err = db.conn.Prepare(countTodoPomodorosSQL)
+	if err != nil {
+		return err
+	}
+
+	deleteTodoPomodorosSQL, err := loadSQL("delete_todo_pomodoros.sql")
+	if err != nil {
+		return err
+	}
+	db.deleteTodoPomodoros, err = db.conn.Prepare(deleteTodoPomodorosSQL)
+	if err != nil {
+		return err
+	}
+
+	bulkUpdateTodoSQL, err := loadSQL("bulk_update_todo.sql")
+	if err != nil {
+		return err
+	}
+	db.bulkUpdateTodo, err = db.conn.Prepare(bulkUpdateTodoSQL)
+	if err != nil {
+		return err
+	}
+
+	saveFilterSQL, err := loadSQL("save_filter.sql")
+	if err != nil {
+		return err
+	}
+	db.saveFilter, err = db.conn.Prepare(saveFilterSQL)
+	if err != nil {
+		return err
+	}
+
+	getSavedFiltersSQL, err := loadSQL("get_saved_filters.sql")
+	if err != nil {
+		return err
+	}
+	db.getSavedFilters, err = db.conn.Prepare(getSavedFiltersSQL)
+	if err != nil {
+		return err
+	}
+
+	deleteSavedFilterSQL, err := loadSQL("delete_saved_filter.sql")
+	if err != nil {
+		return err
+	}
+	db.deleteSavedFilter, err = db.conn.Prepare(deleteSavedFilterSQL)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AddTodo inserts a new todo from the given fields; ID, UUID, Done and
+// timestamps are assigned by the database
+func (db *DB) AddTodo(todo Todo) error {
+	todo.UUID = newUUID()
+	todo.Done = false
+	todo.TimeZone = db.zoneName
+	todo.DueDate = toUTC(todo.DueDate)
+	todo.ScheduledStart = toUTC(todo.ScheduledStart)
+	todo.ScheduledEnd = toUTC(todo.ScheduledEnd)
+
+	return db.withTx(func(tx *sql.Tx) error {
+		_, err := tx.Stmt(db.insertTodo).Exec(
+			todo.UUID,
+			todo.Title,
+			todo.Description,
+			todo.DueDate,
+			todo.ScheduledStart,
+			todo.ScheduledEnd,
+			todo.AllDay,
+			joinTags(todo.Tags),
+			todo.Project,
+			todo.Priority,
+			int(todo.Estimate/time.Minute),
+			todo.TimeZone,
 		)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		todos = append(todos, todo)
-	}
 
-	return todos, rows.Err()
+		return db.recordChanges(tx, todo.UUID, todoFieldValues(todo))
+	})
 }
 
-func (db *DB) GetInboxTodos() ([]Todo, error) {
-	rows, err := db.getInboxTodos.Query()
-	if err != nil {
-		return nil, err
+// toUTC converts a time to UTC for storage, so that text comparisons and
+// day boundaries don't depend on the zone it was written from
+func toUTC(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
+// localize converts a stored time into the display zone
+func (db *DB) localize(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
 	}
+	local := t.In(db.loc)
+	return &local
+}
+
+// localizeTodo converts a todo's stored times into the display zone
+func (db *DB) localizeTodo(todo *Todo) {
+	todo.DueDate = db.localize(todo.DueDate)
+	todo.ScheduledStart = db.localize(todo.ScheduledStart)
+	todo.ScheduledEnd = db.localize(todo.ScheduledEnd)
+	todo.CreatedAt = todo.CreatedAt.In(db.loc)
+	todo.UpdatedAt = todo.UpdatedAt.In(db.loc)
+}
+
+// dayStart returns midnight at the start of date's calendar day in loc
+func dayStart(date time.Time, loc *time.Location) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+}
+
+// scanTodos reads todo rows selected in the standard column order
+func (db *DB) scanTodos(rows *sql.Rows) ([]Todo, error) {
 	defer rows.Close()
 
 	var todos []Todo
 	for rows.Next() {
 		var todo Todo
+		var description sql.NullString // Older and synced rows can have none
+		var tags string
+		var estimateMinutes int
 		err := rows.Scan(
 			&todo.ID,
+			&todo.UUID,
 			&todo.Title,
-			&todo.Description,
+			&description,
 			&todo.Done,
 			&todo.DueDate,
 			&todo.ScheduledStart,
 			&todo.ScheduledEnd,
+			&todo.AllDay,
+			&tags,
+			&todo.Project,
+			&todo.Priority,
+			&estimateMinutes,
+			&todo.TimeZone,
 			&todo.CreatedAt,
 			&todo.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
 		}
+		todo.Description = description.String
+		todo.Tags = splitTags(tags)
+		todo.Estimate = time.Duration(estimateMinutes) * time.Minute
+		db.localizeTodo(&todo)
 		todos = append(todos, todo)
 	}
 
 	return todos, rows.Err()
 }
 
-func (db *DB) GetDateTodos(date time.Time) ([]Todo, error) {
-	// Format date as YYYY-MM-DD for SQLite DATE() function
-	dateStr := date.Format("2006-01-02")
+// GetTodo returns a single todo by ID
+func (db *DB) GetTodo(id int) (*Todo, error) {
+	return db.getTodoTx(nil, id)
+}
+
+// getTodoTx looks up a todo by ID, inside tx when one is given
+func (db *DB) getTodoTx(tx *sql.Tx, id int) (*Todo, error) {
+	stmt := db.getTodo
+	if tx != nil {
+		stmt = tx.Stmt(db.getTodo)
+	}
 
-	rows, err := db.getDateTodos.Query(dateStr)
+	rows, err := stmt.Query(id)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	var todos []Todo
-	for rows.Next() {
-		var todo Todo
-		err := rows.Scan(
-			&todo.ID,
-			&todo.Title,
-			&todo.Description,
-			&todo.Done,
-			&todo.DueDate,
-			&todo.ScheduledStart,
-			&todo.ScheduledEnd,
-			&todo.CreatedAt,
-			&todo.UpdatedAt,
-		)
+	todos, err := db.scanTodos(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(todos) == 0 {
+		return nil, fmt.Errorf("todo %d not found", id)
+	}
+
+	return &todos[0], nil
+}
+
+func (db *DB) GetAllTodos() ([]Todo, error) {
+	rows, err := db.getAllTodos.Query()
+	if err != nil {
+		return nil, err
+	}
+
+	return db.scanTodos(rows)
+}
+
+func (db *DB) GetInboxTodos() ([]Todo, error) {
+	rows, err := db.getInboxTodos.Query()
+	if err != nil {
+		return nil, err
+	}
+
+	return db.scanTodos(rows)
+}
+
+// GetDateTodos returns todos scheduled on the given calendar day, with the
+// day's boundaries taken in the configured zone
+func (db *DB) GetDateTodos(date time.Time) ([]Todo, error) {
+	start := dayStart(date, db.loc)
+	return db.getTodosBetween(start, start.AddDate(0, 0, 1))
+}
+
+// GetRangeTodos returns todos whose block overlaps startDate through endDate inclusive
+func (db *DB) GetRangeTodos(startDate, endDate time.Time) ([]Todo, error) {
+	return db.getTodosBetween(dayStart(startDate, db.loc), dayStart(endDate, db.loc).AddDate(0, 0, 1))
+}
+
+// GetMonthTodos returns todos scheduled in date's month
+func (db *DB) GetMonthTodos(date time.Time) ([]Todo, error) {
+	start := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, db.loc)
+	return db.getTodosBetween(start, start.AddDate(0, 1, 0))
+}
+
+// getTodosBetween returns todos whose block overlaps start up to end, so a
+// multi-day block is returned for every day it covers
+func (db *DB) getTodosBetween(start, end time.Time) ([]Todo, error) {
+	rows, err := db.getRangeTodos.Query(end.UTC(), start.UTC(), start.UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	return db.scanTodos(rows)
+}
+
+// GetDueTodos returns todos due from startDate through endDate inclusive
+func (db *DB) GetDueTodos(startDate, endDate time.Time) ([]Todo, error) {
+	start := dayStart(startDate, db.loc)
+	end := dayStart(endDate, db.loc).AddDate(0, 0, 1)
+	rows, err := db.getDueTodos.Query(start.UTC(), end.UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	return db.scanTodos(rows)
+}
+
+// GetOverdueTodos returns unfinished todos that were due before now or
+// whose block ended before now
+func (db *DB) GetOverdueTodos(now time.Time) ([]Todo, error) {
+	rows, err := db.getOverdue.Query(now.UTC(), now.UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	return db.scanTodos(rows)
+}
+
+// GetUpcomingTodos returns unfinished todos with a block or due date
+// between from and to. Blocks already under way at from are included.
+func (db *DB) GetUpcomingTodos(from, to time.Time) ([]Todo, error) {
+	rows, err := db.getUpcoming.Query(to.UTC(), from.UTC(), from.UTC(), from.UTC(), to.UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	return db.scanTodos(rows)
+}
+
+// GetSomedayTodos returns unfinished todos with neither a block nor a due
+// date
+func (db *DB) GetSomedayTodos() ([]Todo, error) {
+	rows, err := db.getSomeday.Query()
+	if err != nil {
+		return nil, err
+	}
+
+	return db.scanTodos(rows)
+}
+
+func (db *DB) UpdateTodo(id int, title, description string, dueDate, scheduledStart, scheduledEnd *time.Time, allDay bool, estimate time.Duration) error {
+	return db.withTx(func(tx *sql.Tx) error {
+		existing, err := db.getTodoTx(tx, id)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		todos = append(todos, todo)
+
+		dueDate, scheduledStart, scheduledEnd := toUTC(dueDate), toUTC(scheduledStart), toUTC(scheduledEnd)
+		_, err = tx.Stmt(db.updateTodo).Exec(title, description, dueDate, scheduledStart, scheduledEnd, allDay, int(estimate/time.Minute), db.zoneName, id)
+		if err != nil {
+			return err
+		}
+
+		updated := *existing
+		updated.Title = title
+		updated.Description = description
+		updated.DueDate = dueDate
+		updated.ScheduledStart = scheduledStart
+		updated.ScheduledEnd = scheduledEnd
+		updated.AllDay = allDay
+		updated.Estimate = estimate
+		updated.TimeZone = db.zoneName
+
+		return db.recordChanges(tx, existing.UUID, changedFieldValues(*existing, updated))
+	})
+}
+
+func (db *DB) DeleteTodo(id int) error {
+	return db.withTx(func(tx *sql.Tx) error {
+		existing, err := db.getTodoTx(tx, id)
+		if err != nil {
+			return err
+		}
+
+		return db.deleteTodoTx(tx, existing)
+	})
+}
+
+// deleteTodoTx deletes a todo and records the deletion for sync
+func (db *DB) deleteTodoTx(tx *sql.Tx, existing *Todo) error {
+	if err := db.removeTodoRows(tx, existing.ID); err != nil {
+		return err
 	}
 
-	return todos, rows.Err()
+	return db.recordChanges(tx, existing.UUID, map[string]string{fieldDeleted: "true"})
 }
 
-// GetTodayTodos is a convenience method for getting today's todos
-func (db *DB) GetTodayTodos() ([]Todo, error) {
-	return db.GetDateTodos(time.Now())
+// removeTodoRows deletes a todo along with its reminders, time entries and
+// pomodoros. Local and synced deletes both go through here.
+func (db *DB) removeTodoRows(tx *sql.Tx, id int) error {
+	_, err := tx.Stmt(db.deleteTodo).Exec(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Stmt(db.deleteTodoReminders).Exec(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Stmt(db.deleteTodoTimeEntries).Exec(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Stmt(db.deleteTodoPomodoros).Exec(id)
+	return err
 }
 
-func (db *DB) GetRangeTodos(startDate, endDate time.Time) ([]Todo, error) {
-	// Format dates as YYYY-MM-DD for SQLite DATE() function
-	startStr := startDate.Format("2006-01-02")
-	endStr := endDate.Format("2006-01-02")
+// ApplyBulkEdit makes one edit to every todo in ids. It runs in a single
+// transaction, so if any todo is missing none of them change.
+func (db *DB) ApplyBulkEdit(ids []int, edit BulkEdit) error {
+	return db.withTx(func(tx *sql.Tx) error {
+		seen := make(map[int]bool)
+		for _, id := range ids {
+			// A repeated ID is edited once, as in parseIDList
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			existing, err := db.getTodoTx(tx, id)
+			if err != nil {
+				return err
+			}
+
+			if edit.Delete {
+				if err := db.deleteTodoTx(tx, existing); err != nil {
+					return err
+				}
+				continue
+			}
+
+			updated := edit.apply(*existing)
+			updated.ScheduledStart, updated.ScheduledEnd = toUTC(updated.ScheduledStart), toUTC(updated.ScheduledEnd)
+			if edit.Block != nil {
+				updated.TimeZone = db.zoneName
+			}
+			changes := changedFieldValues(*existing, updated)
+			if len(changes) == 0 {
+				continue
+			}
+
+			_, err = tx.Stmt(db.bulkUpdateTodo).Exec(updated.Done, joinTags(updated.Tags), updated.Project, updated.ScheduledStart, updated.ScheduledEnd, updated.AllDay, updated.TimeZone, id)
+			if err != nil {
+				return err
+			}
+
+			if err := db.recordChanges(tx, existing.UUID, changes); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (db *DB) ToggleTodo(id int) error {
+	return db.withTx(func(tx *sql.Tx) error {
+		existing, err := db.getTodoTx(tx, id)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Stmt(db.toggleTodo).Exec(id)
+		if err != nil {
+			return err
+		}
+
+		return db.recordChanges(tx, existing.UUID, map[string]string{fieldDone: encodeBool(!existing.Done)})
+	})
+}
+
+// ScheduleTodo updates only the scheduled time for a todo
+func (db *DB) ScheduleTodo(id int, scheduledStart, scheduledEnd *time.Time, allDay bool) error {
+	scheduleSQL, err := loadSQL("schedule_todo.sql")
+	if err != nil {
+		return err
+	}
+
+	return db.withTx(func(tx *sql.Tx) error {
+		existing, err := db.getTodoTx(tx, id)
+		if err != nil {
+			return err
+		}
+
+		scheduledStart, scheduledEnd := toUTC(scheduledStart), toUTC(scheduledEnd)
+		_, err = tx.Exec(scheduleSQL, scheduledStart, scheduledEnd, allDay, db.zoneName, id)
+		if err != nil {
+			return err
+		}
+
+		updated := *existing
+		updated.ScheduledStart = scheduledStart
+		updated.ScheduledEnd = scheduledEnd
+		updated.AllDay = allDay
+		updated.TimeZone = db.zoneName
+
+		return db.recordChanges(tx, existing.UUID, changedFieldValues(*existing, updated))
+	})
+}
 
-	rows, err := db.getRangeTodos.Query(startStr, endStr)
+// withTx runs fn inside a transaction, committing only if it succeeds
+func (db *DB) withTx(fn func(tx *sql.Tx) error) error {
+	tx, err := db.conn.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	if db.eager {
+		return db.pushPending()
+	}
+	return nil
+}
+
+// pushPending hands unsynced changes to the backend without pulling. The
+// ops stay pending so the next sync can still detect conflicts with them.
+func (db *DB) pushPending() error {
+	pending, err := db.getPendingOps()
+	if err != nil || len(pending) == 0 {
+		return err
+	}
+
+	if err := db.backend.Push(pending); err != nil {
+		return fmt.Errorf("saved, but failed to record change: %w", err)
+	}
+	return nil
+}
+
+// AddReminder attaches a reminder to a todo
+func (db *DB) AddReminder(todoID int, reminder Reminder) error {
+	return db.withTx(func(tx *sql.Tx) error {
+		if _, err := db.getTodoTx(tx, todoID); err != nil {
+			return err
+		}
+
+		offsetMinutes := int(reminder.Offset / time.Minute)
+		_, err := tx.Stmt(db.insertReminder).Exec(todoID, string(reminder.Anchor), offsetMinutes, toUTC(reminder.RemindAt))
+		return err
+	})
+}
+
+// GetTodoReminders returns all reminders attached to a todo
+func (db *DB) GetTodoReminders(todoID int) ([]Reminder, error) {
+	rows, err := db.getTodoReminders.Query(todoID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var todos []Todo
+	var reminders []Reminder
 	for rows.Next() {
-		var todo Todo
+		var reminder Reminder
+		var anchor string
+		var offsetMinutes int
 		err := rows.Scan(
-			&todo.ID,
-			&todo.Title,
-			&todo.Description,
-			&todo.Done,
-			&todo.DueDate,
-			&todo.ScheduledStart,
-			&todo.ScheduledEnd,
-			&todo.CreatedAt,
-			&todo.UpdatedAt,
+			&reminder.ID,
+			&reminder.TodoID,
+			&anchor,
+			&offsetMinutes,
+			&reminder.RemindAt,
+			&reminder.NotifiedAt,
+			&reminder.CreatedAt,
 		)
 		if err != nil {
 			return nil, err
 		}
-		todos = append(todos, todo)
+		reminder.Anchor = ReminderAnchor(anchor)
+		reminder.Offset = time.Duration(offsetMinutes) * time.Minute
+		reminder.RemindAt = db.localize(reminder.RemindAt)
+		reminder.NotifiedAt = db.localize(reminder.NotifiedAt)
+		reminders = append(reminders, reminder)
 	}
 
-	return todos, rows.Err()
+	return reminders, rows.Err()
 }
 
-func (db *DB) GetMonthTodos(date time.Time) ([]Todo, error) {
-	// Format date as YYYY-MM-DD for SQLite strftime function
-	dateStr := date.Format("2006-01-02")
-
-	rows, err := db.getMonthTodos.Query(dateStr)
+// DueReminders returns unsent reminders for open todos whose fire time is at
+// or before now, ordered by fire time. Callers are expected to deliver them
+// and then call MarkReminderNotified.
+func (db *DB) DueReminders(now time.Time) ([]DueReminder, error) {
+	rows, err := db.getPendingReminders.Query()
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var todos []Todo
+	var pending []DueReminder
 	for rows.Next() {
-		var todo Todo
+		var item DueReminder
+		var anchor string
+		var offsetMinutes int
+		var description sql.NullString
+		var tags string
+		var estimateMinutes int
 		err := rows.Scan(
-			&todo.ID,
-			&todo.Title,
-			&todo.Description,
-			&todo.Done,
-			&todo.DueDate,
-			&todo.ScheduledStart,
-			&todo.ScheduledEnd,
-			&todo.CreatedAt,
-			&todo.UpdatedAt,
+			&item.Reminder.ID,
+			&item.Reminder.TodoID,
+			&anchor,
+			&offsetMinutes,
+			&item.Reminder.RemindAt,
+			&item.Reminder.NotifiedAt,
+			&item.Reminder.CreatedAt,
+			&item.Todo.ID,
+			&item.Todo.UUID,
+			&item.Todo.Title,
+			&description,
+			&item.Todo.Done,
+			&item.Todo.DueDate,
+			&item.Todo.ScheduledStart,
+			&item.Todo.ScheduledEnd,
+			&item.Todo.AllDay,
+			&tags,
+			&item.Todo.Project,
+			&item.Todo.Priority,
+			&estimateMinutes,
+			&item.Todo.TimeZone,
+			&item.Todo.CreatedAt,
+			&item.Todo.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
 		}
-		todos = append(todos, todo)
+		item.Reminder.Anchor = ReminderAnchor(anchor)
+		item.Todo.Description = description.String
+		item.Todo.Tags = splitTags(tags)
+		item.Todo.Estimate = time.Duration(estimateMinutes) * time.Minute
+		item.Reminder.Offset = time.Duration(offsetMinutes) * time.Minute
+		item.Reminder.RemindAt = db.localize(item.Reminder.RemindAt)
+		db.localizeTodo(&item.Todo)
+		pending = append(pending, item)
 	}
 
-	return todos, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return selectDueReminders(pending, now), nil
 }
 
-func (db *DB) UpdateTodo(id int, title, description string, dueDate, scheduledStart, scheduledEnd *time.Time) error {
-	_, err := db.updateTodo.Exec(title, description, dueDate, scheduledStart, scheduledEnd, id)
+// MarkReminderNotified records that a reminder has been delivered
+func (db *DB) MarkReminderNotified(id int, notifiedAt time.Time) error {
+	_, err := db.markReminderNotified.Exec(notifiedAt.UTC(), id)
 	return err
 }
 
-func (db *DB) DeleteTodo(id int) error {
-	_, err := db.deleteTodo.Exec(id)
+// StartTimer starts tracking time on a todo, stopping any running timer
+// first since only one can run at a time
+func (db *DB) StartTimer(todoID int, at time.Time) error {
+	return db.withTx(func(tx *sql.Tx) error {
+		if _, err := db.getTodoTx(tx, todoID); err != nil {
+			return err
+		}
+
+		_, err := tx.Stmt(db.stopTimeEntries).Exec(at.UTC())
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Stmt(db.insertTimeEntry).Exec(todoID, at.UTC())
+		return err
+	})
+}
+
+// StopTimer stops the running timer, returning the finished entry, or nil
+// if no timer was running
+func (db *DB) StopTimer(at time.Time) (*TimeEntry, error) {
+	running, err := db.GetRunningTimer()
+	if err != nil || running == nil {
+		return nil, err
+	}
+
+	_, err = db.stopTimeEntries.Exec(at.UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	stopped := at.In(db.loc)
+	running.StoppedAt = &stopped
+	return running, nil
+}
+
+// GetRunningTimer returns the running time entry, or nil if there is none
+func (db *DB) GetRunningTimer() (*TimeEntry, error) {
+	rows, err := db.getRunningTimeEntry.Query()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := db.scanTimeEntries(rows)
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+	return &entries[0], nil
+}
+
+// GetTimeEntries returns the time entries that overlap from through to,
+// including a running entry
+func (db *DB) GetTimeEntries(from, to time.Time) ([]TimeEntry, error) {
+	rows, err := db.getTimeEntries.Query(to.UTC(), from.UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	return db.scanTimeEntries(rows)
+}
+
+// scanTimeEntries reads time entry rows
+func (db *DB) scanTimeEntries(rows *sql.Rows) ([]TimeEntry, error) {
+	defer rows.Close()
+
+	var entries []TimeEntry
+	for rows.Next() {
+		var entry TimeEntry
+		err := rows.Scan(&entry.ID, &entry.TodoID, &entry.StartedAt, &entry.StoppedAt)
+		if err != nil {
+			return nil, err
+		}
+		entry.StartedAt = entry.StartedAt.In(db.loc)
+		entry.StoppedAt = db.localize(entry.StoppedAt)
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// LogPomodoro records a completed pomodoro against a todo
+func (db *DB) LogPomodoro(todoID int, startedAt, completedAt time.Time) error {
+	_, err := db.insertPomodoro.Exec(todoID, startedAt.UTC(), completedAt.UTC())
 	return err
 }
 
-func (db *DB) ToggleTodo(id int) error {
-	_, err := db.toggleTodo.Exec(id)
+// CountPomodoros returns how many pomodoros have been completed on a todo
+func (db *DB) CountPomodoros(todoID int) (int, error) {
+	var count int
+	err := db.countTodoPomodoros.QueryRow(todoID).Scan(&count)
+	return count, err
+}
+
+// FilterTodos returns the todos that pass filter
+func (db *DB) FilterTodos(filter *Filter) ([]Todo, error) {
+	filterSQL, err := loadSQL("filter_todos.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	where, args := filter.Where()
+	rows, err := db.conn.Query(fmt.Sprintf(filterSQL, where), args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.scanTodos(rows)
+}
+
+// SaveFilter stores a filter under a name, replacing any filter already
+// saved under it
+func (db *DB) SaveFilter(name, query string) error {
+	_, err := db.saveFilter.Exec(name, query)
 	return err
 }
 
-// ScheduleTodo updates only the scheduled time for a todo
-func (db *DB) ScheduleTodo(id int, scheduledStart, scheduledEnd *time.Time) error {
-	scheduleSQL, err := loadSQL("schedule_todo.sql")
+// GetSavedFilters returns the saved filters by name
+func (db *DB) GetSavedFilters() ([]SavedFilter, error) {
+	rows, err := db.getSavedFilters.Query()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var filters []SavedFilter
+	for rows.Next() {
+		var filter SavedFilter
+		if err := rows.Scan(&filter.Name, &filter.Query); err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+
+	return filters, rows.Err()
+}
+
+// DeleteFilter removes a saved filter
+func (db *DB) DeleteFilter(name string) error {
+	result, err := db.deleteSavedFilter.Exec(name)
 	if err != nil {
 		return err
 	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("no filter named %q", name)
+	}
+	return nil
+}
 
-	_, err = db.conn.Exec(scheduleSQL, scheduledStart, scheduledEnd, id)
-	return err
+// SyncEnabled reports whether the database has a remote to sync with
+func (db *DB) SyncEnabled() bool {
+	return db.syncer != nil || db.backend != nil
+}
+
+// Sync pulls changes from the primary into the embedded replica, or in
+// offline mode exchanges and merges field changes with other devices
+func (db *DB) Sync() (SyncResult, error) {
+	if db.backend != nil {
+		return db.syncOps()
+	}
+
+	if db.syncer == nil {
+		return SyncResult{}, ErrSyncNotConfigured
+	}
+
+	replicated, err := db.syncer.Sync()
+	if err != nil {
+		return SyncResult{}, fmt.Errorf("failed to sync: %w", err)
+	}
+
+	return SyncResult{FrameNo: replicated.FrameNo, FramesSynced: replicated.FramesSynced}, nil
 }
 
 func (db *DB) Close() error {
@@ -436,14 +1319,20 @@ func (db *DB) Close() error {
 	if db.getInboxTodos != nil {
 		db.getInboxTodos.Close()
 	}
-	if db.getDateTodos != nil {
-		db.getDateTodos.Close()
-	}
 	if db.getRangeTodos != nil {
 		db.getRangeTodos.Close()
 	}
-	if db.getMonthTodos != nil {
-		db.getMonthTodos.Close()
+	if db.getDueTodos != nil {
+		db.getDueTodos.Close()
+	}
+	if db.getOverdue != nil {
+		db.getOverdue.Close()
+	}
+	if db.getUpcoming != nil {
+		db.getUpcoming.Close()
+	}
+	if db.getSomeday != nil {
+		db.getSomeday.Close()
 	}
 	if db.updateTodo != nil {
 		db.updateTodo.Close()
@@ -454,6 +1343,64 @@ func (db *DB) Close() error {
 	if db.toggleTodo != nil {
 		db.toggleTodo.Close()
 	}
+	if db.getTodo != nil {
+		db.getTodo.Close()
+	}
+	if db.insertReminder != nil {
+		db.insertReminder.Close()
+	}
+	if db.getTodoReminders != nil {
+		db.getTodoReminders.Close()
+	}
+	if db.getPendingReminders != nil {
+		db.getPendingReminders.Close()
+	}
+	if db.markReminderNotified != nil {
+		db.markReminderNotified.Close()
+	}
+	if db.deleteTodoReminders != nil {
+		db.deleteTodoReminders.Close()
+	}
+	if db.insertTimeEntry != nil {
+		db.insertTimeEntry.Close()
+	}
+	if db.stopTimeEntries != nil {
+		db.stopTimeEntries.Close()
+	}
+	if db.getRunningTimeEntry != nil {
+		db.getRunningTimeEntry.Close()
+	}
+	if db.getTimeEntries != nil {
+		db.getTimeEntries.Close()
+	}
+	if db.deleteTodoTimeEntries != nil {
+		db.deleteTodoTimeEntries.Close()
+	}
+	if db.insertPomodoro != nil {
+		db.insertPomodoro.Close()
+	}
+	if db.countTodoPomodoros != nil {
+		db.countTodoPomodoros.Close()
+	}
+	if db.deleteTodoPomodoros != nil {
+		db.deleteTodoPomodoros.Close()
+	}
+	if db.bulkUpdateTodo != nil {
+		db.bulkUpdateTodo.Close()
+	}
+	if db.saveFilter != nil {
+		db.saveFilter.Close()
+	}
+	if db.getSavedFilters != nil {
+		db.getSavedFilters.Close()
+	}
+	if db.deleteSavedFilter != nil {
+		db.deleteSavedFilter.Close()
+	}
+
+	if closer, ok := db.backend.(io.Closer); ok {
+		closer.Close()
+	}
 
 	return db.conn.Close()
 }
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tursodatabase/go-libsql"
+)
+
+// newTestDB opens a fresh local database in a temporary directory
+func newTestDB(t *testing.T) *DB {
+	t.Helper()
+	return newTestDBIn(t, "UTC")
+}
+
+// newTestDBIn opens a fresh local database showing times in zone
+func newTestDBIn(t *testing.T, zone string) *DB {
+	t.Helper()
+	config := &Config{
+		DatabasePath: "file:" + filepath.Join(t.TempDir(), "tasks.db"),
+		Timezone:     zone,
+	}
+	db, err := NewDB(config)
+	if err != nil {
+		t.Fatalf("opening database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestDayBoundariesUseConfiguredZone(t *testing.T) {
+	ny := loadLocation(t, "America/New_York")
+	memory := newTestStore()
+	memory.loc = ny
+
+	for name, store := range map[string]Store{"memory": memory, "database": newTestDBIn(t, "America/New_York")} {
+		// 11pm in New York is already the next day in UTC
+		lateStart := time.Date(2026, 10, 19, 23, 0, 0, 0, ny)
+		lateEnd := lateStart.Add(time.Hour)
+		addScheduled(t, store, "Late call", &lateStart, &lateEnd)
+
+		tests := []struct {
+			query func() ([]Todo, error)
+			want  []string
+		}{
+			{query: func() ([]Todo, error) { return store.GetDateTodos(time.Date(2026, 10, 19, 12, 0, 0, 0, ny)) }, want: []string{"Late call"}},
+			{query: func() ([]Todo, error) { return store.GetDateTodos(time.Date(2026, 10, 20, 12, 0, 0, 0, ny)) }},
+			{query: func() ([]Todo, error) {
+				return store.GetRangeTodos(time.Date(2026, 10, 18, 0, 0, 0, 0, ny), time.Date(2026, 10, 19, 0, 0, 0, 0, ny))
+			}, want: []string{"Late call"}},
+			{query: func() ([]Todo, error) { return store.GetMonthTodos(time.Date(2026, 10, 1, 0, 0, 0, 0, ny)) }, want: []string{"Late call"}},
+		}
+		for i, tt := range tests {
+			todos, err := tt.query()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(titles(todos), tt.want) {
+				t.Errorf("%s query %d: got %q, want %q", name, i, titles(todos), tt.want)
+			}
+		}
+
+		todo, err := store.GetTodo(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !todo.ScheduledStart.Equal(lateStart) || todo.ScheduledStart.Location().String() != ny.String() || todo.TimeZone != "America/New_York" {
+			t.Errorf("%s: read back %v from zone %q", name, todo.ScheduledStart, todo.TimeZone)
+		}
+	}
+}
+
+func TestMigrateTimestampsToUTC(t *testing.T) {
+	db := newTestDB(t)
+
+	// A block written with an offset, before times were stored in UTC
+	_, err := db.conn.Exec(`INSERT INTO todos (uuid, title, description, scheduled_start, scheduled_end)
+		VALUES ('old', 'Late call', '', '2026-10-19T23:00:00-04:00', '2026-10-20T00:00:00-04:00')`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.conn.Exec("DELETE FROM sync_state WHERE key = ?", syncStateUTC); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.migrateTables(); err != nil {
+		t.Fatal(err)
+	}
+
+	var start string
+	if err := db.conn.QueryRow("SELECT scheduled_start FROM todos WHERE uuid = 'old'").Scan(&start); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(start, "Z") {
+		t.Errorf("stored %q, want UTC", start)
+	}
+
+	todos, err := db.GetDateTodos(day(2026, 10, 20, 12, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(todos) != 1 || !todos[0].ScheduledStart.Equal(day(2026, 10, 20, 3, 0)) {
+		t.Errorf("got %v on the UTC day of the old block", todos)
+	}
+
+	// Once done, the migration doesn't run again
+	if done, _ := getSyncState(db.conn, syncStateUTC); done == "" {
+		t.Error("migration not recorded")
+	}
+}
+
+// fakeSyncer stands in for the embedded replica connector
+type fakeSyncer struct {
+	calls  int
+	frames int   // Frames each sync applies
+	err    error // Returned instead of syncing when set
+}
+
+func (s *fakeSyncer) Sync() (libsql.Replicated, error) {
+	s.calls++
+	if s.err != nil {
+		return libsql.Replicated{}, s.err
+	}
+	return libsql.Replicated{FrameNo: s.calls * s.frames, FramesSynced: s.frames}, nil
+}
+
+func TestSyncWithoutRemote(t *testing.T) {
+	db := newTestDB(t)
+	if db.SyncEnabled() {
+		t.Error("sync enabled without a syncUrl")
+	}
+	if _, err := db.Sync(); !errors.Is(err, ErrSyncNotConfigured) {
+		t.Errorf("got %v, want ErrSyncNotConfigured", err)
+	}
+}
+
+func TestSyncReportsReplicatedFrames(t *testing.T) {
+	db := newTestDB(t)
+	syncer := &fakeSyncer{frames: 3}
+	db.syncer = syncer
+
+	for want := 1; want <= 2; want++ {
+		result, err := db.Sync()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.FramesSynced != 3 || result.FrameNo != 3*want {
+			t.Errorf("sync %d: got %+v", want, result)
+		}
+	}
+	if syncer.calls != 2 {
+		t.Errorf("got %d replica syncs, want 2", syncer.calls)
+	}
+}
+
+func TestSyncReturnsReplicaErrors(t *testing.T) {
+	db := newTestDB(t)
+	offline := errors.New("connection refused")
+	db.syncer = &fakeSyncer{err: offline}
+
+	result, err := db.Sync()
+	if !errors.Is(err, offline) || !strings.HasPrefix(err.Error(), "failed to sync") {
+		t.Errorf("got %v, want the replica error wrapped", err)
+	}
+	if result != (SyncResult{}) {
+		t.Errorf("got %+v from a failed sync", result)
+	}
+}
+
+func TestReplicaReadsItsOwnWrites(t *testing.T) {
+	db := newTestDB(t)
+	syncer := &fakeSyncer{err: errors.New("offline")}
+	db.syncer = syncer
+
+	// A write shows up straight away, without waiting on a sync that can't
+	// reach the primary
+	if err := db.AddTodo(Todo{Title: "Call mom"}); err != nil {
+		t.Fatal(err)
+	}
+	todos, err := db.GetAllTodos()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(todos) != 1 || todos[0].Title != "Call mom" {
+		t.Fatalf("got %v before syncing, want the new todo", todos)
+	}
+	if syncer.calls != 0 {
+		t.Errorf("writing synced %d times; syncing is left to the caller", syncer.calls)
+	}
+
+	// and survives the sync that follows
+	syncer.err = nil
+	if _, err := db.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if todos, _ := db.GetAllTodos(); len(todos) != 1 {
+		t.Errorf("got %d todos after syncing, want 1", len(todos))
+	}
+}
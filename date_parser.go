@@ -0,0 +1,961 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// DateParseError reports the token where a date or time expression
+// could not be understood
+type DateParseError struct {
+	Input  string
+	Offset int // Byte offset of Token within Input
+	Token  string
+	Reason string
+}
+
+func (e *DateParseError) Error() string {
+	marker := strings.Repeat(" ", e.Offset) + strings.Repeat("^", max(len(e.Token), 1))
+	return fmt.Sprintf("%s %q\n  %s\n  %s", e.Reason, e.Token, e.Input, marker)
+}
+
+// dateToken is a word of the input along with where it started
+type dateToken struct {
+	text   string // Lowercased
+	offset int
+}
+
+// clockTime is a time of day; hasMeridiem records whether am/pm was given
+type clockTime struct {
+	hour        int
+	minute      int
+	hasMeridiem bool
+}
+
+// dateExpr collects the parts recognised in a date or time expression
+type dateExpr struct {
+	date     *time.Time // Midnight of the day referred to
+	start    *clockTime
+	end      *clockTime // Set for ranges like "2-4pm"
+	duration time.Duration
+	exact    *time.Time // Absolute instants like "in 2h"
+	lastDate *time.Time // Last day of a date range like "dec 24-26"
+	allDay   bool
+
+	endToken int // Token indexes, for error reporting
+	durToken int
+}
+
+// dateParser is a small recursive descent parser over whitespace separated tokens
+type dateParser struct {
+	input  string
+	tokens []dateToken
+	pos    int
+	now    time.Time
+}
+
+// weekStart is the first day of the week for "eow" and "this weekend".
+// Like activeLocale, it is set once from the config at startup.
+var weekStart = time.Sunday
+
+// SetWeekStart changes the first day of the week used when parsing dates
+func SetWeekStart(weekday time.Weekday) {
+	weekStart = weekday
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+var monthNames = map[string]time.Month{
+	"january": time.January, "jan": time.January,
+	"february": time.February, "feb": time.February,
+	"march": time.March, "mar": time.March,
+	"april": time.April, "apr": time.April,
+	"may":  time.May,
+	"june": time.June, "jun": time.June,
+	"july": time.July, "jul": time.July,
+	"august": time.August, "aug": time.August,
+	"september": time.September, "sep": time.September, "sept": time.September,
+	"october": time.October, "oct": time.October,
+	"november": time.November, "nov": time.November,
+	"december": time.December, "dec": time.December,
+}
+
+var ordinalWords = map[string]int{
+	"first": 1, "1st": 1,
+	"second": 2, "2nd": 2,
+	"third": 3, "3rd": 3,
+	"fourth": 4, "4th": 4,
+	"fifth": 5, "5th": 5,
+	"last": -1,
+}
+
+// Named times of day
+var namedTimes = map[string]clockTime{
+	"noon":      {hour: 12, hasMeridiem: true},
+	"midday":    {hour: 12, hasMeridiem: true},
+	"midnight":  {hour: 0, hasMeridiem: true},
+	"morning":   {hour: 9, hasMeridiem: true},
+	"afternoon": {hour: 14, hasMeridiem: true},
+	"evening":   {hour: 19, hasMeridiem: true},
+	"tonight":   {hour: 20, hasMeridiem: true},
+	"eod":       {hour: 17, hasMeridiem: true},
+}
+
+var (
+	clockPattern        = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm)?$`)
+	dayNumberPattern    = regexp.MustCompile(`^(\d{1,2})(?:st|nd|rd|th)?$`)
+	dayRangePattern     = regexp.MustCompile(`^(\d{1,2})(?:st|nd|rd|th)?-(\d{1,2})(?:st|nd|rd|th)?$`)
+	yearPattern         = regexp.MustCompile(`^\d{4}$`)
+	compactDurationPart = regexp.MustCompile(`(\d+)(w|d|h|m)`)
+	compactDuration     = regexp.MustCompile(`^(?:\d+(?:w|d|h|m))+$`)
+)
+
+// tokenizeDate splits input on whitespace and commas, keeping offsets
+func tokenizeDate(input string) []dateToken {
+	var tokens []dateToken
+	start := -1
+
+	for i, r := range input {
+		if unicode.IsSpace(r) || r == ',' {
+			if start >= 0 {
+				tokens = append(tokens, dateToken{text: strings.ToLower(input[start:i]), offset: start})
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+
+	if start >= 0 {
+		tokens = append(tokens, dateToken{text: strings.ToLower(input[start:]), offset: start})
+	}
+
+	return tokens
+}
+
+func newDateParser(input string, now time.Time) *dateParser {
+	return &dateParser{input: input, tokens: tokenizeDate(input), now: now}
+}
+
+// peek returns the token n places ahead, or "" past the end
+func (p *dateParser) peek(n int) string {
+	if p.pos+n >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos+n].text
+}
+
+// errorAt builds an error pointing at the token with the given index
+func (p *dateParser) errorAt(index int, reason string) error {
+	return p.errorSpan(index, index, reason)
+}
+
+// errorSpan builds an error pointing at the tokens from first to last
+func (p *dateParser) errorSpan(first, last int, reason string) error {
+	if first >= len(p.tokens) {
+		return &DateParseError{Input: p.input, Offset: len(p.input), Reason: reason}
+	}
+	last = min(last, len(p.tokens)-1)
+	start := p.tokens[first].offset
+	end := p.tokens[last].offset + len(p.tokens[last].text)
+	return &DateParseError{
+		Input:  p.input,
+		Offset: start,
+		Token:  p.input[start:end],
+		Reason: reason,
+	}
+}
+
+// today returns midnight of the current day
+func (p *dateParser) today() time.Time {
+	return startOfDay(p.now)
+}
+
+// startOfDay returns midnight at the start of t's day
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// parse consumes every token, filling in the expression
+func (p *dateParser) parse() (*dateExpr, error) {
+	expr := &dateExpr{}
+
+	for p.pos < len(p.tokens) {
+		switch p.peek(0) {
+		case "at", "on", "by", "from":
+			p.pos++
+			continue
+		case "all-day", "allday":
+			expr.allDay = true
+			p.pos++
+			continue
+		case "all":
+			if p.peek(1) == "day" {
+				expr.allDay = true
+				p.pos += 2
+				continue
+			}
+		case "for":
+			if expr.duration != 0 {
+				return nil, p.errorAt(p.pos, "duration given twice at")
+			}
+			expr.durToken = p.pos
+			p.pos++
+			duration, err := p.parseDuration()
+			if err != nil {
+				return nil, err
+			}
+			expr.duration = duration
+			continue
+		}
+
+		index := p.pos
+		matched, err := p.parseDate(expr)
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			if _, err := p.parseLastDate(expr); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		matched, err = p.parseTime(expr)
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			continue
+		}
+
+		return nil, p.errorAt(index, "unable to parse")
+	}
+
+	return expr, nil
+}
+
+// setDate records the day, rejecting a second date in the same expression
+func (p *dateParser) setDate(expr *dateExpr, index int, date time.Time) error {
+	if expr.date != nil || expr.exact != nil {
+		return p.errorAt(index, "date given twice at")
+	}
+	expr.date = &date
+	return nil
+}
+
+// parseDate tries each date form at the current token
+func (p *dateParser) parseDate(expr *dateExpr) (bool, error) {
+	index := p.pos
+	tok := p.peek(0)
+	today := p.today()
+
+	var date time.Time
+	switch {
+	case tok == "today" || tok == "tod":
+		date = today
+	case tok == "tomorrow" || tok == "tmr" || tok == "tmrw":
+		date = today.AddDate(0, 0, 1)
+	case tok == "yesterday":
+		date = today.AddDate(0, 0, -1)
+	case tok == "eow":
+		date = endOfWeek(today)
+	case tok == "eom":
+		date = endOfMonth(today)
+	case tok == "eoy":
+		date = time.Date(today.Year(), time.December, 31, 0, 0, 0, 0, today.Location())
+	case tok == "weekend":
+		date = upcomingWeekend(today)
+	case tok == "end":
+		return p.parseEndOf(expr)
+	case tok == "in":
+		return p.parseIn(expr)
+	case tok == "this" || tok == "next":
+		return p.parseThisNext(expr)
+	case isWeekday(tok):
+		date = nextWeekday(today, weekdayNames[tok])
+	case isWeekdayRange(tok):
+		return p.parseWeekdayRange(expr)
+	case ordinalWords[tok] != 0 && isWeekday(p.peek(1)) && p.peek(2) == "of":
+		return p.parseOrdinalWeekday(expr)
+	case isMonth(tok):
+		return p.parseMonthDay(expr)
+	case dayNumberPattern.MatchString(tok) && (isMonth(p.peek(1)) || (p.peek(1) == "of" && isMonth(p.peek(2)))):
+		return p.parseDayMonth(expr)
+	default:
+		parsed, ok := parseNumericDate(tok, p.now)
+		if !ok {
+			return false, nil
+		}
+		date = parsed
+	}
+
+	p.pos++
+	return true, p.setDate(expr, index, date)
+}
+
+// setLastDate records the last day of a date range
+func (p *dateParser) setLastDate(expr *dateExpr, index int, last time.Time) error {
+	if expr.lastDate != nil {
+		return p.errorAt(index, "date range given twice at")
+	}
+	if last.Before(*expr.date) {
+		return p.errorAt(index, "date range ends before it starts at")
+	}
+	expr.lastDate = &last
+	return nil
+}
+
+// parseLastDate handles the end of a date range following a date:
+// "dec 24 - dec 26", "dec 24 to 26", "mon through wed"
+func (p *dateParser) parseLastDate(expr *dateExpr) (bool, error) {
+	switch p.peek(0) {
+	case "-", "to", "until", "till", "through", "thru":
+	default:
+		return false, nil
+	}
+
+	start := p.pos
+	index := p.pos + 1
+	tok := p.peek(1)
+	first := *expr.date
+
+	var last time.Time
+	switch {
+	case isWeekday(tok):
+		// Weekdays count forward from the first day, so "fri to mon" works
+		last = nextWeekday(first, weekdayNames[tok])
+		p.pos += 2
+	case dayNumberPattern.MatchString(tok) && !isMonth(p.peek(2)) && p.peek(2) != "of" &&
+		dayNumberPattern.MatchString(p.tokens[start-1].text):
+		// A bare day after "dec 24" stays in the same month
+		matches := dayNumberPattern.FindStringSubmatch(tok)
+		day, _ := strconv.Atoi(matches[1])
+		date, ok := makeDate(first.Year(), first.Month(), day, first.Location())
+		if !ok {
+			return false, p.errorAt(index, "no such date")
+		}
+		last = date
+		p.pos += 2
+	default:
+		p.pos++
+		end := &dateExpr{}
+
+		// A month and day without a year falls on or after the first day,
+		// so "dec 31 - jan 2" runs into the next year. Within one month a
+		// backwards range like "dec 26 - dec 24" is still an error.
+		now := p.now
+		if month, ok := p.monthAhead(); ok && month != first.Month() {
+			p.now = first
+		}
+		matched, err := p.parseDate(end)
+		p.now = now
+		if err != nil || !matched || end.date == nil {
+			// Not a date, so leave the separator for a time range
+			p.pos = start
+			return false, nil
+		}
+		last = *end.date
+	}
+
+	return true, p.setLastDate(expr, index, last)
+}
+
+// monthAhead returns the month named by a date like "jan 2" or "2nd of jan"
+// at the current token
+func (p *dateParser) monthAhead() (time.Month, bool) {
+	for _, tok := range []string{p.peek(0), p.peek(1), p.peek(2)} {
+		if month, ok := monthNames[tok]; ok {
+			return month, true
+		}
+		if !dayNumberPattern.MatchString(tok) && tok != "of" {
+			break
+		}
+	}
+	return 0, false
+}
+
+// parseWeekdayRange handles a weekday range written as one token: "mon-wed"
+func (p *dateParser) parseWeekdayRange(expr *dateExpr) (bool, error) {
+	index := p.pos
+	parts := strings.Split(p.peek(0), "-")
+	first := nextWeekday(p.today(), weekdayNames[parts[0]])
+	last := nextWeekday(first, weekdayNames[parts[1]])
+	p.pos++
+
+	if err := p.setDate(expr, index, first); err != nil {
+		return true, err
+	}
+	return true, p.setLastDate(expr, index, last)
+}
+
+// parseEndOf handles "end of week|month|year", optionally with "the"
+func (p *dateParser) parseEndOf(expr *dateExpr) (bool, error) {
+	index := p.pos
+	if p.peek(1) != "of" {
+		return false, nil
+	}
+
+	unitIndex := 2
+	if p.peek(2) == "the" {
+		unitIndex = 3
+	}
+
+	today := p.today()
+	var date time.Time
+	switch p.peek(unitIndex) {
+	case "week":
+		date = endOfWeek(today)
+	case "month":
+		date = endOfMonth(today)
+	case "year":
+		date = time.Date(today.Year(), time.December, 31, 0, 0, 0, 0, today.Location())
+	default:
+		return false, p.errorAt(p.pos+unitIndex, "expected week, month or year but got")
+	}
+
+	p.pos += unitIndex + 1
+	return true, p.setDate(expr, index, date)
+}
+
+// parseIn handles relative offsets like "in 3 days", "in 2 months" and "in 2h30m"
+func (p *dateParser) parseIn(expr *dateExpr) (bool, error) {
+	index := p.pos
+	p.pos++
+
+	// Months and years are calendar offsets rather than fixed durations
+	if amount, err := strconv.Atoi(p.peek(0)); err == nil {
+		switch strings.TrimSuffix(p.peek(1), "s") {
+		case "month", "mo":
+			p.pos += 2
+			return true, p.setDate(expr, index, p.today().AddDate(0, amount, 0))
+		case "year", "yr", "y":
+			p.pos += 2
+			return true, p.setDate(expr, index, p.today().AddDate(amount, 0, 0))
+		}
+	}
+
+	duration, err := p.parseDuration()
+	if err != nil {
+		return false, err
+	}
+
+	// Whole days stay on the calendar so they keep working across DST changes
+	if duration%(24*time.Hour) == 0 {
+		days := int(duration / (24 * time.Hour))
+		return true, p.setDate(expr, index, p.today().AddDate(0, 0, days))
+	}
+
+	if expr.date != nil || expr.exact != nil {
+		return false, p.errorAt(index, "date given twice at")
+	}
+	exact := p.now.Add(duration)
+	expr.exact = &exact
+	return true, nil
+}
+
+// parseThisNext handles "this friday", "next week", "next weekend", etc.
+func (p *dateParser) parseThisNext(expr *dateExpr) (bool, error) {
+	index := p.pos
+	next := p.peek(0) == "next"
+	unit := p.peek(1)
+	today := p.today()
+
+	var date time.Time
+	switch {
+	case isWeekday(unit):
+		date = nextWeekday(today, weekdayNames[unit])
+		if next {
+			date = date.AddDate(0, 0, 7)
+		}
+	case unit == "weekend":
+		date = upcomingWeekend(today)
+		if next {
+			date = date.AddDate(0, 0, 7)
+		}
+	case unit == "week":
+		date = today
+		if next {
+			date = today.AddDate(0, 0, 7)
+		}
+	case unit == "month":
+		date = today
+		if next {
+			date = time.Date(today.Year(), today.Month()+1, 1, 0, 0, 0, 0, today.Location())
+		}
+	case unit == "year":
+		date = today
+		if next {
+			date = time.Date(today.Year()+1, time.January, 1, 0, 0, 0, 0, today.Location())
+		}
+	case !next && namedTimes[unit] != (clockTime{}):
+		// "this morning", "this evening"
+		p.pos++
+		return p.parseTime(expr)
+	default:
+		return false, p.errorAt(p.pos+1, "expected a weekday, week, month or year but got")
+	}
+
+	p.pos += 2
+	return true, p.setDate(expr, index, date)
+}
+
+// parseOrdinalWeekday handles "first monday of june", "last friday of next month"
+func (p *dateParser) parseOrdinalWeekday(expr *dateExpr) (bool, error) {
+	index := p.pos
+	ordinal := ordinalWords[p.peek(0)]
+	weekday := weekdayNames[p.peek(1)]
+	p.pos += 3 // ordinal, weekday, "of"
+
+	today := p.today()
+	year := today.Year()
+	var month time.Month
+	rollover := false // Named months without a year move to next year once passed
+
+	switch tok := p.peek(0); {
+	case tok == "month" || (tok == "this" && p.peek(1) == "month"):
+		month = today.Month()
+		if tok == "this" {
+			p.pos++
+		}
+		p.pos++
+	case tok == "next" && p.peek(1) == "month":
+		next := time.Date(today.Year(), today.Month()+1, 1, 0, 0, 0, 0, today.Location())
+		year, month = next.Year(), next.Month()
+		p.pos += 2
+	case isMonth(tok):
+		month = monthNames[tok]
+		p.pos++
+		rollover = true
+		if yearPattern.MatchString(p.peek(0)) {
+			year, _ = strconv.Atoi(p.peek(0))
+			rollover = false
+			p.pos++
+		}
+	default:
+		return false, p.errorAt(p.pos, "expected a month but got")
+	}
+
+	date, ok := nthWeekdayOfMonth(year, month, weekday, ordinal, today.Location())
+	if ok && rollover && date.Before(today) {
+		date, ok = nthWeekdayOfMonth(year+1, month, weekday, ordinal, today.Location())
+	}
+	if !ok {
+		return false, p.errorSpan(index, p.pos-1, "no such date")
+	}
+
+	return true, p.setDate(expr, index, date)
+}
+
+// parseMonthDay handles "jun 5", "june 5th", "jun 5 2026" and day
+// ranges like "dec 24-26"
+func (p *dateParser) parseMonthDay(expr *dateExpr) (bool, error) {
+	index := p.pos
+	month := monthNames[p.peek(0)]
+
+	if matches := dayRangePattern.FindStringSubmatch(p.peek(1)); matches != nil {
+		day, _ := strconv.Atoi(matches[1])
+		lastDay, _ := strconv.Atoi(matches[2])
+		p.pos += 2
+
+		if _, err := p.finishMonthDay(expr, index, month, day); err != nil {
+			return true, err
+		}
+		first := *expr.date
+		last, ok := makeDate(first.Year(), first.Month(), lastDay, first.Location())
+		if !ok {
+			return true, p.errorSpan(index, p.pos-1, "no such date")
+		}
+		return true, p.setLastDate(expr, index+1, last)
+	}
+
+	matches := dayNumberPattern.FindStringSubmatch(p.peek(1))
+	if matches == nil {
+		return false, p.errorAt(p.pos+1, "expected a day of the month but got")
+	}
+	day, _ := strconv.Atoi(matches[1])
+	p.pos += 2
+
+	return p.finishMonthDay(expr, index, month, day)
+}
+
+// parseDayMonth handles "5 june", "5th of june 2026"
+func (p *dateParser) parseDayMonth(expr *dateExpr) (bool, error) {
+	index := p.pos
+	matches := dayNumberPattern.FindStringSubmatch(p.peek(0))
+	day, _ := strconv.Atoi(matches[1])
+	p.pos++
+
+	if p.peek(0) == "of" {
+		p.pos++
+	}
+	month := monthNames[p.peek(0)]
+	p.pos++
+
+	return p.finishMonthDay(expr, index, month, day)
+}
+
+// finishMonthDay reads an optional year and rolls past dates into next year
+func (p *dateParser) finishMonthDay(expr *dateExpr, index int, month time.Month, day int) (bool, error) {
+	today := p.today()
+	year := today.Year()
+	explicitYear := false
+
+	if yearPattern.MatchString(p.peek(0)) {
+		year, _ = strconv.Atoi(p.peek(0))
+		explicitYear = true
+		p.pos++
+	}
+
+	date, ok := makeDate(year, month, day, today.Location())
+
+	// Without a year, take the next time the date comes round: feb 29
+	// may be several years away
+	for next := year + 1; !explicitYear && (!ok || date.Before(today)) && next <= year+8; next++ {
+		date, ok = makeDate(next, month, day, today.Location())
+	}
+	if !ok {
+		return false, p.errorSpan(index, p.pos-1, "no such date")
+	}
+
+	return true, p.setDate(expr, index, date)
+}
+
+// parseTime handles times of day and ranges: "3pm", "15:30", "noon", "2-4pm", "9am to 11am"
+func (p *dateParser) parseTime(expr *dateExpr) (bool, error) {
+	index := p.pos
+	tok := p.peek(0)
+
+	// A range written as a single token, e.g. "2-4pm" or "2pm-3:30pm"
+	if parts := strings.Split(tok, "-"); len(parts) == 2 {
+		start, startOK := p.clockAt(parts[0], "")
+		end, endOK := p.clockAt(parts[1], "")
+		if !startOK || !endOK {
+			return false, nil
+		}
+		p.pos++
+		return true, p.setRange(expr, index, start, end)
+	}
+
+	start, ok := p.clockAt(tok, p.peek(1))
+	if !ok {
+		return false, nil
+	}
+	p.pos++
+	if p.peek(0) == "am" || p.peek(0) == "pm" {
+		p.pos++
+	}
+
+	// A range written with a separator, e.g. "2pm - 4pm" or "9 to 11am"
+	switch p.peek(0) {
+	case "-", "to", "until", "till":
+		endIndex := p.pos + 1
+		end, ok := p.clockAt(p.peek(1), p.peek(2))
+		if !ok {
+			return false, p.errorAt(endIndex, "expected an end time but got")
+		}
+		p.pos += 2
+		if p.peek(0) == "am" || p.peek(0) == "pm" {
+			p.pos++
+		}
+		return true, p.setRange(expr, index, start, end)
+	}
+
+	if expr.start != nil {
+		return false, p.errorAt(index, "time given twice at")
+	}
+	expr.start = &start
+	return true, nil
+}
+
+// setRange records a start and end time, sharing am/pm from the end when
+// only it was given ("2-4pm" is 2pm to 4pm, "11-1pm" is 11am to 1pm)
+func (p *dateParser) setRange(expr *dateExpr, index int, start, end clockTime) error {
+	if expr.start != nil {
+		return p.errorAt(index, "time given twice at")
+	}
+
+	if !start.hasMeridiem && end.hasMeridiem && start.hour <= 12 {
+		hour := start.hour % 12
+		if end.hour >= 12 {
+			hour += 12
+		}
+		if hour*60+start.minute > end.hour*60+end.minute {
+			hour = (hour + 12) % 24
+		}
+		start.hour = hour
+		start.hasMeridiem = true
+	}
+
+	expr.start = &start
+	expr.end = &end
+	expr.endToken = index
+	return nil
+}
+
+// clockAt parses a time of day from a token, using a following "am"/"pm"
+// token if the time does not carry its own
+func (p *dateParser) clockAt(tok, next string) (clockTime, bool) {
+	if named, ok := namedTimes[tok]; ok {
+		return named, true
+	}
+
+	matches := clockPattern.FindStringSubmatch(tok)
+	if matches == nil {
+		return clockTime{}, false
+	}
+
+	hour, _ := strconv.Atoi(matches[1])
+	minute := 0
+	if matches[2] != "" {
+		minute, _ = strconv.Atoi(matches[2])
+	}
+	meridiem := matches[3]
+	if meridiem == "" && (next == "am" || next == "pm") {
+		meridiem = next
+	}
+
+	if minute > 59 || hour > 23 {
+		return clockTime{}, false
+	}
+
+	switch meridiem {
+	case "am", "pm":
+		if hour < 1 || hour > 12 {
+			return clockTime{}, false
+		}
+		if meridiem == "pm" && hour != 12 {
+			hour += 12
+		} else if meridiem == "am" && hour == 12 {
+			hour = 0
+		}
+		return clockTime{hour: hour, minute: minute, hasMeridiem: true}, true
+	}
+
+	// Only "H:MM" or a bare hour without am/pm
+	return clockTime{hour: hour, minute: minute, hasMeridiem: matches[2] != "" && hour > 12}, true
+}
+
+// resolve applies the smart default for bare hours: 1-7 are afternoon
+func (c clockTime) resolve() clockTime {
+	if !c.hasMeridiem && c.hour >= 1 && c.hour <= 7 {
+		c.hour += 12
+	}
+	return c
+}
+
+// on returns the time of day on the given date. A time skipped when the
+// clocks go forward is read with the offset from before the change, so
+// 2:30am becomes 3:30am.
+func (c clockTime) on(date time.Time) time.Time {
+	t := time.Date(date.Year(), date.Month(), date.Day(), c.hour, c.minute, 0, 0, date.Location())
+	if t.Hour() == c.hour && t.Minute() == c.minute {
+		return t
+	}
+
+	_, offset := t.Add(-6 * time.Hour).Zone()
+	wall := time.Date(date.Year(), date.Month(), date.Day(), c.hour, c.minute, 0, 0, time.UTC)
+	return wall.Add(-time.Duration(offset) * time.Second).In(date.Location())
+}
+
+// parseDuration reads "2h30m", "90 min", "1 hour 30 minutes", "3 days"
+func (p *dateParser) parseDuration() (time.Duration, error) {
+	index := p.pos
+	tok := p.peek(0)
+
+	if compactDuration.MatchString(tok) {
+		p.pos++
+		return parseCompactDuration(tok), nil
+	}
+
+	var total time.Duration
+	for {
+		amount, err := strconv.Atoi(p.peek(0))
+		if err != nil {
+			break
+		}
+		unit, ok := durationUnit(p.peek(1))
+		if !ok {
+			if total > 0 {
+				break
+			}
+			return 0, p.errorAt(p.pos+1, "expected a unit like minutes, hours or days but got")
+		}
+		total += time.Duration(amount) * unit
+		p.pos += 2
+
+		if p.peek(0) == "and" {
+			p.pos++
+		}
+	}
+
+	if total == 0 {
+		return 0, p.errorAt(index, "expected a duration like 2h30m but got")
+	}
+
+	return total, nil
+}
+
+// ParseDuration parses human durations like "2h30m", "90 min" or "1 hour 30 minutes"
+func ParseDuration(input string) (time.Duration, error) {
+	p := newDateParser(strings.TrimSpace(input), time.Time{})
+	duration, err := p.parseDuration()
+	if err != nil {
+		return 0, err
+	}
+	if p.pos < len(p.tokens) {
+		return 0, p.errorAt(p.pos, "unable to parse")
+	}
+	return duration, nil
+}
+
+// parseCompactDuration sums the parts of a duration like "1d2h30m"
+func parseCompactDuration(tok string) time.Duration {
+	var total time.Duration
+	for _, part := range compactDurationPart.FindAllStringSubmatch(tok, -1) {
+		amount, _ := strconv.Atoi(part[1])
+		unit, _ := durationUnit(part[2])
+		total += time.Duration(amount) * unit
+	}
+	return total
+}
+
+// durationUnit maps a unit word to its length
+func durationUnit(word string) (time.Duration, bool) {
+	switch strings.TrimSuffix(word, "s") {
+	case "minute", "min", "m":
+		return time.Minute, true
+	case "hour", "hr", "h":
+		return time.Hour, true
+	case "day", "d":
+		return 24 * time.Hour, true
+	case "week", "wk", "w":
+		return 7 * 24 * time.Hour, true
+	}
+	return 0, false
+}
+
+// parseNumericDate parses "2024-12-25", "2024/12/25", "12/25/2024", "12/25"
+func parseNumericDate(tok string, now time.Time) (time.Time, bool) {
+	formats := []string{"2006-01-02", "2006/01/02", "1/2/2006"}
+	for _, format := range formats {
+		if parsed, err := time.ParseInLocation(format, tok, now.Location()); err == nil {
+			return parsed, true
+		}
+	}
+
+	if parsed, err := time.ParseInLocation("1/2", tok, now.Location()); err == nil {
+		date, ok := makeDate(now.Year(), parsed.Month(), parsed.Day(), now.Location())
+		if !ok {
+			return time.Time{}, false
+		}
+		if date.Before(startOfDay(now)) {
+			date, _ = makeDate(now.Year()+1, parsed.Month(), parsed.Day(), now.Location())
+		}
+		return date, true
+	}
+
+	return time.Time{}, false
+}
+
+// makeDate builds a date, rejecting days that overflow the month
+func makeDate(year int, month time.Month, day int, loc *time.Location) (time.Time, bool) {
+	date := time.Date(year, month, day, 0, 0, 0, 0, loc)
+	return date, date.Month() == month && date.Day() == day
+}
+
+// nthWeekdayOfMonth finds e.g. the 2nd Tuesday (n=2) or last Friday (n=-1) of a month
+func nthWeekdayOfMonth(year int, month time.Month, weekday time.Weekday, n int, loc *time.Location) (time.Time, bool) {
+	if n < 0 {
+		last := time.Date(year, month+1, 0, 0, 0, 0, 0, loc)
+		back := (int(last.Weekday()) - int(weekday) + 7) % 7
+		return last.AddDate(0, 0, -back), true
+	}
+
+	first := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	ahead := (int(weekday) - int(first.Weekday()) + 7) % 7
+	date := first.AddDate(0, 0, ahead+7*(n-1))
+	return date, date.Month() == month
+}
+
+// nextWeekday returns the next given weekday on or after date
+func nextWeekday(date time.Time, weekday time.Weekday) time.Time {
+	daysUntil := (int(weekday) - int(date.Weekday()) + 7) % 7
+	return date.AddDate(0, 0, daysUntil)
+}
+
+// upcomingWeekend returns the Saturday of this weekend, or today on a
+// Sunday that ends the week. When weeks start on Sunday, a Sunday looks
+// ahead to the coming Saturday instead.
+func upcomingWeekend(date time.Time) time.Time {
+	if date.Weekday() == time.Sunday && weekStart != time.Sunday {
+		return date
+	}
+	return nextWeekday(date, time.Saturday)
+}
+
+// endOfWeek returns the last day of date's week, the day before weekStart
+func endOfWeek(date time.Time) time.Time {
+	return nextWeekday(date, (weekStart+6)%7)
+}
+
+// endOfMonth returns the last day of date's month
+func endOfMonth(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month()+1, 0, 0, 0, 0, 0, date.Location())
+}
+
+func isWeekday(tok string) bool {
+	_, ok := weekdayNames[tok]
+	return ok
+}
+
+// isWeekdayRange reports whether tok is a range like "mon-wed"
+func isWeekdayRange(tok string) bool {
+	parts := strings.Split(tok, "-")
+	return len(parts) == 2 && isWeekday(parts[0]) && isWeekday(parts[1])
+}
+
+func isMonth(tok string) bool {
+	_, ok := monthNames[tok]
+	return ok
+}
+
+// parseDateExpr parses input into its parts, relative to now
+func parseDateExpr(input string, now time.Time) (*dateExpr, *dateParser, error) {
+	p := newDateParser(strings.TrimSpace(input), now)
+	if len(p.tokens) == 0 {
+		return nil, p, fmt.Errorf("date is required")
+	}
+
+	expr, err := p.parse()
+	if err != nil {
+		return nil, p, err
+	}
+
+	return expr, p, nil
+}
+
+// day returns the date the expression refers to, defaulting to today
+func (e *dateExpr) day(now time.Time) time.Time {
+	if e.date != nil {
+		return *e.date
+	}
+	if e.exact != nil {
+		return startOfDay(*e.exact)
+	}
+	return startOfDay(now)
+}
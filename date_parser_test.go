@@ -0,0 +1,115 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDateGrammar(t *testing.T) {
+	// testNow is Monday 19 October 2026 at 10am
+	tests := []struct {
+		input string
+		want  time.Time
+	}{
+		{input: "in 3 days", want: endOfDayUTC(2026, 10, 22)},
+		{input: "in 2 weeks", want: endOfDayUTC(2026, 11, 2)},
+		{input: "in 2h30m", want: day(2026, 10, 19, 12, 30)},
+		{input: "next friday", want: endOfDayUTC(2026, 10, 30)},
+		{input: "this friday", want: endOfDayUTC(2026, 10, 23)},
+		{input: "fri", want: endOfDayUTC(2026, 10, 23)},
+		{input: "monday", want: endOfDayUTC(2026, 10, 19)},
+		{input: "end of month", want: endOfDayUTC(2026, 10, 31)},
+		{input: "end of week", want: endOfDayUTC(2026, 10, 24)},
+		{input: "eod", want: day(2026, 10, 19, 17, 0)},
+		{input: "first monday of june", want: endOfDayUTC(2027, 6, 7)},
+		{input: "last friday of november", want: endOfDayUTC(2026, 11, 27)},
+		{input: "third tuesday of october", want: endOfDayUTC(2026, 10, 20)},
+		{input: "this weekend", want: endOfDayUTC(2026, 10, 24)},
+		{input: "noon", want: day(2026, 10, 19, 12, 0)},
+		{input: "tonight", want: day(2026, 10, 19, 20, 0)},
+		{input: "5pm", want: day(2026, 10, 19, 17, 0)},
+		{input: "at 3", want: day(2026, 10, 19, 15, 0)},
+		{input: "tomorrow at 9:30am", want: day(2026, 10, 20, 9, 30)},
+		{input: "dec 25 at noon", want: day(2026, 12, 25, 12, 0)},
+		{input: "25th december", want: endOfDayUTC(2026, 12, 25)},
+		{input: "12/25", want: endOfDayUTC(2026, 12, 25)},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDueDate(tt.input, NewFixedClock(testNow))
+		if err != nil {
+			t.Errorf("ParseDueDate(%q): %v", tt.input, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseDueDate(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestTimeBlockGrammar(t *testing.T) {
+	tests := []struct {
+		input      string
+		start, end time.Time
+	}{
+		{input: "fri 2-4pm", start: day(2026, 10, 23, 14, 0), end: day(2026, 10, 23, 16, 0)},
+		{input: "11-1pm", start: day(2026, 10, 19, 11, 0), end: day(2026, 10, 19, 13, 0)},
+		{input: "wed 9am to 11am", start: day(2026, 10, 21, 9, 0), end: day(2026, 10, 21, 11, 0)},
+		{input: "noon for 1 hour", start: day(2026, 10, 19, 12, 0), end: day(2026, 10, 19, 13, 0)},
+		{input: "tomorrow 9am for 2h30m", start: day(2026, 10, 20, 9, 0), end: day(2026, 10, 20, 11, 30)},
+		{input: "first monday of june 10am-noon", start: day(2027, 6, 7, 10, 0), end: day(2027, 6, 7, 12, 0)},
+		{input: "fri 11pm to 1am", start: day(2026, 10, 23, 23, 0), end: day(2026, 10, 24, 1, 0)},
+	}
+
+	for _, tt := range tests {
+		block, err := ParseTimeBlock(tt.input, NewFixedClock(testNow))
+		if err != nil {
+			t.Errorf("ParseTimeBlock(%q): %v", tt.input, err)
+			continue
+		}
+		if !block.Start.Equal(tt.start) || !block.End.Equal(tt.end) {
+			t.Errorf("ParseTimeBlock(%q) = %v - %v, want %v - %v", tt.input, block.Start, block.End, tt.start, tt.end)
+		}
+	}
+}
+
+func TestDateParseErrorPointsAtToken(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{
+			input: "feb 30",
+			want:  "no such date \"feb 30\"\n  feb 30\n  ^^^^^^",
+		},
+		{
+			input: "tomorrow 25pm",
+			want:  "unable to parse \"25pm\"\n  tomorrow 25pm\n           ^^^^",
+		},
+		{
+			input: "next blursday",
+			want:  "expected a weekday, week, month or year but got \"blursday\"\n  next blursday\n       ^^^^^^^^",
+		},
+		{
+			input: "in three days",
+			want:  "expected a duration like 2h30m but got \"three\"\n  in three days\n     ^^^^^",
+		},
+		{
+			input: "first monday of smarch",
+			want:  "expected a month but got \"smarch\"\n  first monday of smarch\n                  ^^^^^^",
+		},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseDueDate(tt.input, NewFixedClock(testNow))
+		var parseErr *DateParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("ParseDueDate(%q) = %v, want a DateParseError", tt.input, err)
+			continue
+		}
+		if got := parseErr.Error(); got != tt.want {
+			t.Errorf("ParseDueDate(%q) error:\n%s\nwant:\n%s", tt.input, got, tt.want)
+		}
+	}
+}
@@ -2,131 +2,104 @@ package main
 
 import (
 	"fmt"
-	"regexp"
-	"strconv"
 	"strings"
 	"time"
 )
 
 // ParseDueDate parses a due date string into a time.Time pointer
 // Supports formats: "2024-12-25", "Dec 25", "tomorrow", "next week", etc.
-func ParseDueDate(dateStr string) (*time.Time, error) {
-	if dateStr == "" {
+func ParseDueDate(dateStr string, clock Clock) (*time.Time, error) {
+	return parseDueDate(dateStr, clock.Now())
+}
+
+// parseDueDate parses a due date relative to now. A date without a time
+// means the end of that day; a time without a date means today.
+func parseDueDate(dateStr string, now time.Time) (*time.Time, error) {
+	if strings.TrimSpace(dateStr) == "" {
 		return nil, nil
 	}
 
-	dateStr = strings.TrimSpace(strings.ToLower(dateStr))
-	now := time.Now()
-
-	// Handle relative dates
-	switch dateStr {
-	case "today":
-		date := time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 0, now.Location())
-		return &date, nil
-	case "tomorrow":
-		date := now.AddDate(0, 0, 1)
-		date = time.Date(date.Year(), date.Month(), date.Day(), 23, 59, 59, 0, date.Location())
-		return &date, nil
-	case "next week":
-		date := now.AddDate(0, 0, 7)
-		date = time.Date(date.Year(), date.Month(), date.Day(), 23, 59, 59, 0, date.Location())
-		return &date, nil
+	expr, p, err := parseDateExpr(dateStr, now)
+	if err != nil {
+		return nil, err
 	}
 
-	// Try standard date formats
-	formats := []string{
-		"2006-01-02",      // YYYY-MM-DD
-		"2006/01/02",      // YYYY/MM/DD
-		"01/02/2006",      // MM/DD/YYYY
-		"Jan 2, 2006",     // Jan 2, 2006
-		"January 2, 2006", // January 2, 2006
-		"2 Jan 2006",      // 2 Jan 2006
-		"2 January 2006",  // 2 January 2006
+	if expr.end != nil {
+		return nil, p.errorAt(expr.endToken, "due dates can't be a range:")
 	}
-
-	for _, format := range formats {
-		if parsed, err := time.Parse(format, dateStr); err == nil {
-			// Set to end of day
-			date := time.Date(parsed.Year(), parsed.Month(), parsed.Day(), 23, 59, 59, 0, now.Location())
-			return &date, nil
-		}
+	if expr.duration != 0 {
+		return nil, p.errorAt(expr.durToken, "due dates can't have a duration:")
+	}
+	if expr.lastDate != nil {
+		return nil, fmt.Errorf("due dates can't span several days")
 	}
 
-	// Try month/day without year (assume current year)
-	monthDayFormats := []string{
-		"Jan 2",     // Jan 2
-		"January 2", // January 2
-		"01/02",     // MM/DD
-		"1/2",       // M/D
+	if expr.exact != nil {
+		return expr.exact, nil
 	}
 
-	for _, format := range monthDayFormats {
-		if parsed, err := time.Parse(format, dateStr); err == nil {
-			// Use current year and set to end of day
-			date := time.Date(now.Year(), parsed.Month(), parsed.Day(), 23, 59, 59, 0, now.Location())
-			// If the date has already passed this year, use next year
-			if date.Before(now) {
-				date = date.AddDate(1, 0, 0)
-			}
-			return &date, nil
-		}
+	day := expr.day(now)
+	if expr.start == nil {
+		date := time.Date(day.Year(), day.Month(), day.Day(), 23, 59, 59, 0, day.Location())
+		return &date, nil
 	}
 
-	return nil, fmt.Errorf("unable to parse date: %s", dateStr)
+	date := expr.start.resolve().on(day)
+	return &date, nil
 }
 
 // FormatDueDate formats a due date for display
-func FormatDueDate(dueDate *time.Time) string {
+func FormatDueDate(dueDate *time.Time, clock Clock) string {
 	if dueDate == nil {
 		return ""
 	}
 
-	now := time.Now()
+	now := clock.Now()
 	diff := dueDate.Sub(now)
 
 	// If it's today
 	if dueDate.Year() == now.Year() && dueDate.YearDay() == now.YearDay() {
-		return "Today"
+		return activeLocale.Today
 	}
 
 	// If it's tomorrow
 	tomorrow := now.AddDate(0, 0, 1)
 	if dueDate.Year() == tomorrow.Year() && dueDate.YearDay() == tomorrow.YearDay() {
-		return "Tomorrow"
+		return activeLocale.Tomorrow
 	}
 
-	// If it's overdue
+	// If it's overdue, counting calendar days so yesterday evening is a day ago
 	if diff < 0 {
-		days := int(-diff.Hours() / 24)
+		days := int(startOfDay(now).Sub(startOfDay(*dueDate)).Round(24*time.Hour) / (24 * time.Hour))
 		if days == 1 {
-			return "1 day overdue"
+			return activeLocale.OneDayOverdue
 		}
-		return fmt.Sprintf("%d days overdue", days)
+		return fmt.Sprintf(activeLocale.DaysOverdue, days)
 	}
 
 	// If it's within a week
 	if diff < 7*24*time.Hour {
 		days := int(diff.Hours() / 24)
 		if days == 1 {
-			return "In 1 day"
+			return activeLocale.InOneDay
 		}
-		return fmt.Sprintf("In %d days", days)
+		return fmt.Sprintf(activeLocale.InDays, days)
 	}
 
 	// Otherwise show the date
 	if dueDate.Year() == now.Year() {
-		return dueDate.Format("Jan 2")
+		return activeLocale.Date(*dueDate)
 	}
-	return dueDate.Format("Jan 2, 2006")
+	return activeLocale.DateYear(*dueDate)
 }
 
 // GetDueDateColor returns appropriate color for due date status
-func GetDueDateColor(dueDate *time.Time) string {
+func GetDueDateColor(dueDate *time.Time, clock Clock) string {
 	if dueDate == nil {
 		return ColorGray
 	}
 
-	now := time.Now()
+	now := clock.Now()
 	diff := dueDate.Sub(now)
 
 	// Overdue - red
@@ -154,206 +127,142 @@ func GetDueDateColor(dueDate *time.Time) string {
 	return ColorGray
 }
 
-// TimeBlock represents a scheduled time block
+// TimeBlock represents a scheduled time block. All-day blocks run from
+// midnight of their first day to midnight after their last.
 type TimeBlock struct {
-	Start *time.Time
-	End   *time.Time
+	Start  *time.Time
+	End    *time.Time
+	AllDay bool
 }
 
 // ParseTimeBlock parses time block expressions like:
-// "Monday 2pm-4pm", "Dec 25 9am-11am", "tomorrow 3pm for 2 hours"
-func ParseTimeBlock(input string) (*TimeBlock, error) {
+// "Monday 2pm-4pm", "fri 2-4pm", "tomorrow 3pm for 2h30m", "noon for 1 hour".
+// A time without a date means today. Dates without times make all-day
+// blocks: "friday all day", "dec 24-26", "mon to wed", "sat for 2 days".
+func ParseTimeBlock(input string, clock Clock) (*TimeBlock, error) {
+	return ParseTimeBlockFor(input, clock, 0)
+}
+
+// ParseTimeBlockFor parses a time block like ParseTimeBlock, except that a
+// start time alone ("tomorrow 2pm") lasts for estimate, if it is set.
+func ParseTimeBlockFor(input string, clock Clock, estimate time.Duration) (*TimeBlock, error) {
 	if input == "" {
 		return nil, nil
 	}
 
-	input = strings.TrimSpace(input)
-	now := time.Now()
-
-	// Pattern: "DAY TIME-TIME" or "DATE TIME-TIME"
-	timeRangePattern := regexp.MustCompile(`^(.+?)\s+(\d{1,2}(?::\d{2})?(?:am|pm)?)-(\d{1,2}(?::\d{2})?(?:am|pm)?)$`)
-
-	// Pattern: "DAY TIME for DURATION"
-	durationPattern := regexp.MustCompile(`^(.+?)\s+(\d{1,2}(?::\d{2})?(?:am|pm)?)\s+for\s+(\d+)\s*(hour|hr|h|minute|min|m)s?$`)
-
-	if matches := timeRangePattern.FindStringSubmatch(input); matches != nil {
-		datePart := strings.TrimSpace(matches[1])
-		startTime := strings.TrimSpace(matches[2])
-		endTime := strings.TrimSpace(matches[3])
-
-		baseDate, err := parseBaseDate(datePart, now)
-		if err != nil {
-			return nil, err
-		}
-
-		start, err := parseTimeOnDate(startTime, baseDate)
-		if err != nil {
-			return nil, err
-		}
-
-		end, err := parseTimeOnDate(endTime, baseDate)
-		if err != nil {
-			return nil, err
-		}
-
-		// Handle overnight times (end < start means next day)
-		if end.Before(*start) {
-			nextDay := end.AddDate(0, 0, 1)
-			end = &nextDay
-		}
-
-		return &TimeBlock{Start: start, End: end}, nil
-	}
-
-	if matches := durationPattern.FindStringSubmatch(input); matches != nil {
-		datePart := strings.TrimSpace(matches[1])
-		startTime := strings.TrimSpace(matches[2])
-		durationValue := matches[3]
-		durationUnit := matches[4]
-
-		baseDate, err := parseBaseDate(datePart, now)
-		if err != nil {
-			return nil, err
-		}
-
-		start, err := parseTimeOnDate(startTime, baseDate)
-		if err != nil {
-			return nil, err
-		}
-
-		duration, err := strconv.Atoi(durationValue)
-		if err != nil {
-			return nil, err
-		}
-
-		var durationTime time.Duration
-		switch strings.ToLower(string(durationUnit[0])) {
-		case "h":
-			durationTime = time.Duration(duration) * time.Hour
-		case "m":
-			durationTime = time.Duration(duration) * time.Minute
-		default:
-			return nil, fmt.Errorf("unsupported duration unit: %s", durationUnit)
-		}
-
-		end := start.Add(durationTime)
-		return &TimeBlock{Start: start, End: &end}, nil
+	now := clock.Now()
+	expr, p, err := parseDateExpr(input, now)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, fmt.Errorf("unable to parse time block: %s", input)
-}
-
-// parseBaseDate parses the date part (Monday, tomorrow, Dec 25, etc.)
-func parseBaseDate(datePart string, now time.Time) (*time.Time, error) {
-	datePart = strings.ToLower(datePart)
-
-	// Handle relative dates
-	switch datePart {
-	case "today":
-		date := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-		return &date, nil
-	case "tomorrow":
-		date := now.AddDate(0, 0, 1)
-		date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
-		return &date, nil
+	if expr.allDay || expr.lastDate != nil || (expr.start == nil && expr.exact == nil && isWholeDays(expr.duration)) {
+		return allDayBlock(expr, p, now)
 	}
 
-	// Handle weekdays
-	weekdays := map[string]time.Weekday{
-		"sunday": time.Sunday, "sun": time.Sunday,
-		"monday": time.Monday, "mon": time.Monday,
-		"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
-		"wednesday": time.Wednesday, "wed": time.Wednesday,
-		"thursday": time.Thursday, "thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday,
-		"friday": time.Friday, "fri": time.Friday,
-		"saturday": time.Saturday, "sat": time.Saturday,
+	var start time.Time
+	switch {
+	case expr.exact != nil && expr.start == nil:
+		start = *expr.exact
+	case expr.start != nil:
+		start = expr.start.resolve().on(expr.day(now))
+	default:
+		return nil, fmt.Errorf("time block needs a start time, e.g. %q", "tomorrow 2pm-3pm")
 	}
 
-	if weekday, exists := weekdays[datePart]; exists {
-		daysUntil := (int(weekday) - int(now.Weekday()) + 7) % 7
-		if daysUntil == 0 && now.Hour() >= 12 { // If it's the same day but afternoon, assume next week
-			daysUntil = 7
+	var end time.Time
+	switch {
+	case expr.end != nil && expr.duration != 0:
+		return nil, p.errorAt(expr.durToken, "time block has both an end time and a duration:")
+	case expr.end != nil:
+		end = expr.end.resolve().on(start)
+		// Handle overnight times (end < start means next day)
+		if end.Before(start) {
+			end = expr.end.resolve().on(start.AddDate(0, 0, 1))
 		}
-		date := now.AddDate(0, 0, daysUntil)
-		date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
-		return &date, nil
+	case expr.duration != 0:
+		end = start.Add(expr.duration)
+	case estimate > 0:
+		end = start.Add(estimate)
+	default:
+		return nil, fmt.Errorf("time block needs an end time or duration, e.g. %q or %q", "2pm-3pm", "2pm for 1h")
 	}
 
-	// Try to parse as regular date
-	return ParseDueDate(datePart)
+	return &TimeBlock{Start: &start, End: &end}, nil
 }
 
-// parseTimeOnDate parses time like "2pm", "14:30" on a specific date
-func parseTimeOnDate(timeStr string, baseDate *time.Time) (*time.Time, error) {
-	timeStr = strings.ToLower(timeStr)
-
-	// Handle AM/PM format
-	amPmPattern := regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(?:\s*)?(am|pm)$`)
-	if matches := amPmPattern.FindStringSubmatch(timeStr); matches != nil {
-		hour, _ := strconv.Atoi(matches[1])
-		minute := 0
-		if matches[2] != "" {
-			minute, _ = strconv.Atoi(matches[2])
-		}
-		ampm := matches[3]
-
-		if ampm == "pm" && hour != 12 {
-			hour += 12
-		} else if ampm == "am" && hour == 12 {
-			hour = 0
-		}
-
-		result := time.Date(baseDate.Year(), baseDate.Month(), baseDate.Day(), hour, minute, 0, 0, baseDate.Location())
-		return &result, nil
-	}
-
-	// Handle 24-hour format
-	hourMinPattern := regexp.MustCompile(`^(\d{1,2}):(\d{2})$`)
-	if matches := hourMinPattern.FindStringSubmatch(timeStr); matches != nil {
-		hour, _ := strconv.Atoi(matches[1])
-		minute, _ := strconv.Atoi(matches[2])
-
-		result := time.Date(baseDate.Year(), baseDate.Month(), baseDate.Day(), hour, minute, 0, 0, baseDate.Location())
-		return &result, nil
+// allDayBlock builds the all-day block for an expression with dates only
+func allDayBlock(expr *dateExpr, p *dateParser, now time.Time) (*TimeBlock, error) {
+	if expr.start != nil || expr.exact != nil {
+		return nil, fmt.Errorf("all-day blocks can't have a time")
 	}
 
-	// Handle hour only (assume PM if < 8, AM if >= 8)
-	hourPattern := regexp.MustCompile(`^(\d{1,2})$`)
-	if matches := hourPattern.FindStringSubmatch(timeStr); matches != nil {
-		hour, _ := strconv.Atoi(matches[1])
-
-		// Smart defaulting: 1-7 = PM, 8-12 = AM, 13-24 = 24hr format
-		if hour >= 1 && hour <= 7 {
-			hour += 12
+	start := expr.day(now)
+	var end time.Time
+	switch {
+	case expr.lastDate != nil && expr.duration != 0:
+		return nil, p.errorAt(expr.durToken, "time block has both an end date and a duration:")
+	case expr.lastDate != nil:
+		end = expr.lastDate.AddDate(0, 0, 1)
+	case expr.duration != 0:
+		if !isWholeDays(expr.duration) {
+			return nil, p.errorAt(expr.durToken, "all-day blocks need a duration in whole days:")
 		}
-
-		result := time.Date(baseDate.Year(), baseDate.Month(), baseDate.Day(), hour, 0, 0, 0, baseDate.Location())
-		return &result, nil
+		end = start.AddDate(0, 0, int(expr.duration/(24*time.Hour)))
+	default:
+		end = start.AddDate(0, 0, 1)
 	}
 
-	return nil, fmt.Errorf("unable to parse time: %s", timeStr)
+	return &TimeBlock{Start: &start, End: &end, AllDay: true}, nil
+}
+
+// isWholeDays reports whether d is a positive number of days
+func isWholeDays(d time.Duration) bool {
+	return d > 0 && d%(24*time.Hour) == 0
 }
 
 // FormatTimeBlock formats a time block for display
-func FormatTimeBlock(start, end *time.Time) string {
+func FormatTimeBlock(start, end *time.Time, allDay bool) string {
 	if start == nil {
 		return ""
 	}
 
+	if allDay {
+		if end == nil || !end.AddDate(0, 0, -1).After(*start) {
+			return fmt.Sprintf("Scheduled: %s (%s)", activeLocale.Date(*start), activeLocale.AllDay)
+		}
+		return fmt.Sprintf("Scheduled: %s - %s (%s)",
+			activeLocale.Date(*start),
+			activeLocale.Date(end.AddDate(0, 0, -1)),
+			activeLocale.AllDay)
+	}
+
 	if end == nil {
-		return fmt.Sprintf("Scheduled: %s", start.Format("Jan 2 3:04pm"))
+		return fmt.Sprintf("Scheduled: %s", activeLocale.DateTime(*start))
 	}
 
 	// Same day
 	if start.Year() == end.Year() && start.YearDay() == end.YearDay() {
 		return fmt.Sprintf("Scheduled: %s %s-%s",
-			start.Format("Jan 2"),
-			start.Format("3:04pm"),
-			end.Format("3:04pm"))
+			activeLocale.Date(*start),
+			activeLocale.Clock(*start),
+			activeLocale.Clock(*end))
 	}
 
 	// Different days
 	return fmt.Sprintf("Scheduled: %s - %s",
-		start.Format("Jan 2 3:04pm"),
-		end.Format("Jan 2 3:04pm"))
+		activeLocale.DateTime(*start),
+		activeLocale.DateTime(*end))
+}
+
+// blockOverlaps reports whether a todo's time block overlaps start up to
+// end. A block without an end only covers its start time.
+func blockOverlaps(todo Todo, start, end time.Time) bool {
+	if todo.ScheduledStart == nil || !todo.ScheduledStart.Before(end) {
+		return false
+	}
+	if !todo.ScheduledStart.Before(start) {
+		return true
+	}
+	return todo.ScheduledEnd != nil && todo.ScheduledEnd.After(start)
 }
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// Monday 19 October 2026, mid-morning
+var testNow = time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+
+// day returns the given wall-clock time in UTC
+func day(year int, month time.Month, d, hour, minute int) time.Time {
+	return time.Date(year, month, d, hour, minute, 0, 0, time.UTC)
+}
+
+// endOfDayUTC is when a due date given without a time falls
+func endOfDayUTC(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 23, 59, 59, 0, time.UTC)
+}
+
+// loadLocation loads a time zone, skipping the test if the system has no
+// zone database
+func loadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("time zone %s not available: %v", name, err)
+	}
+	return loc
+}
+
+func TestParseDueDate(t *testing.T) {
+	ny := loadLocation(t, "America/New_York")
+	springForward := time.Date(2026, 3, 7, 10, 0, 0, 0, ny) // Clocks go forward at 2am on Mar 8
+	fallBack := time.Date(2026, 10, 31, 10, 0, 0, 0, ny)    // Clocks go back at 2am on Nov 1
+
+	tests := []struct {
+		name    string
+		now     time.Time
+		input   string
+		want    time.Time
+		wantErr string
+	}{
+		{name: "today", now: testNow, input: "today", want: endOfDayUTC(2026, 10, 19)},
+		{name: "tomorrow", now: testNow, input: "Tomorrow", want: endOfDayUTC(2026, 10, 20)},
+		{name: "next week", now: testNow, input: "next week", want: endOfDayUTC(2026, 10, 26)},
+		{name: "iso date", now: testNow, input: "2027-03-01", want: endOfDayUTC(2027, 3, 1)},
+		{name: "explicit year", now: testNow, input: "feb 29, 2028", want: endOfDayUTC(2028, 2, 29)},
+		{name: "month and day later this year", now: testNow, input: "dec 25", want: endOfDayUTC(2026, 12, 25)},
+		{name: "month and day today", now: testNow, input: "oct 19", want: endOfDayUTC(2026, 10, 19)},
+		{name: "month and day already passed", now: testNow, input: "oct 18", want: endOfDayUTC(2027, 10, 18)},
+
+		{name: "tomorrow across months", now: day(2026, 10, 31, 10, 0), input: "tomorrow", want: endOfDayUTC(2026, 11, 1)},
+		{name: "tomorrow across years", now: day(2026, 12, 31, 10, 0), input: "tomorrow", want: endOfDayUTC(2027, 1, 1)},
+		{name: "next week across years", now: day(2026, 12, 28, 10, 0), input: "next week", want: endOfDayUTC(2027, 1, 4)},
+		{name: "past month rolls to next year", now: day(2026, 12, 30, 10, 0), input: "jan 15", want: endOfDayUTC(2027, 1, 15)},
+		{name: "past numeric date rolls to next year", now: day(2026, 12, 30, 10, 0), input: "1/2", want: endOfDayUTC(2027, 1, 2)},
+		{name: "last day of the year", now: day(2026, 12, 31, 10, 0), input: "dec 31", want: endOfDayUTC(2026, 12, 31)},
+		{name: "feb 29 in the next leap year", now: testNow, input: "feb 29", want: endOfDayUTC(2028, 2, 29)},
+
+		{name: "feb 29 after this year's has passed", now: day(2028, 3, 1, 10, 0), input: "feb 29", want: endOfDayUTC(2032, 2, 29)},
+
+		{name: "feb 30", now: testNow, input: "feb 30", wantErr: "no such date"},
+		{name: "june 31", now: testNow, input: "june 31st", wantErr: "no such date"},
+		{name: "feb 29 outside a leap year", now: testNow, input: "feb 29, 2027", wantErr: "no such date"},
+		{name: "nonsense", now: testNow, input: "someday soon", wantErr: "unable to parse"},
+		{name: "date range", now: testNow, input: "mon to wed", wantErr: "due dates can't span several days"},
+
+		{name: "tomorrow across spring forward", now: springForward, input: "tomorrow", want: time.Date(2026, 3, 8, 23, 59, 59, 0, ny)},
+		{name: "next week across fall back", now: fallBack, input: "next week", want: time.Date(2026, 11, 7, 23, 59, 59, 0, ny)},
+		{name: "date on the fall back day", now: fallBack, input: "nov 1", want: time.Date(2026, 11, 1, 23, 59, 59, 0, ny)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDueDate(tt.input, NewFixedClock(tt.now))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseDueDate(%q) = %v, %v; want error %q", tt.input, got, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseDueDate(%q): %v", tt.input, err)
+			}
+			if got == nil || !got.Equal(tt.want) {
+				t.Errorf("ParseDueDate(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTimeBlock(t *testing.T) {
+	ny := loadLocation(t, "America/New_York")
+	springForward := time.Date(2026, 3, 7, 10, 0, 0, 0, ny)
+	fallBack := time.Date(2026, 10, 31, 10, 0, 0, 0, ny)
+
+	tests := []struct {
+		name       string
+		now        time.Time
+		input      string
+		start, end time.Time
+		allDay     bool
+		wantErr    string
+	}{
+		{name: "tomorrow", now: testNow, input: "tomorrow 2pm-4pm", start: day(2026, 10, 20, 14, 0), end: day(2026, 10, 20, 16, 0)},
+		{name: "weekday later this week", now: testNow, input: "wed 9am-11am", start: day(2026, 10, 21, 9, 0), end: day(2026, 10, 21, 11, 0)},
+		{name: "today's weekday in the morning", now: testNow, input: "monday 2pm-4pm", start: day(2026, 10, 19, 14, 0), end: day(2026, 10, 19, 16, 0)},
+		{name: "24 hour times", now: testNow, input: "dec 24 13:30-15:00", start: day(2026, 12, 24, 13, 30), end: day(2026, 12, 24, 15, 0)},
+		{name: "bare hours", now: testNow, input: "today 9-5", start: day(2026, 10, 19, 9, 0), end: day(2026, 10, 19, 17, 0)},
+		{name: "duration", now: testNow, input: "tomorrow 3pm for 2 hours", start: day(2026, 10, 20, 15, 0), end: day(2026, 10, 20, 17, 0)},
+
+		{name: "overnight", now: testNow, input: "today 10pm-2am", start: day(2026, 10, 19, 22, 0), end: day(2026, 10, 20, 2, 0)},
+		{name: "overnight into next month", now: day(2026, 10, 31, 10, 0), input: "today 11pm-1am", start: day(2026, 10, 31, 23, 0), end: day(2026, 11, 1, 1, 0)},
+		{name: "overnight into next year", now: day(2026, 12, 31, 10, 0), input: "today 11pm-1am", start: day(2026, 12, 31, 23, 0), end: day(2027, 1, 1, 1, 0)},
+		{name: "duration into next year", now: day(2026, 12, 31, 10, 0), input: "today 11pm for 90 min", start: day(2026, 12, 31, 23, 0), end: day(2027, 1, 1, 0, 30)},
+		{name: "date rolls to next year", now: day(2026, 12, 30, 10, 0), input: "jan 2 9am-10am", start: day(2027, 1, 2, 9, 0), end: day(2027, 1, 2, 10, 0)},
+
+		{name: "hour lost to spring forward", now: springForward, input: "tomorrow 1am-3am", start: time.Date(2026, 3, 8, 1, 0, 0, 0, ny), end: time.Date(2026, 3, 8, 3, 0, 0, 0, ny)},
+		{name: "skipped start moves past the gap", now: springForward, input: "tomorrow 2:30am-4am", start: time.Date(2026, 3, 8, 3, 30, 0, 0, ny), end: time.Date(2026, 3, 8, 4, 0, 0, 0, ny)},
+		{name: "overnight into spring forward", now: springForward, input: "today 10pm-2am", start: time.Date(2026, 3, 7, 22, 0, 0, 0, ny), end: time.Date(2026, 3, 8, 3, 0, 0, 0, ny)},
+		{name: "working day after spring forward", now: springForward, input: "tomorrow 9am for 8 hours", start: time.Date(2026, 3, 8, 9, 0, 0, 0, ny), end: time.Date(2026, 3, 8, 17, 0, 0, 0, ny)},
+		{name: "hour gained at fall back", now: fallBack, input: "tomorrow 12am-3am", start: time.Date(2026, 11, 1, 0, 0, 0, 0, ny), end: time.Date(2026, 11, 1, 3, 0, 0, 0, ny)},
+
+		{name: "all day", now: testNow, input: "fri all day", start: day(2026, 10, 23, 0, 0), end: day(2026, 10, 24, 0, 0), allDay: true},
+		{name: "weekday range", now: testNow, input: "mon to wed", start: day(2026, 10, 19, 0, 0), end: day(2026, 10, 22, 0, 0), allDay: true},
+		{name: "weekday range wraps the week", now: testNow, input: "fri-mon", start: day(2026, 10, 23, 0, 0), end: day(2026, 10, 27, 0, 0), allDay: true},
+		{name: "day range", now: testNow, input: "dec 24-26", start: day(2026, 12, 24, 0, 0), end: day(2026, 12, 27, 0, 0), allDay: true},
+		{name: "whole days", now: testNow, input: "sat for 2 days", start: day(2026, 10, 24, 0, 0), end: day(2026, 10, 26, 0, 0), allDay: true},
+		{name: "date range into next year", now: testNow, input: "dec 31 - jan 2", start: day(2026, 12, 31, 0, 0), end: day(2027, 1, 3, 0, 0), allDay: true},
+		{name: "date range into next year from january", now: day(2026, 1, 1, 10, 0), input: "dec 31 - jan 2", start: day(2026, 12, 31, 0, 0), end: day(2027, 1, 3, 0, 0), allDay: true},
+		{name: "date range with years", now: testNow, input: "dec 30 to jan 1 2027", start: day(2026, 12, 30, 0, 0), end: day(2027, 1, 2, 0, 0), allDay: true},
+		{name: "day range into next month", now: testNow, input: "oct 30 - nov 2", start: day(2026, 10, 30, 0, 0), end: day(2026, 11, 3, 0, 0), allDay: true},
+		{name: "all day across spring forward", now: springForward, input: "tomorrow for 2 days", start: time.Date(2026, 3, 8, 0, 0, 0, 0, ny), end: time.Date(2026, 3, 10, 0, 0, 0, 0, ny), allDay: true},
+
+		{name: "all day with a time", now: testNow, input: "fri all day 3pm", wantErr: "all-day blocks can't have a time"},
+		{name: "partial days", now: testNow, input: "mon to wed for 36h", wantErr: "both an end date and a duration"},
+		{name: "backwards date range", now: testNow, input: "dec 26 - dec 24", wantErr: "date range ends before it starts"},
+		{name: "no such end date", now: testNow, input: "feb 27-30", wantErr: "no such date"},
+		{name: "no start", now: testNow, input: "tomorrow", wantErr: "time block needs a start time"},
+		{name: "no end", now: testNow, input: "tomorrow 2pm", wantErr: "time block needs an end time or duration"},
+		{name: "end and duration", now: testNow, input: "2pm-3pm for 1h", wantErr: "both an end time and a duration"},
+		{name: "bad date", now: testNow, input: "someday 2pm-4pm", wantErr: "unable to parse \"someday\""},
+		{name: "bad time", now: testNow, input: "tomorrow 25pm-26pm", wantErr: "unable to parse \"25pm-26pm\""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			block, err := ParseTimeBlock(tt.input, NewFixedClock(tt.now))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseTimeBlock(%q) = %v; want error %q", tt.input, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTimeBlock(%q): %v", tt.input, err)
+			}
+			if !block.Start.Equal(tt.start) || !block.End.Equal(tt.end) || block.AllDay != tt.allDay {
+				t.Errorf("ParseTimeBlock(%q) = %v - %v all day %v; want %v - %v all day %v",
+					tt.input, block.Start, block.End, block.AllDay, tt.start, tt.end, tt.allDay)
+			}
+		})
+	}
+}
+
+func TestFormatDueDate(t *testing.T) {
+	clock := NewFixedClock(testNow)
+	tests := []struct {
+		due   time.Time
+		want  string
+		color string
+	}{
+		{due: day(2026, 10, 19, 23, 59), want: "Today", color: ColorOrange},
+		{due: day(2026, 10, 20, 9, 0), want: "Tomorrow", color: ColorYellow},
+		{due: day(2026, 10, 17, 9, 0), want: "2 days overdue", color: ColorRed},
+		{due: day(2026, 10, 23, 12, 0), want: "In 4 days", color: ColorBlue},
+		{due: day(2026, 12, 25, 12, 0), want: "Dec 25", color: ColorGray},
+		{due: day(2027, 1, 15, 12, 0), want: "Jan 15, 2027", color: ColorGray},
+	}
+
+	for _, tt := range tests {
+		due := tt.due
+		if got := FormatDueDate(&due, clock); got != tt.want {
+			t.Errorf("FormatDueDate(%v) = %q, want %q", due, got, tt.want)
+		}
+		if got := GetDueDateColor(&due, clock); got != tt.color {
+			t.Errorf("GetDueDateColor(%v) = %q, want %q", due, got, tt.color)
+		}
+	}
+}
+
+func TestFormatTimeBlock(t *testing.T) {
+	tests := []struct {
+		start, end time.Time
+		allDay     bool
+		want       string
+	}{
+		{start: day(2026, 10, 20, 14, 0), end: day(2026, 10, 20, 15, 30), want: "Scheduled: Oct 20 2:00pm-3:30pm"},
+		{start: day(2026, 10, 20, 22, 0), end: day(2026, 10, 21, 2, 0), want: "Scheduled: Oct 20 10:00pm - Oct 21 2:00am"},
+		{start: day(2026, 10, 23, 0, 0), end: day(2026, 10, 24, 0, 0), allDay: true, want: "Scheduled: Oct 23 (all day)"},
+		{start: day(2026, 12, 24, 0, 0), end: day(2026, 12, 27, 0, 0), allDay: true, want: "Scheduled: Dec 24 - Dec 26 (all day)"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatTimeBlock(&tt.start, &tt.end, tt.allDay); got != tt.want {
+			t.Errorf("FormatTimeBlock(%v, %v, %v) = %q, want %q", tt.start, tt.end, tt.allDay, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,499 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Filter is a compiled filter expression such as
+// "done:false due<+3d tag:work and not project:home". It runs as
+// parameterised SQL against the database, and can be matched against todos
+// directly for stores without SQL.
+//
+// Terms are field:value or field with a comparison (<, <=, >, >=):
+//
+//	done:true|false     tag:<name> or #name     project:<name>|none or +name
+//	priority:high or !high, priority>=medium     estimate>1h, estimate:none
+//	due:<day>|none|any, due<+3d                  scheduled:<day>|none|any
+//
+// Days are anything a due date accepts ("today", "fri", "dec 24") or an
+// offset from today ("+3d", "-1w"); quote days with spaces. Any other word
+// matches the title or description. Terms next to each other must all
+// match; "or", "not" and parentheses combine them.
+type Filter struct {
+	Query string
+	root  filterNode
+}
+
+// SavedFilter is a filter stored under a name
+type SavedFilter struct {
+	Name  string
+	Query string
+}
+
+// filterNamePattern is what a saved filter can be called, so that names
+// are easy to type on the command line
+var filterNamePattern = regexp.MustCompile(`^[A-Za-z0-9][\w-]*$`)
+
+// filterNode is one part of a compiled filter
+type filterNode interface {
+	// sql returns a WHERE clause fragment, appending its arguments
+	sql(args *[]any) string
+	match(todo Todo) bool
+}
+
+// filterTerm splits a term into field, comparison and value
+var filterTerm = regexp.MustCompile(`^([A-Za-z]+)(<=|>=|:|<|>|=)(.*)$`)
+
+// filterDayOffset matches offsets from today like "+3d" or "-1w"
+var filterDayOffset = regexp.MustCompile(`^[+-]\d+[dw]$`)
+
+// CompileFilter parses a filter expression. Days are worked out relative
+// to now, so a filter should be compiled again when the day changes.
+func CompileFilter(query string, now time.Time) (*Filter, error) {
+	tokens, err := tokenizeFilter(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("filter is empty")
+	}
+
+	p := &filterParser{tokens: tokens, now: now}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q in filter", p.tokens[p.pos].text)
+	}
+
+	return &Filter{Query: query, root: root}, nil
+}
+
+// Where returns the filter as a SQL condition and its arguments
+func (f *Filter) Where() (string, []any) {
+	var args []any
+	return f.root.sql(&args), args
+}
+
+// Match reports whether todo passes the filter
+func (f *Filter) Match(todo Todo) bool {
+	return f.root.match(todo)
+}
+
+// filterToken is a word of a filter; quoted tokens are never keywords
+type filterToken struct {
+	text   string
+	quoted bool
+}
+
+// tokenizeFilter splits a filter on whitespace and parentheses. Double
+// quotes group words, including inside a term: due:"next friday".
+func tokenizeFilter(query string) ([]filterToken, error) {
+	var tokens []filterToken
+	var current strings.Builder
+	inToken, quoted := false, false
+
+	flush := func() {
+		if inToken {
+			tokens = append(tokens, filterToken{text: current.String(), quoted: quoted})
+		}
+		current.Reset()
+		inToken, quoted = false, false
+	}
+
+	runes := []rune(query)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("unclosed quote in filter")
+			}
+			current.WriteString(string(runes[i+1 : end]))
+			// Only a token that starts quoted is plain text; a quoted
+			// value like due:"next friday" is still a term
+			if !inToken {
+				quoted = true
+			}
+			inToken = true
+			i = end
+		case r == '(' || r == ')':
+			flush()
+			tokens = append(tokens, filterToken{text: string(r)})
+		case r == ' ' || r == '\t' || r == '\n':
+			flush()
+		default:
+			current.WriteRune(r)
+			inToken = true
+		}
+	}
+	flush()
+
+	return tokens, nil
+}
+
+// filterParser is a recursive descent parser over filter tokens
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+	now    time.Time
+}
+
+// keyword reports whether the current token is the given keyword
+func (p *filterParser) keyword(word string) bool {
+	if p.pos >= len(p.tokens) || p.tokens[p.pos].quoted {
+		return false
+	}
+	return strings.EqualFold(p.tokens[p.pos].text, word)
+}
+
+// parseOr handles a or b or c
+func (p *filterParser) parseOr() (filterNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+// parseAnd handles a and b, or just a b
+func (p *filterParser) parseAnd() (filterNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.pos < len(p.tokens) && !p.keyword("or") && !p.keyword(")") {
+		if p.keyword("and") {
+			p.pos++
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+// parseNot handles not a, parentheses and terms
+func (p *filterParser) parseNot() (filterNode, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("filter ends too early")
+	}
+
+	switch {
+	case p.keyword("not"):
+		p.pos++
+		inner, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{inner}, nil
+	case p.keyword("("):
+		p.pos++
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.keyword(")") {
+			return nil, fmt.Errorf("missing ) in filter")
+		}
+		p.pos++
+		return inner, nil
+	case p.keyword(")"), p.keyword("and"), p.keyword("or"):
+		return nil, fmt.Errorf("unexpected %q in filter", p.tokens[p.pos].text)
+	}
+
+	token := p.tokens[p.pos]
+	p.pos++
+	if token.quoted {
+		return textNode{token.text}, nil
+	}
+	return p.parseTerm(token.text)
+}
+
+// parseTerm compiles a single term
+func (p *filterParser) parseTerm(term string) (filterNode, error) {
+	switch {
+	case smartTagPattern.MatchString(term):
+		return tagNode{term[1:]}, nil
+	case smartProjectPattern.MatchString(term):
+		return projectNode{term[1:]}, nil
+	case strings.HasPrefix(term, "!") && len(term) > 1:
+		return p.parsePriority(":", term[1:])
+	}
+
+	m := filterTerm.FindStringSubmatch(term)
+	if m == nil {
+		return textNode{term}, nil
+	}
+	field, op, value := strings.ToLower(m[1]), m[2], m[3]
+	if value == "" {
+		return nil, fmt.Errorf("%s needs a value", term)
+	}
+	equality := op == ":" || op == "="
+
+	switch field {
+	case "done":
+		done, err := strconv.ParseBool(value)
+		if err != nil || !equality {
+			return nil, fmt.Errorf("done takes true or false, e.g. done:false")
+		}
+		return doneNode{done}, nil
+	case "tag":
+		if !equality {
+			return nil, fmt.Errorf("tag can't be compared with %s", op)
+		}
+		return tagNode{value}, nil
+	case "project":
+		if !equality {
+			return nil, fmt.Errorf("project can't be compared with %s", op)
+		}
+		if strings.EqualFold(value, "none") {
+			value = ""
+		}
+		return projectNode{value}, nil
+	case "priority":
+		return p.parsePriority(op, value)
+	case "estimate":
+		if equality && strings.EqualFold(value, "none") {
+			return numberNode{"estimate_minutes", "=", 0, func(t Todo) int { return int(t.Estimate / time.Minute) }}, nil
+		}
+		estimate, err := ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid estimate %q: %w", value, err)
+		}
+		return numberNode{"estimate_minutes", sqlOp(op), int(estimate / time.Minute), func(t Todo) int { return int(t.Estimate / time.Minute) }}, nil
+	case "due":
+		return p.parseDay("due_date", func(t Todo) *time.Time { return t.DueDate }, op, value)
+	case "scheduled", "sched":
+		return p.parseDay("scheduled_start", func(t Todo) *time.Time { return t.ScheduledStart }, op, value)
+	}
+
+	return nil, fmt.Errorf("unknown filter field %q (use done, tag, project, priority, estimate, due or scheduled)", field)
+}
+
+// parsePriority compiles a priority term
+func (p *filterParser) parsePriority(op, value string) (filterNode, error) {
+	priority, err := ParsePriority(value)
+	if err != nil {
+		return nil, err
+	}
+	return numberNode{"priority", sqlOp(op), int(priority), func(t Todo) int { return int(t.Priority) }}, nil
+}
+
+// parseDay compiles a due or scheduled term into a range of time
+func (p *filterParser) parseDay(column string, get func(Todo) *time.Time, op, value string) (filterNode, error) {
+	equality := op == ":" || op == "="
+	switch {
+	case equality && strings.EqualFold(value, "none"):
+		return nullNode{column, get, true}, nil
+	case equality && strings.EqualFold(value, "any"):
+		return nullNode{column, get, false}, nil
+	}
+
+	var day time.Time
+	if filterDayOffset.MatchString(value) {
+		n, _ := strconv.Atoi(value[:len(value)-1])
+		if strings.HasSuffix(value, "w") {
+			n *= 7
+		}
+		day = startOfDay(p.now).AddDate(0, 0, n)
+	} else {
+		due, err := parseDueDate(value, p.now)
+		if err != nil {
+			return nil, fmt.Errorf("invalid day %q: %w", value, err)
+		}
+		day = startOfDay(*due)
+	}
+	next := day.AddDate(0, 0, 1)
+
+	// Comparisons are by whole days: due<fri is before friday starts,
+	// due<=fri is up to the end of friday
+	switch op {
+	case "<":
+		return rangeNode{column, get, nil, &day}, nil
+	case "<=":
+		return rangeNode{column, get, nil, &next}, nil
+	case ">":
+		return rangeNode{column, get, &next, nil}, nil
+	case ">=":
+		return rangeNode{column, get, &day, nil}, nil
+	}
+	return rangeNode{column, get, &day, &next}, nil
+}
+
+// sqlOp maps a filter comparison to SQL
+func sqlOp(op string) string {
+	if op == ":" {
+		return "="
+	}
+	return op
+}
+
+type andNode struct{ left, right filterNode }
+
+func (n andNode) sql(args *[]any) string {
+	return "(" + n.left.sql(args) + " AND " + n.right.sql(args) + ")"
+}
+
+func (n andNode) match(todo Todo) bool { return n.left.match(todo) && n.right.match(todo) }
+
+type orNode struct{ left, right filterNode }
+
+func (n orNode) sql(args *[]any) string {
+	return "(" + n.left.sql(args) + " OR " + n.right.sql(args) + ")"
+}
+
+func (n orNode) match(todo Todo) bool { return n.left.match(todo) || n.right.match(todo) }
+
+type notNode struct{ inner filterNode }
+
+func (n notNode) sql(args *[]any) string { return "NOT " + n.inner.sql(args) }
+
+func (n notNode) match(todo Todo) bool { return !n.inner.match(todo) }
+
+// doneNode matches finished or unfinished todos
+type doneNode struct{ done bool }
+
+func (n doneNode) sql(args *[]any) string {
+	if n.done {
+		return "done = TRUE"
+	}
+	return "done = FALSE"
+}
+
+func (n doneNode) match(todo Todo) bool { return todo.Done == n.done }
+
+// tagNode matches a tag in the comma separated tags column
+type tagNode struct{ tag string }
+
+func (n tagNode) sql(args *[]any) string {
+	*args = append(*args, "%,"+escapeLike(strings.ToLower(n.tag))+",%")
+	return `(',' || LOWER(COALESCE(tags, '')) || ',') LIKE ? ESCAPE '\'`
+}
+
+func (n tagNode) match(todo Todo) bool { return containsFold(todo.Tags, n.tag) }
+
+// projectNode matches a project by name; an empty name matches no project
+type projectNode struct{ project string }
+
+func (n projectNode) sql(args *[]any) string {
+	*args = append(*args, strings.ToLower(n.project))
+	return "LOWER(COALESCE(project, '')) = ?"
+}
+
+func (n projectNode) match(todo Todo) bool { return strings.EqualFold(todo.Project, n.project) }
+
+// numberNode compares an integer column
+type numberNode struct {
+	column string
+	op     string
+	value  int
+	get    func(Todo) int
+}
+
+func (n numberNode) sql(args *[]any) string {
+	*args = append(*args, n.value)
+	return fmt.Sprintf("%s %s ?", n.column, n.op)
+}
+
+func (n numberNode) match(todo Todo) bool {
+	v := n.get(todo)
+	switch n.op {
+	case "<":
+		return v < n.value
+	case "<=":
+		return v <= n.value
+	case ">":
+		return v > n.value
+	case ">=":
+		return v >= n.value
+	}
+	return v == n.value
+}
+
+// nullNode matches a time column being unset, or being set
+type nullNode struct {
+	column string
+	get    func(Todo) *time.Time
+	isNull bool
+}
+
+func (n nullNode) sql(args *[]any) string {
+	if n.isNull {
+		return n.column + " IS NULL"
+	}
+	return n.column + " IS NOT NULL"
+}
+
+func (n nullNode) match(todo Todo) bool { return (n.get(todo) == nil) == n.isNull }
+
+// rangeNode matches a time column from from up to to; either may be open
+type rangeNode struct {
+	column   string
+	get      func(Todo) *time.Time
+	from, to *time.Time
+}
+
+func (n rangeNode) sql(args *[]any) string {
+	parts := []string{n.column + " IS NOT NULL"}
+	if n.from != nil {
+		*args = append(*args, n.from.UTC())
+		parts = append(parts, fmt.Sprintf("datetime(%s) >= datetime(?)", n.column))
+	}
+	if n.to != nil {
+		*args = append(*args, n.to.UTC())
+		parts = append(parts, fmt.Sprintf("datetime(%s) < datetime(?)", n.column))
+	}
+	return "(" + strings.Join(parts, " AND ") + ")"
+}
+
+func (n rangeNode) match(todo Todo) bool {
+	t := n.get(todo)
+	if t == nil {
+		return false
+	}
+	if n.from != nil && t.Before(*n.from) {
+		return false
+	}
+	return n.to == nil || t.Before(*n.to)
+}
+
+// textNode matches words in the title or description. Nullable columns are
+// coalesced so that a NULL never makes the whole condition NULL, which NOT
+// would then drop.
+type textNode struct{ text string }
+
+func (n textNode) sql(args *[]any) string {
+	pattern := "%" + escapeLike(strings.ToLower(n.text)) + "%"
+	*args = append(*args, pattern, pattern)
+	return `(LOWER(title) LIKE ? ESCAPE '\' OR LOWER(COALESCE(description, '')) LIKE ? ESCAPE '\')`
+}
+
+func (n textNode) match(todo Todo) bool {
+	text := strings.ToLower(n.text)
+	return strings.Contains(strings.ToLower(todo.Title), text) || strings.Contains(strings.ToLower(todo.Description), text)
+}
+
+// escapeLike escapes the LIKE wildcards in s
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
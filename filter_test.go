@@ -0,0 +1,133 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+// addFilterTodos fills store with todos to filter around testNow,
+// Monday 19 October 2026
+func addFilterTodos(t *testing.T, store Store) {
+	t.Helper()
+	addTodos(t, store, NewFixedClock(testNow),
+		"Fix login bug #work +app !high ~2h due:tomorrow",
+		"Write 100% of the docs #work +app ~30m due:fri",
+		"Buy groceries #home due:yesterday",
+		"Plan trip @ sat 10am-noon",
+		"Read a book",
+	)
+	if err := store.ToggleTodo(3); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFilterTodos(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: "#work", want: []string{"Write 100% of the docs", "Fix login bug"}},
+		{query: "tag:WORK and !high", want: []string{"Fix login bug"}},
+		{query: "project:none done:false", want: []string{"Read a book", "Plan trip"}},
+		{query: "+app estimate<1h", want: []string{"Write 100% of the docs"}},
+		{query: "due<+3d", want: []string{"Buy groceries", "Fix login bug"}},
+		{query: "due<=fri not done:true", want: []string{"Write 100% of the docs", "Fix login bug"}},
+		{query: "due:none and scheduled:any", want: []string{"Plan trip"}},
+		{query: "scheduled:sat or \"a book\"", want: []string{"Read a book", "Plan trip"}},
+		{query: "(#home or #work) and not priority>=medium", want: []string{"Buy groceries", "Write 100% of the docs"}},
+		{query: "100%", want: []string{"Write 100% of the docs"}},
+	}
+
+	for name, store := range map[string]Store{"memory": newTestStore(), "database": newTestDB(t)} {
+		t.Run(name, func(t *testing.T) {
+			addFilterTodos(t, store)
+			for _, tt := range tests {
+				filter, err := CompileFilter(tt.query, testNow)
+				if err != nil {
+					t.Errorf("CompileFilter(%q): %v", tt.query, err)
+					continue
+				}
+				todos, err := store.FilterTodos(filter)
+				if err != nil {
+					t.Errorf("FilterTodos(%q): %v", tt.query, err)
+					continue
+				}
+				if got := titles(todos); !slices.Equal(got, tt.want) {
+					t.Errorf("FilterTodos(%q) = %q, want %q", tt.query, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestFilterWhereAgreesWithMatch(t *testing.T) {
+	db := newTestDB(t)
+	addFilterTodos(t, db)
+	// Synced and older rows can have no description, which Match sees as empty
+	if _, err := db.conn.Exec("UPDATE todos SET description = NULL WHERE id IN (3, 5)"); err != nil {
+		t.Fatal(err)
+	}
+	all, err := db.GetAllTodos()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, query := range []string{
+		"not book",
+		"not #home",
+		"not project:app",
+		"project:none",
+		"not (\"a book\" or #work)",
+	} {
+		filter, err := CompileFilter(query, testNow)
+		if err != nil {
+			t.Fatalf("CompileFilter(%q): %v", query, err)
+		}
+		todos, err := db.FilterTodos(filter)
+		if err != nil {
+			t.Fatalf("FilterTodos(%q): %v", query, err)
+		}
+		var want []Todo
+		for _, todo := range all {
+			if filter.Match(todo) {
+				want = append(want, todo)
+			}
+		}
+		if got := titles(todos); !slices.Equal(got, titles(want)) {
+			t.Errorf("%q: SQL matched %q, Match matched %q", query, got, titles(want))
+		}
+	}
+}
+
+func TestCompileFilterErrors(t *testing.T) {
+	for _, query := range []string{
+		"",
+		"(#work",
+		"#work or",
+		"done:maybe",
+		"tag>work",
+		"colour:red",
+		"due:\"next blursday\"",
+		"\"unclosed",
+	} {
+		if _, err := CompileFilter(query, testNow); err == nil {
+			t.Errorf("CompileFilter(%q) should fail", query)
+		}
+	}
+}
+
+func TestCLISavedFilters(t *testing.T) {
+	c, store, out := newTestCLI()
+	addFilterTodos(t, store)
+
+	assertContains(t, run(c, out, "filter", "save", "work", "#work done:false"), "work")
+	assertContains(t, run(c, out, "filter", "save", "list", "#work"), "Error")
+	assertContains(t, run(c, out, "filter", "list"), "work", "#work done:false")
+	assertContains(t, run(c, out, "filter", "work"), "Fix login bug", "Write 100% of the docs")
+	assertContains(t, run(c, out, "list", "--filter", "#home"), "Buy groceries")
+
+	run(c, out, "filter", "delete", "work")
+	if filters, _ := store.GetSavedFilters(); len(filters) != 0 {
+		t.Errorf("saved filters after delete = %v", filters)
+	}
+}
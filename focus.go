@@ -0,0 +1,210 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// FocusOptions are the pomodoro and break lengths used by focus mode
+type FocusOptions struct {
+	Pomodoro       time.Duration
+	ShortBreak     time.Duration
+	LongBreak      time.Duration
+	LongBreakEvery int // Pomodoros between long breaks
+}
+
+// DefaultFocusOptions returns the classic 25/5/15 minute pomodoro cycle
+func DefaultFocusOptions() FocusOptions {
+	return FocusOptions{
+		Pomodoro:       25 * time.Minute,
+		ShortBreak:     5 * time.Minute,
+		LongBreak:      15 * time.Minute,
+		LongBreakEvery: 4,
+	}
+}
+
+type focusPhase int
+
+const (
+	focusWork focusPhase = iota
+	focusShortBreak
+	focusLongBreak
+)
+
+// String names the phase for display
+func (p focusPhase) String() string {
+	switch p {
+	case focusShortBreak:
+		return "Short break"
+	case focusLongBreak:
+		return "Long break"
+	}
+	return "Focus"
+}
+
+// focusSession is a run of pomodoros on one todo. Time left is worked out
+// from the clock rather than counted down tick by tick, so a late tick
+// never makes the countdown drift.
+type focusSession struct {
+	todo      Todo
+	options   FocusOptions
+	phase     focusPhase
+	started   time.Time     // When the phase last started or resumed
+	remaining time.Duration // Time left as of started
+	running   bool
+	waiting   bool      // The phase is over and the next one hasn't started
+	workStart time.Time // When the current pomodoro started
+	completed int       // Pomodoros completed in this session
+	total     int       // Pomodoros completed on the todo, ever
+	ticks     int       // Identifies the current tick loop
+}
+
+// newFocusSession starts a session on todo with its first pomodoro running
+func newFocusSession(todo Todo, options FocusOptions, total int, now time.Time) *focusSession {
+	return &focusSession{
+		todo:      todo,
+		options:   options,
+		phase:     focusWork,
+		started:   now,
+		remaining: options.Pomodoro,
+		running:   true,
+		workStart: now,
+		total:     total,
+	}
+}
+
+// left returns the time left in the phase
+func (f *focusSession) left(now time.Time) time.Duration {
+	if !f.running {
+		return f.remaining
+	}
+	return max(f.remaining-now.Sub(f.started), 0)
+}
+
+// phaseEnd returns when the running phase ends
+func (f *focusSession) phaseEnd() time.Time {
+	return f.started.Add(f.remaining)
+}
+
+// length returns how long a phase lasts
+func (f *focusSession) length(phase focusPhase) time.Duration {
+	switch phase {
+	case focusShortBreak:
+		return f.options.ShortBreak
+	case focusLongBreak:
+		return f.options.LongBreak
+	}
+	return f.options.Pomodoro
+}
+
+// nextPhase returns the phase that follows the current one
+func (f *focusSession) nextPhase() focusPhase {
+	if f.phase != focusWork {
+		return focusWork
+	}
+	if f.options.LongBreakEvery > 0 && f.completed > 0 && f.completed%f.options.LongBreakEvery == 0 {
+		return focusLongBreak
+	}
+	return focusShortBreak
+}
+
+// advance moves on to the next phase and waits for it to be started
+func (f *focusSession) advance() {
+	f.phase = f.nextPhase()
+	f.remaining = f.length(f.phase)
+	f.running = false
+	f.waiting = true
+}
+
+// pause stops the countdown, keeping the time left
+func (f *focusSession) pause(now time.Time) {
+	f.remaining = f.left(now)
+	f.running = false
+}
+
+// resume restarts the countdown, starting the phase if it was waiting
+func (f *focusSession) resume(now time.Time) {
+	if f.waiting && f.phase == focusWork {
+		f.workStart = now
+	}
+	f.started = now
+	f.running = true
+	f.waiting = false
+}
+
+// bigDigits draws the countdown in block characters, five rows high
+var bigDigits = map[rune][5]string{
+	'0': {"█████", "█   █", "█   █", "█   █", "█████"},
+	'1': {"   █ ", "  ██ ", "   █ ", "   █ ", "  ███"},
+	'2': {"█████", "    █", "█████", "█    ", "█████"},
+	'3': {"█████", "    █", " ████", "    █", "█████"},
+	'4': {"█   █", "█   █", "█████", "    █", "    █"},
+	'5': {"█████", "█    ", "█████", "    █", "█████"},
+	'6': {"█████", "█    ", "█████", "█   █", "█████"},
+	'7': {"█████", "    █", "   █ ", "  █  ", "  █  "},
+	'8': {"█████", "█   █", "█████", "█   █", "█████"},
+	'9': {"█████", "█   █", "█████", "    █", "█████"},
+	':': {"   ", " █ ", "   ", " █ ", "   "},
+}
+
+// renderBigCountdown draws d as a large MM:SS clock
+func renderBigCountdown(d time.Duration) string {
+	d = d.Round(time.Second)
+	text := fmt.Sprintf("%02d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+
+	var rows [5]string
+	for _, r := range text {
+		glyph := bigDigits[r]
+		for i := range rows {
+			rows[i] += glyph[i] + " "
+		}
+	}
+	return strings.Join(rows[:], "\n")
+}
+
+// render draws the focus screen
+func (f *focusSession) render(now time.Time) string {
+	var s strings.Builder
+
+	s.WriteString(tuiTitleStyle.Render("🍅 Focus"))
+	s.WriteString("\n\n")
+	s.WriteString(idStyle.Render(fmt.Sprintf("[%d]", f.todo.ID)) + " " + tuiSelectedStyle.Render(f.todo.Title))
+	s.WriteString("\n\n")
+
+	color := ColorRed
+	if f.phase != focusWork {
+		color = ColorGreen
+	}
+	phaseStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Bold(true)
+
+	phase := f.phase.String()
+	if f.phase == focusWork {
+		phase = fmt.Sprintf("Pomodoro %d", f.completed+1)
+	}
+	if !f.running && !f.waiting {
+		phase += " (paused)"
+	}
+	s.WriteString(phaseStyle.Render(phase))
+	s.WriteString("\n\n")
+	s.WriteString(phaseStyle.Render(renderBigCountdown(f.left(now))))
+	s.WriteString("\n\n")
+
+	if f.waiting {
+		if f.phase == focusWork {
+			s.WriteString(successStyle.Render("Break over. Press enter to start the next pomodoro."))
+		} else {
+			s.WriteString(successStyle.Render(fmt.Sprintf("🍅 Pomodoro done! Press enter to start your %s %s.",
+				formatDuration(f.remaining), strings.ToLower(f.phase.String()))))
+		}
+		s.WriteString("\n")
+	}
+	s.WriteString(tuiDoneStyle.Render(fmt.Sprintf("🍅 %d this session, %d on this todo", f.completed, f.total)))
+	s.WriteString("\n\n")
+
+	s.WriteString(tuiHelpStyle.Render("Enter: start next, Space: pause/resume, n: skip, d: mark done, Esc: leave"))
+
+	return tuiContainerStyle.Render(s.String())
+}
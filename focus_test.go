@@ -0,0 +1,72 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFocusSessionCycle(t *testing.T) {
+	options := DefaultFocusOptions()
+	options.LongBreakEvery = 2
+	f := newFocusSession(Todo{ID: 1, Title: "Write"}, options, 0, testNow)
+
+	if left := f.left(testNow.Add(10 * time.Minute)); left != 15*time.Minute {
+		t.Errorf("left after 10m = %v, want 15m", left)
+	}
+
+	// Pausing keeps the time left however long the pause lasts
+	f.pause(testNow.Add(10 * time.Minute))
+	if left := f.left(testNow.Add(time.Hour)); left != 15*time.Minute {
+		t.Errorf("left while paused = %v, want 15m", left)
+	}
+	f.resume(testNow.Add(time.Hour))
+	if end := f.phaseEnd(); !end.Equal(testNow.Add(75 * time.Minute)) {
+		t.Errorf("phase ends at %v, want 15m after resuming", end)
+	}
+
+	var phases []focusPhase
+	for range 4 {
+		if f.phase == focusWork {
+			f.completed++
+		}
+		f.advance()
+		phases = append(phases, f.phase)
+		f.resume(testNow)
+	}
+	want := []focusPhase{focusShortBreak, focusWork, focusLongBreak, focusWork}
+	for i := range want {
+		if phases[i] != want[i] {
+			t.Fatalf("phases = %v, want %v", phases, want)
+		}
+	}
+	if f.remaining != options.Pomodoro {
+		t.Errorf("remaining = %v, want a full pomodoro", f.remaining)
+	}
+}
+
+func TestTuiFocusLogsPomodoros(t *testing.T) {
+	store := newTestStore()
+	addTodos(t, store, NewFixedClock(testNow), "Write report")
+	m := newTestTui(store)
+
+	m = press(m, "i", "f")
+	if m.focus == nil || !m.focus.running {
+		t.Fatal("f should start a pomodoro on the selected todo")
+	}
+	if entry, _ := store.GetRunningTimer(); entry == nil || entry.TodoID != 1 {
+		t.Errorf("focus should track time on the todo, running timer = %+v", entry)
+	}
+
+	m.clock = NewFixedClock(testNow.Add(25 * time.Minute))
+	updated, _ := m.Update(focusTickMsg(m.focus.ticks))
+	m = updated.(tuiModel)
+	if !m.focus.waiting || m.focus.phase != focusShortBreak {
+		t.Errorf("after a pomodoro the short break should wait to start, phase %v", m.focus.phase)
+	}
+	if count, _ := store.CountPomodoros(1); count != 1 {
+		t.Errorf("logged %d pomodoros, want 1", count)
+	}
+	if entry, _ := store.GetRunningTimer(); entry != nil {
+		t.Errorf("the timer should stop for the break, running %+v", entry)
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// freeSearchDays is how far ahead li free looks without a range
+const freeSearchDays = 7
+
+// nextFreeSearchDays is how far ahead next-free looks for a slot
+const nextFreeSearchDays = 28
+
+// slotStep is the granularity free slots start on
+const slotStep = 15 * time.Minute
+
+// Slot is a stretch of free time
+type Slot struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Length returns how long the slot lasts
+func (s Slot) Length() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// FreeSlots returns the free time of at least minLength between from and
+// to, within working hours, left after subtracting the timed blocks of
+// todos. All-day blocks don't take up time, as with overlaps.
+func FreeSlots(todos []Todo, from, to time.Time, options CalendarOptions, minLength time.Duration) []Slot {
+	var busy []Slot
+	for _, todo := range todos {
+		if isTimedBlock(todo) {
+			busy = append(busy, Slot{Start: *todo.ScheduledStart, End: *todo.ScheduledEnd})
+		}
+	}
+	sort.Slice(busy, func(i, j int) bool {
+		return busy[i].Start.Before(busy[j].Start)
+	})
+
+	var slots []Slot
+	for day := startOfDay(from); day.Before(to); day = day.AddDate(0, 0, 1) {
+		// Wall-clock hours, so that working hours hold on DST change days
+		start := time.Date(day.Year(), day.Month(), day.Day(), options.WorkStart, 0, 0, 0, day.Location())
+		end := time.Date(day.Year(), day.Month(), day.Day(), options.WorkEnd, 0, 0, 0, day.Location())
+		if start.Before(from) {
+			start = roundUp(from, slotStep)
+		}
+		if end.After(to) {
+			end = to
+		}
+
+		// Walk the busy blocks in order, keeping the gaps between them
+		for _, block := range busy {
+			if !block.End.After(start) {
+				continue
+			}
+			if !block.Start.Before(end) {
+				break
+			}
+			if block.Start.After(start) {
+				slots = appendSlot(slots, Slot{Start: start, End: block.Start}, minLength)
+			}
+			start = block.End
+		}
+		slots = appendSlot(slots, Slot{Start: start, End: end}, minLength)
+	}
+
+	return slots
+}
+
+// parseFreeRange parses the range to search for free time. Dates alone
+// cover whole days, so "friday" and "mon-wed" work as well as
+// "tomorrow 1pm-5pm".
+func parseFreeRange(input string, clock Clock) (time.Time, time.Time, error) {
+	now := clock.Now()
+	expr, p, err := parseDateExpr(input, now)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	var block *TimeBlock
+	if expr.start == nil && expr.exact == nil {
+		block, err = allDayBlock(expr, p, now)
+	} else {
+		block, err = ParseTimeBlock(input, clock)
+	}
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return *block.Start, *block.End, nil
+}
+
+// appendSlot adds slot to slots if it's at least minLength long
+func appendSlot(slots []Slot, slot Slot, minLength time.Duration) []Slot {
+	if slot.Length() <= 0 || slot.Length() < minLength {
+		return slots
+	}
+	return append(slots, slot)
+}
+
+// NextFreeSlot returns the earliest block of the given length after from
+// that fits in free working time. Todo id's own block counts as free, so
+// that a scheduled todo can be moved.
+func NextFreeSlot(db Store, id int, from time.Time, length time.Duration, options CalendarOptions) (*TimeBlock, error) {
+	to := startOfDay(from).AddDate(0, 0, nextFreeSearchDays)
+	todos, err := db.GetRangeTodos(startOfDay(from), to)
+	if err != nil {
+		return nil, err
+	}
+
+	var others []Todo
+	for _, todo := range todos {
+		if todo.ID != id {
+			others = append(others, todo)
+		}
+	}
+
+	slots := FreeSlots(others, from, to, options, length)
+	if len(slots) == 0 {
+		return nil, fmt.Errorf("no free %s slot in the next %d days", formatDuration(length), nextFreeSearchDays)
+	}
+
+	start := slots[0].Start
+	end := start.Add(length)
+	return &TimeBlock{Start: &start, End: &end}, nil
+}
+
+// roundUp rounds t up to the next multiple of step
+func roundUp(t time.Time, step time.Duration) time.Time {
+	rounded := t.Truncate(step)
+	if rounded.Before(t) {
+		rounded = rounded.Add(step)
+	}
+	return rounded
+}
+
+// formatDuration formats a length of time in hours and minutes, e.g. "1h30m"
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	hours := int(d / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes == 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dh%dm", hours, minutes)
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFreeSlots(t *testing.T) {
+	todos := []Todo{
+		{ID: 1, Title: "Standup", ScheduledStart: at(2026, 10, 19, 9, 0), ScheduledEnd: at(2026, 10, 19, 9, 30)},
+		{ID: 2, Title: "Review", ScheduledStart: at(2026, 10, 19, 9, 15), ScheduledEnd: at(2026, 10, 19, 11, 0)},
+		{ID: 3, Title: "Lunch", ScheduledStart: at(2026, 10, 19, 12, 0), ScheduledEnd: at(2026, 10, 19, 13, 0)},
+		{ID: 4, Title: "Holiday", ScheduledStart: at(2026, 10, 20, 0, 0), ScheduledEnd: at(2026, 10, 21, 0, 0), AllDay: true},
+	}
+
+	slots := FreeSlots(todos, *at(2026, 10, 19, 8, 5), *at(2026, 10, 21, 0, 0), DefaultCalendarOptions(), 30*time.Minute)
+	// from rounds up to the next quarter hour
+	want := []Slot{
+		{Start: *at(2026, 10, 19, 8, 15), End: *at(2026, 10, 19, 9, 0)},
+		{Start: *at(2026, 10, 19, 11, 0), End: *at(2026, 10, 19, 12, 0)},
+		{Start: *at(2026, 10, 19, 13, 0), End: *at(2026, 10, 19, 18, 0)},
+		{Start: *at(2026, 10, 20, 8, 0), End: *at(2026, 10, 20, 18, 0)},
+	}
+	if len(slots) != len(want) {
+		t.Fatalf("FreeSlots = %v, want %v", slots, want)
+	}
+	for i := range want {
+		if !slots[i].Start.Equal(want[i].Start) || !slots[i].End.Equal(want[i].End) {
+			t.Errorf("slot %d = %v-%v, want %v-%v", i, slots[i].Start, slots[i].End, want[i].Start, want[i].End)
+		}
+	}
+}
+
+func TestFreeSlotsKeepWorkingHoursAcrossDST(t *testing.T) {
+	newYork := loadLocation(t, "America/New_York")
+
+	// Clocks go forward on March 8 and back on November 1, 2026
+	for _, from := range []time.Time{
+		time.Date(2026, 3, 8, 0, 0, 0, 0, newYork),
+		time.Date(2026, 11, 1, 0, 0, 0, 0, newYork),
+	} {
+		slots := FreeSlots(nil, from, from.AddDate(0, 0, 1), DefaultCalendarOptions(), 0)
+		if len(slots) != 1 {
+			t.Fatalf("%s: FreeSlots = %v, want one slot", from.Format("Jan 2"), slots)
+		}
+		if slots[0].Start.Hour() != 8 || slots[0].End.Hour() != 18 {
+			t.Errorf("%s: slot = %v-%v, want 8am-6pm on the day clocks change", from.Format("Jan 2"), slots[0].Start, slots[0].End)
+		}
+	}
+}
+
+func TestNextFreeSlot(t *testing.T) {
+	store := newTestStore()
+	addScheduled(t, store, "Standup", at(2026, 10, 19, 10, 0), at(2026, 10, 19, 11, 0))
+	addScheduled(t, store, "Review", at(2026, 10, 19, 11, 30), at(2026, 10, 19, 12, 0))
+
+	block, err := NextFreeSlot(store, 2, testNow, time.Hour, DefaultCalendarOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Review's own block counts as free, so it can move straight after Standup
+	if !block.Start.Equal(*at(2026, 10, 19, 11, 0)) || !block.End.Equal(*at(2026, 10, 19, 12, 0)) {
+		t.Errorf("NextFreeSlot = %v-%v, want 11am-12pm", block.Start, block.End)
+	}
+
+	c, _, out := newTestCLI()
+	c.db = store
+	output := run(c, out, "schedule", "1", "next-free", "2h")
+	assertContains(t, output, "Scheduled todo 1", "12:00pm-2:00pm")
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Priority ranks how urgent a todo is. The zero value means no priority.
+type Priority int
+
+const (
+	PriorityNone Priority = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+)
+
+// ParsePriority parses a priority name: "high", "medium", "low", their first
+// letters, or 1-3 with 1 being the highest
+func ParsePriority(input string) (Priority, error) {
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case "high", "hi", "h", "1":
+		return PriorityHigh, nil
+	case "medium", "med", "m", "2":
+		return PriorityMedium, nil
+	case "low", "lo", "l", "3":
+		return PriorityLow, nil
+	case "none", "":
+		return PriorityNone, nil
+	}
+	return PriorityNone, fmt.Errorf("unknown priority: %s (use high, medium or low)", input)
+}
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityHigh:
+		return "high"
+	case PriorityMedium:
+		return "medium"
+	case PriorityLow:
+		return "low"
+	}
+	return "none"
+}
+
+// joinTags stores tags in a single comma separated column
+func joinTags(tags []string) string {
+	return strings.Join(tags, ",")
+}
+
+// splitTags reverses joinTags
+func splitTags(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// FormatLabels formats a todo's project, tags, priority and estimate for
+// display, e.g. "+work #health !high ~1h30m"
+func FormatLabels(todo Todo) string {
+	var parts []string
+	if todo.Project != "" {
+		parts = append(parts, "+"+todo.Project)
+	}
+	for _, tag := range todo.Tags {
+		parts = append(parts, "#"+tag)
+	}
+	if todo.Priority != PriorityNone {
+		parts = append(parts, "!"+todo.Priority.String())
+	}
+	if todo.Estimate > 0 {
+		parts = append(parts, "~"+formatDuration(todo.Estimate))
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Locale holds the names, words and date orders used to display dates
+type Locale struct {
+	Months      [12]string
+	ShortMonths [12]string
+	Weekdays    [7]string // Sunday first, like time.Weekday
+	ShortDays   [7]string
+
+	DatePattern     string // {d}, {mon} and {y} stand for the day, short month and year
+	DateYearPattern string
+	Clock24         bool // 15:04 rather than 3:04pm
+
+	Today         string
+	Tomorrow      string
+	InOneDay      string
+	InDays        string // Takes the number of days
+	OneDayOverdue string
+	DaysOverdue   string // Takes the number of days
+	AllDay        string
+}
+
+var localeEnglish = &Locale{
+	Months:          [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+	ShortMonths:     [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+	Weekdays:        [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+	ShortDays:       [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+	DatePattern:     "{mon} {d}",
+	DateYearPattern: "{mon} {d}, {y}",
+	Today:           "Today",
+	Tomorrow:        "Tomorrow",
+	InOneDay:        "In 1 day",
+	InDays:          "In %d days",
+	OneDayOverdue:   "1 day overdue",
+	DaysOverdue:     "%d days overdue",
+	AllDay:          "all day",
+}
+
+// locales maps locale names to their settings; see findLocale for matching
+var locales = map[string]*Locale{
+	"en": localeEnglish,
+	"en-gb": {
+		Months:          localeEnglish.Months,
+		ShortMonths:     localeEnglish.ShortMonths,
+		Weekdays:        localeEnglish.Weekdays,
+		ShortDays:       localeEnglish.ShortDays,
+		DatePattern:     "{d} {mon}",
+		DateYearPattern: "{d} {mon} {y}",
+		Clock24:         true,
+		Today:           "Today",
+		Tomorrow:        "Tomorrow",
+		InOneDay:        "In 1 day",
+		InDays:          "In %d days",
+		OneDayOverdue:   "1 day overdue",
+		DaysOverdue:     "%d days overdue",
+		AllDay:          "all day",
+	},
+	"de": {
+		Months:          [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		ShortMonths:     [12]string{"Jan", "Feb", "Mär", "Apr", "Mai", "Jun", "Jul", "Aug", "Sep", "Okt", "Nov", "Dez"},
+		Weekdays:        [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		ShortDays:       [7]string{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+		DatePattern:     "{d}. {mon}",
+		DateYearPattern: "{d}. {mon} {y}",
+		Clock24:         true,
+		Today:           "Heute",
+		Tomorrow:        "Morgen",
+		InOneDay:        "In 1 Tag",
+		InDays:          "In %d Tagen",
+		OneDayOverdue:   "1 Tag überfällig",
+		DaysOverdue:     "%d Tage überfällig",
+		AllDay:          "ganztägig",
+	},
+	"fr": {
+		Months:          [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		ShortMonths:     [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		Weekdays:        [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		ShortDays:       [7]string{"dim", "lun", "mar", "mer", "jeu", "ven", "sam"},
+		DatePattern:     "{d} {mon}",
+		DateYearPattern: "{d} {mon} {y}",
+		Clock24:         true,
+		Today:           "Aujourd'hui",
+		Tomorrow:        "Demain",
+		InOneDay:        "Dans 1 jour",
+		InDays:          "Dans %d jours",
+		OneDayOverdue:   "1 jour de retard",
+		DaysOverdue:     "%d jours de retard",
+		AllDay:          "toute la journée",
+	},
+	"es": {
+		Months:          [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		ShortMonths:     [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+		Weekdays:        [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		ShortDays:       [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+		DatePattern:     "{d} {mon}",
+		DateYearPattern: "{d} {mon} {y}",
+		Clock24:         true,
+		Today:           "Hoy",
+		Tomorrow:        "Mañana",
+		InOneDay:        "En 1 día",
+		InDays:          "En %d días",
+		OneDayOverdue:   "1 día de retraso",
+		DaysOverdue:     "%d días de retraso",
+		AllDay:          "todo el día",
+	},
+}
+
+// activeLocale is used for all date display. Like time.Local, it is set
+// once from the config at startup.
+var activeLocale = localeEnglish
+
+// SetLocale changes the locale used to display dates
+func SetLocale(locale *Locale) {
+	activeLocale = locale
+}
+
+// findLocale looks up a locale such as "de", "en-GB" or "fr_FR.UTF-8",
+// falling back from the region to the language
+func findLocale(name string) (*Locale, error) {
+	if name == "" {
+		return localeEnglish, nil
+	}
+
+	key := strings.ToLower(strings.ReplaceAll(name, "_", "-"))
+	if i := strings.IndexAny(key, ".@"); i >= 0 {
+		key = key[:i]
+	}
+
+	if locale, ok := locales[key]; ok {
+		return locale, nil
+	}
+	if language, _, found := strings.Cut(key, "-"); found {
+		if locale, ok := locales[language]; ok {
+			return locale, nil
+		}
+	}
+
+	var names []string
+	for known := range locales {
+		names = append(names, known)
+	}
+	sort.Strings(names)
+	return nil, fmt.Errorf("unsupported locale %q (supported: %s)", name, strings.Join(names, ", "))
+}
+
+// Month returns the full name of a month
+func (l *Locale) Month(month time.Month) string {
+	return l.Months[month-1]
+}
+
+// Weekday returns the full name of a weekday
+func (l *Locale) Weekday(weekday time.Weekday) string {
+	return l.Weekdays[weekday]
+}
+
+// ShortWeekday returns a weekday abbreviation of at most n characters
+func (l *Locale) ShortWeekday(weekday time.Weekday, n int) string {
+	runes := []rune(l.ShortDays[weekday])
+	if len(runes) > n {
+		runes = runes[:n]
+	}
+	return string(runes)
+}
+
+// Date formats a day without its year, e.g. "Jan 2" or "2. Jan"
+func (l *Locale) Date(t time.Time) string {
+	return l.fillDate(l.DatePattern, t)
+}
+
+// DateYear formats a day with its year, e.g. "Jan 2, 2006"
+func (l *Locale) DateYear(t time.Time) string {
+	return l.fillDate(l.DateYearPattern, t)
+}
+
+// WeekdayDate formats a day with its weekday, e.g. "Monday, Jan 2"
+func (l *Locale) WeekdayDate(t time.Time) string {
+	return l.Weekday(t.Weekday()) + ", " + l.Date(t)
+}
+
+// MonthYear formats a month, e.g. "January 2006"
+func (l *Locale) MonthYear(t time.Time) string {
+	return fmt.Sprintf("%s %d", l.Month(t.Month()), t.Year())
+}
+
+// Clock formats a time of day, e.g. "3:04pm" or "15:04"
+func (l *Locale) Clock(t time.Time) string {
+	if l.Clock24 {
+		return t.Format("15:04")
+	}
+	return t.Format("3:04pm")
+}
+
+// DateTime formats a day and time of day, e.g. "Jan 2 3:04pm"
+func (l *Locale) DateTime(t time.Time) string {
+	return l.Date(t) + " " + l.Clock(t)
+}
+
+func (l *Locale) fillDate(pattern string, t time.Time) string {
+	return strings.NewReplacer(
+		"{d}", fmt.Sprint(t.Day()),
+		"{mon}", l.ShortMonths[t.Month()-1],
+		"{y}", fmt.Sprint(t.Year()),
+	).Replace(pattern)
+}
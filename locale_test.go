@@ -0,0 +1,159 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// withLocale switches the active locale for the rest of the test
+func withLocale(t *testing.T, name string) {
+	t.Helper()
+	locale, err := findLocale(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetLocale(locale)
+	t.Cleanup(func() { SetLocale(localeEnglish) })
+}
+
+// withWeekStart switches the parser's first day of the week for the rest of the test
+func withWeekStart(t *testing.T, weekday time.Weekday) {
+	SetWeekStart(weekday)
+	t.Cleanup(func() { SetWeekStart(time.Sunday) })
+}
+
+func TestFindLocale(t *testing.T) {
+	tests := map[string]*Locale{
+		"":            localeEnglish,
+		"en":          localeEnglish,
+		"de":          locales["de"],
+		"de_AT":       locales["de"],
+		"fr_FR.UTF-8": locales["fr"],
+		"en-GB":       locales["en-gb"],
+	}
+	for name, want := range tests {
+		got, err := findLocale(name)
+		if err != nil {
+			t.Errorf("findLocale(%q): %v", name, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("findLocale(%q) returned the wrong locale", name)
+		}
+	}
+
+	if _, err := findLocale("xx"); err == nil {
+		t.Error("findLocale(\"xx\") should fail")
+	}
+}
+
+func TestLocaleFormats(t *testing.T) {
+	date := day(2026, 3, 5, 14, 30)
+
+	tests := []struct {
+		locale               string
+		date, dateYear, time string
+	}{
+		{locale: "en", date: "Mar 5", dateYear: "Mar 5, 2026", time: "2:30pm"},
+		{locale: "en-GB", date: "5 Mar", dateYear: "5 Mar 2026", time: "14:30"},
+		{locale: "de", date: "5. Mär", dateYear: "5. Mär 2026", time: "14:30"},
+	}
+	for _, tt := range tests {
+		locale, err := findLocale(tt.locale)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := locale.Date(date); got != tt.date {
+			t.Errorf("%s Date = %q, want %q", tt.locale, got, tt.date)
+		}
+		if got := locale.DateYear(date); got != tt.dateYear {
+			t.Errorf("%s DateYear = %q, want %q", tt.locale, got, tt.dateYear)
+		}
+		if got := locale.Clock(date); got != tt.time {
+			t.Errorf("%s Clock = %q, want %q", tt.locale, got, tt.time)
+		}
+	}
+
+	if got := locales["de"].ShortWeekday(time.Wednesday, 2); got != "Mi" {
+		t.Errorf("ShortWeekday = %q, want Mi", got)
+	}
+}
+
+func TestConfigWeekStart(t *testing.T) {
+	options, err := (&Config{WeekStart: "Monday"}).CalendarOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if options.WeekStart != time.Monday {
+		t.Errorf("WeekStart = %v, want Monday", options.WeekStart)
+	}
+
+	if _, err := (&Config{WeekStart: "someday"}).CalendarOptions(); err == nil {
+		t.Error("an unknown weekStart should be rejected")
+	}
+	if _, err := (&Config{Locale: "xx"}).GetLocale(); err == nil {
+		t.Error("an unknown locale should be rejected")
+	}
+}
+
+func TestWeekStartEndsTheWeek(t *testing.T) {
+	clock := NewFixedClock(testNow)
+	sunday := NewFixedClock(day(2026, 10, 25, 10, 0))
+
+	tests := []struct {
+		weekStart time.Weekday
+		clock     Clock
+		input     string
+		want      time.Time
+	}{
+		{weekStart: time.Sunday, clock: clock, input: "eow", want: endOfDayUTC(2026, 10, 24)},
+		{weekStart: time.Monday, clock: clock, input: "eow", want: endOfDayUTC(2026, 10, 25)},
+		{weekStart: time.Monday, clock: clock, input: "end of week", want: endOfDayUTC(2026, 10, 25)},
+		{weekStart: time.Saturday, clock: clock, input: "eow", want: endOfDayUTC(2026, 10, 23)},
+		{weekStart: time.Monday, clock: sunday, input: "this weekend", want: endOfDayUTC(2026, 10, 25)},
+		{weekStart: time.Sunday, clock: sunday, input: "this weekend", want: endOfDayUTC(2026, 10, 31)},
+		{weekStart: time.Monday, clock: clock, input: "next weekend", want: endOfDayUTC(2026, 10, 31)},
+	}
+	for _, tt := range tests {
+		withWeekStart(t, tt.weekStart)
+		got, err := ParseDueDate(tt.input, tt.clock)
+		if err != nil {
+			t.Errorf("ParseDueDate(%q): %v", tt.input, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseDueDate(%q) with weeks from %v = %v, want %v", tt.input, tt.weekStart, got, tt.want)
+		}
+	}
+}
+
+func TestCalendarUsesLocaleAndWeekStart(t *testing.T) {
+	withLocale(t, "de")
+	store := newTestStore()
+
+	options := DefaultCalendarOptions()
+	options.WeekStart = time.Monday
+	output := NewCalendar(store, NewFixedClock(testNow), options, testNow, MonthView).Render()
+	assertContains(t, output, "Oktober 2026")
+
+	monday := strings.Index(output, " Mo ")
+	if monday < 0 || strings.Index(output, " So ") < monday {
+		t.Errorf("weekday header should start on Monday:\n%s", output)
+	}
+
+	options.WeekStart = time.Sunday
+	output = NewCalendar(store, NewFixedClock(testNow), options, testNow, YearView).Render()
+	assertContains(t, output, "So Mo Di Mi Do Fr Sa")
+}
+
+func TestFormatFieldValueUsesLocaleAndZone(t *testing.T) {
+	withLocale(t, "de")
+	berlin := loadLocation(t, "Europe/Berlin")
+
+	due := day(2026, 10, 19, 12, 0)
+	value := encodeTime(&due)
+	if got, want := FormatFieldValue(fieldDueDate, value, berlin), "19. Okt 2026 14:00"; got != want {
+		t.Errorf("FormatFieldValue = %q, want %q", got, want)
+	}
+}
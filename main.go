@@ -12,28 +12,63 @@ func main() {
 		os.Exit(1)
 	}
 
+	locale, err := config.GetLocale()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	SetLocale(locale)
+
+	options, err := config.CalendarOptions()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	SetWeekStart(options.WeekStart)
+
+	// --now is a hidden global flag that pins the clock for previews
+	args, nowValue, err := extractNowFlag(os.Args[1:])
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Show and parse times in the configured zone rather than the system's
+	loc, err := config.Location()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	clock := NewSystemClock(loc)
+	if nowValue != "" {
+		now, err := ParseNowFlag(nowValue, loc)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		clock = NewFixedClock(now)
+	}
+
 	// Ensure database directory exists
 	if err := config.EnsureDatabaseDir(); err != nil {
 		fmt.Printf("Error creating database directory: %v\n", err)
 		os.Exit(1)
 	}
 
-	db, err := NewDB(config.DatabasePath, config.SyncUrl)
+	db, err := NewDB(config)
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
 	}
 	defer db.Close()
 
-	cli := NewCLI(db)
+	cli := NewCLI(db, config, clock)
 
-	if len(os.Args) < 2 {
+	if len(args) < 1 {
 		cli.HandleCommand("ui", nil)
 		return
 	}
 
-	command := os.Args[1]
-	args := os.Args[2:]
-
-	cli.HandleCommand(command, args)
+	cli.HandleCommand(args[0], args[1:])
 }
@@ -0,0 +1,539 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStore is a Store that keeps todos in memory. It mirrors the
+// ordering and filtering of the SQL queries so it can stand in for DB
+// wherever a real database file is not wanted.
+type MemoryStore struct {
+	mu             sync.Mutex
+	todos          []Todo
+	reminders      []Reminder
+	timeEntries    []TimeEntry
+	pomodoros      map[int]int // Completed pomodoros by todo ID
+	filters        map[string]string
+	nextTodoID     int
+	nextReminderID int
+	nextEntryID    int
+	now            func() time.Time // Timestamps for created/updated fields
+	loc            *time.Location   // Zone for day boundaries
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		nextTodoID:     1,
+		nextReminderID: 1,
+		nextEntryID:    1,
+		pomodoros:      make(map[int]int),
+		filters:        make(map[string]string),
+		now:            time.Now,
+		loc:            time.Local,
+	}
+}
+
+// find returns the index of the todo with the given ID, or -1
+func (s *MemoryStore) find(id int) int {
+	for i, todo := range s.todos {
+		if todo.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// filter returns copies of the todos matching keep
+func (s *MemoryStore) filter(keep func(Todo) bool) []Todo {
+	var todos []Todo
+	for _, todo := range s.todos {
+		if keep(todo) {
+			todos = append(todos, todo)
+		}
+	}
+	return todos
+}
+
+// sortByCreated orders todos newest first, like ORDER BY created_at DESC
+func sortByCreated(todos []Todo) {
+	sort.SliceStable(todos, func(i, j int) bool {
+		if !todos[i].CreatedAt.Equal(todos[j].CreatedAt) {
+			return todos[i].CreatedAt.After(todos[j].CreatedAt)
+		}
+		return todos[i].ID > todos[j].ID
+	})
+}
+
+// sortByScheduled orders todos by block start, like ORDER BY scheduled_start ASC
+func sortByScheduled(todos []Todo) {
+	sort.SliceStable(todos, func(i, j int) bool {
+		return todos[i].ScheduledStart.Before(*todos[j].ScheduledStart)
+	})
+}
+
+// sortByDue orders todos by due date, like ORDER BY due_date ASC
+func sortByDue(todos []Todo) {
+	sort.SliceStable(todos, func(i, j int) bool {
+		return todos[i].DueDate.Before(*todos[j].DueDate)
+	})
+}
+
+// sortByWhen orders todos by block start, or due date when unscheduled,
+// like ORDER BY COALESCE(scheduled_start, due_date)
+func sortByWhen(todos []Todo) {
+	when := func(todo Todo) time.Time {
+		if todo.ScheduledStart != nil {
+			return *todo.ScheduledStart
+		}
+		return *todo.DueDate
+	}
+	sort.SliceStable(todos, func(i, j int) bool {
+		return when(todos[i]).Before(when(todos[j]))
+	})
+}
+
+func (s *MemoryStore) AddTodo(todo Todo) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	todo.ID = s.nextTodoID
+	todo.UUID = newUUID()
+	todo.Done = false
+	todo.TimeZone = s.loc.String()
+	todo.CreatedAt = now
+	todo.UpdatedAt = now
+	s.todos = append(s.todos, todo)
+	s.nextTodoID++
+
+	return nil
+}
+
+func (s *MemoryStore) GetTodo(id int) (*Todo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.find(id)
+	if i < 0 {
+		return nil, fmt.Errorf("todo %d not found", id)
+	}
+
+	todo := s.todos[i]
+	return &todo, nil
+}
+
+func (s *MemoryStore) GetAllTodos() ([]Todo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	todos := s.filter(func(Todo) bool { return true })
+	sortByCreated(todos)
+	return todos, nil
+}
+
+func (s *MemoryStore) GetInboxTodos() ([]Todo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	todos := s.filter(func(todo Todo) bool { return todo.ScheduledStart == nil })
+	sortByCreated(todos)
+	return todos, nil
+}
+
+func (s *MemoryStore) GetDateTodos(date time.Time) ([]Todo, error) {
+	start := dayStart(date, s.loc)
+	return s.getTodosBetween(start, start.AddDate(0, 0, 1))
+}
+
+func (s *MemoryStore) GetRangeTodos(startDate, endDate time.Time) ([]Todo, error) {
+	return s.getTodosBetween(dayStart(startDate, s.loc), dayStart(endDate, s.loc).AddDate(0, 0, 1))
+}
+
+func (s *MemoryStore) GetMonthTodos(date time.Time) ([]Todo, error) {
+	start := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, s.loc)
+	return s.getTodosBetween(start, start.AddDate(0, 1, 0))
+}
+
+func (s *MemoryStore) GetDueTodos(startDate, endDate time.Time) ([]Todo, error) {
+	start := dayStart(startDate, s.loc)
+	end := dayStart(endDate, s.loc).AddDate(0, 0, 1)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	todos := s.filter(func(todo Todo) bool {
+		return todo.DueDate != nil && !todo.DueDate.Before(start) && todo.DueDate.Before(end)
+	})
+	sortByDue(todos)
+	return todos, nil
+}
+
+func (s *MemoryStore) GetOverdueTodos(now time.Time) ([]Todo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	todos := s.filter(func(todo Todo) bool { return isOverdue(todo, now) })
+	sortByWhen(todos)
+	return todos, nil
+}
+
+func (s *MemoryStore) GetUpcomingTodos(from, to time.Time) ([]Todo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	todos := s.filter(func(todo Todo) bool {
+		if todo.Done {
+			return false
+		}
+		if blockOverlaps(todo, from, to) {
+			return true
+		}
+		return todo.DueDate != nil && !todo.DueDate.Before(from) && todo.DueDate.Before(to)
+	})
+	sortByWhen(todos)
+	return todos, nil
+}
+
+func (s *MemoryStore) GetSomedayTodos() ([]Todo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	todos := s.filter(func(todo Todo) bool {
+		return !todo.Done && todo.ScheduledStart == nil && todo.DueDate == nil
+	})
+	sortByCreated(todos)
+	return todos, nil
+}
+
+// getTodosBetween mirrors DB.getTodosBetween
+func (s *MemoryStore) getTodosBetween(start, end time.Time) ([]Todo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	todos := s.filter(func(todo Todo) bool {
+		return blockOverlaps(todo, start, end)
+	})
+	sortByScheduled(todos)
+	return todos, nil
+}
+
+func (s *MemoryStore) UpdateTodo(id int, title, description string, dueDate, scheduledStart, scheduledEnd *time.Time, allDay bool, estimate time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.find(id)
+	if i < 0 {
+		return fmt.Errorf("todo %d not found", id)
+	}
+
+	s.todos[i].Title = title
+	s.todos[i].Description = description
+	s.todos[i].DueDate = dueDate
+	s.todos[i].ScheduledStart = scheduledStart
+	s.todos[i].ScheduledEnd = scheduledEnd
+	s.todos[i].AllDay = allDay
+	s.todos[i].Estimate = estimate
+	s.todos[i].UpdatedAt = s.now()
+	return nil
+}
+
+func (s *MemoryStore) DeleteTodo(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.find(id)
+	if i < 0 {
+		return fmt.Errorf("todo %d not found", id)
+	}
+	s.remove(i)
+	return nil
+}
+
+// remove deletes the todo at index i along with everything attached to it.
+// The caller holds the lock.
+func (s *MemoryStore) remove(i int) {
+	id := s.todos[i].ID
+	s.todos = append(s.todos[:i], s.todos[i+1:]...)
+
+	var reminders []Reminder
+	for _, reminder := range s.reminders {
+		if reminder.TodoID != id {
+			reminders = append(reminders, reminder)
+		}
+	}
+	s.reminders = reminders
+
+	var entries []TimeEntry
+	for _, entry := range s.timeEntries {
+		if entry.TodoID != id {
+			entries = append(entries, entry)
+		}
+	}
+	s.timeEntries = entries
+	delete(s.pomodoros, id)
+}
+
+func (s *MemoryStore) ToggleTodo(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.find(id)
+	if i < 0 {
+		return fmt.Errorf("todo %d not found", id)
+	}
+
+	s.todos[i].Done = !s.todos[i].Done
+	s.todos[i].UpdatedAt = s.now()
+	return nil
+}
+
+func (s *MemoryStore) ScheduleTodo(id int, scheduledStart, scheduledEnd *time.Time, allDay bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.find(id)
+	if i < 0 {
+		return fmt.Errorf("todo %d not found", id)
+	}
+
+	s.todos[i].ScheduledStart = scheduledStart
+	s.todos[i].ScheduledEnd = scheduledEnd
+	s.todos[i].AllDay = allDay
+	s.todos[i].UpdatedAt = s.now()
+	return nil
+}
+
+func (s *MemoryStore) ApplyBulkEdit(ids []int, edit BulkEdit) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Check every ID first so that a missing todo changes nothing
+	for _, id := range ids {
+		if s.find(id) < 0 {
+			return fmt.Errorf("todo %d not found", id)
+		}
+	}
+
+	seen := make(map[int]bool)
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		i := s.find(id)
+		if edit.Delete {
+			s.remove(i)
+			continue
+		}
+		s.todos[i] = edit.apply(s.todos[i])
+		s.todos[i].UpdatedAt = s.now()
+	}
+	return nil
+}
+
+func (s *MemoryStore) AddReminder(todoID int, reminder Reminder) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.find(todoID) < 0 {
+		return fmt.Errorf("todo %d not found", todoID)
+	}
+
+	reminder.ID = s.nextReminderID
+	reminder.TodoID = todoID
+	reminder.CreatedAt = s.now()
+	s.reminders = append(s.reminders, reminder)
+	s.nextReminderID++
+
+	return nil
+}
+
+func (s *MemoryStore) GetTodoReminders(todoID int) ([]Reminder, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var reminders []Reminder
+	for _, reminder := range s.reminders {
+		if reminder.TodoID == todoID {
+			reminders = append(reminders, reminder)
+		}
+	}
+	return reminders, nil
+}
+
+func (s *MemoryStore) DueReminders(now time.Time) ([]DueReminder, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var pending []DueReminder
+	for _, reminder := range s.reminders {
+		if reminder.NotifiedAt != nil {
+			continue
+		}
+
+		i := s.find(reminder.TodoID)
+		if i < 0 || s.todos[i].Done {
+			continue
+		}
+
+		pending = append(pending, DueReminder{Reminder: reminder, Todo: s.todos[i]})
+	}
+
+	return selectDueReminders(pending, now), nil
+}
+
+func (s *MemoryStore) MarkReminderNotified(id int, notifiedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.reminders {
+		if s.reminders[i].ID == id {
+			s.reminders[i].NotifiedAt = &notifiedAt
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) StartTimer(todoID int, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.find(todoID) < 0 {
+		return fmt.Errorf("todo %d not found", todoID)
+	}
+
+	s.stopTimer(at)
+	s.timeEntries = append(s.timeEntries, TimeEntry{ID: s.nextEntryID, TodoID: todoID, StartedAt: at})
+	s.nextEntryID++
+	return nil
+}
+
+func (s *MemoryStore) StopTimer(at time.Time) (*TimeEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.stopTimer(at), nil
+}
+
+// stopTimer stops the running entry, if any, and returns a copy of it
+func (s *MemoryStore) stopTimer(at time.Time) *TimeEntry {
+	for i := range s.timeEntries {
+		if s.timeEntries[i].StoppedAt == nil {
+			s.timeEntries[i].StoppedAt = &at
+			entry := s.timeEntries[i]
+			return &entry
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) GetRunningTimer() (*TimeEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, entry := range s.timeEntries {
+		if entry.StoppedAt == nil {
+			return &entry, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *MemoryStore) GetTimeEntries(from, to time.Time) ([]TimeEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var entries []TimeEntry
+	for _, entry := range s.timeEntries {
+		if entry.StartedAt.Before(to) && (entry.StoppedAt == nil || entry.StoppedAt.After(from)) {
+			entries = append(entries, entry)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].StartedAt.Before(entries[j].StartedAt)
+	})
+	return entries, nil
+}
+
+func (s *MemoryStore) LogPomodoro(todoID int, startedAt, completedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pomodoros[todoID]++
+	return nil
+}
+
+func (s *MemoryStore) CountPomodoros(todoID int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.pomodoros[todoID], nil
+}
+
+func (s *MemoryStore) FilterTodos(filter *Filter) ([]Todo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	todos := s.filter(filter.Match)
+	sortByCreated(todos)
+	return todos, nil
+}
+
+func (s *MemoryStore) SaveFilter(name, query string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.filters[name] = query
+	return nil
+}
+
+func (s *MemoryStore) GetSavedFilters() ([]SavedFilter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var filters []SavedFilter
+	for name, query := range s.filters {
+		filters = append(filters, SavedFilter{Name: name, Query: query})
+	}
+	sort.Slice(filters, func(i, j int) bool {
+		return filters[i].Name < filters[j].Name
+	})
+	return filters, nil
+}
+
+func (s *MemoryStore) DeleteFilter(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.filters[name]; !ok {
+		return fmt.Errorf("no filter named %q", name)
+	}
+	delete(s.filters, name)
+	return nil
+}
+
+// SyncEnabled is always false: a memory store has no remote
+func (s *MemoryStore) SyncEnabled() bool {
+	return false
+}
+
+func (s *MemoryStore) Sync() (SyncResult, error) {
+	return SyncResult{}, ErrSyncNotConfigured
+}
+
+func (s *MemoryStore) GetConflicts() ([]SyncConflict, error) {
+	return nil, nil
+}
+
+func (s *MemoryStore) ResolveConflict(id int, keep string) error {
+	return fmt.Errorf("conflict %d not found", id)
+}
+
+func (s *MemoryStore) Close() error {
+	return nil
+}
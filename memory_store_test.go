@@ -0,0 +1,166 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// newTestStore returns an empty MemoryStore in UTC
+func newTestStore() *MemoryStore {
+	store := NewMemoryStore()
+	store.loc = time.UTC
+	return store
+}
+
+// at returns a wall-clock time in UTC
+func at(year int, month time.Month, day, hour, minute int) *time.Time {
+	t := time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+	return &t
+}
+
+// addTodos adds smart-add titles to store, failing the test on a bad one
+func addTodos(t *testing.T, store Store, clock Clock, titles ...string) {
+	t.Helper()
+	for _, title := range titles {
+		parsed, err := ParseSmartAdd(title, clock)
+		if err != nil {
+			t.Fatalf("parsing %q: %v", title, err)
+		}
+		if err := store.AddTodo(parsed.Todo("")); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// titles returns the titles of todos in order
+func titles(todos []Todo) []string {
+	var names []string
+	for _, todo := range todos {
+		names = append(names, todo.Title)
+	}
+	return names
+}
+
+// addScheduled adds a todo with a block, failing the test if it can't
+func addScheduled(t *testing.T, store Store, title string, start, end *time.Time) {
+	t.Helper()
+	if err := store.AddTodo(Todo{Title: title, ScheduledStart: start, ScheduledEnd: end}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMemoryStoreMatchesDB(t *testing.T) {
+	memory, db := newTestStore(), newTestDB(t)
+
+	for _, store := range []Store{memory, db} {
+		addScheduled(t, store, "Inbox item", nil, nil)
+		addScheduled(t, store, "Standup", at(2030, 1, 15, 9, 0), at(2030, 1, 15, 9, 15))
+		addScheduled(t, store, "Review", at(2030, 1, 15, 14, 0), at(2030, 1, 15, 15, 0))
+		addScheduled(t, store, "Planning", at(2030, 1, 17, 10, 0), at(2030, 1, 17, 11, 0))
+		addScheduled(t, store, "Retro", at(2030, 2, 1, 16, 0), at(2030, 2, 1, 17, 0))
+		addScheduled(t, store, "Another inbox item", nil, nil)
+	}
+
+	queries := map[string]func(Store) ([]Todo, error){
+		"all":   Store.GetAllTodos,
+		"inbox": Store.GetInboxTodos,
+		"day":   func(s Store) ([]Todo, error) { return s.GetDateTodos(*at(2030, 1, 15, 12, 0)) },
+		"week":  func(s Store) ([]Todo, error) { return s.GetRangeTodos(*at(2030, 1, 13, 0, 0), *at(2030, 1, 19, 0, 0)) },
+		"month": func(s Store) ([]Todo, error) { return s.GetMonthTodos(*at(2030, 1, 1, 0, 0)) },
+	}
+
+	for name, query := range queries {
+		fromMemory, err := query(memory)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fromDB, err := query(db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(titles(fromMemory), titles(fromDB)) {
+			t.Errorf("%s: memory store gave %q, database gave %q", name, titles(fromMemory), titles(fromDB))
+		}
+	}
+}
+
+func TestMemoryStoreEdits(t *testing.T) {
+	store := newTestStore()
+	addScheduled(t, store, "Buy milk", nil, nil)
+
+	if err := store.ScheduleTodo(1, at(2030, 1, 15, 9, 0), at(2030, 1, 15, 10, 0), false); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.ToggleTodo(1); err != nil {
+		t.Fatal(err)
+	}
+	todo, err := store.GetTodo(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !todo.Done || !todo.ScheduledStart.Equal(*at(2030, 1, 15, 9, 0)) {
+		t.Errorf("got %+v", todo)
+	}
+
+	// Returned todos are copies
+	todo.Title = "Changed"
+	if again, _ := store.GetTodo(1); again.Title != "Buy milk" {
+		t.Errorf("editing a returned todo changed the store: %q", again.Title)
+	}
+
+	if err := store.DeleteTodo(1); err != nil {
+		t.Fatal(err)
+	}
+	for name, err := range map[string]error{
+		"get":      func() error { _, err := store.GetTodo(1); return err }(),
+		"toggle":   store.ToggleTodo(1),
+		"delete":   store.DeleteTodo(1),
+		"schedule": store.ScheduleTodo(1, nil, nil, false),
+	} {
+		if err == nil {
+			t.Errorf("%s on a deleted todo succeeded", name)
+		}
+	}
+}
+
+func TestMultiDayBlocksOverlapEveryDay(t *testing.T) {
+	for name, store := range map[string]Store{"memory": newTestStore(), "database": newTestDB(t)} {
+		// A conference from Tuesday to Thursday
+		block, err := ParseTimeBlock("oct 20-22", NewFixedClock(testNow))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := store.AddTodo(Todo{Title: "Conference", ScheduledStart: block.Start, ScheduledEnd: block.End, AllDay: true}); err != nil {
+			t.Fatal(err)
+		}
+		addScheduled(t, store, "Overnight shift", at(2026, 10, 23, 22, 0), at(2026, 10, 24, 6, 0))
+
+		tests := []struct {
+			name  string
+			query func() ([]Todo, error)
+			want  []string
+		}{
+			{name: "day before", query: func() ([]Todo, error) { return store.GetDateTodos(day(2026, 10, 19, 12, 0)) }},
+			{name: "first day", query: func() ([]Todo, error) { return store.GetDateTodos(day(2026, 10, 20, 12, 0)) }, want: []string{"Conference"}},
+			{name: "middle day", query: func() ([]Todo, error) { return store.GetDateTodos(day(2026, 10, 21, 12, 0)) }, want: []string{"Conference"}},
+			{name: "last day", query: func() ([]Todo, error) { return store.GetDateTodos(day(2026, 10, 22, 12, 0)) }, want: []string{"Conference"}},
+			{name: "day after", query: func() ([]Todo, error) { return store.GetDateTodos(day(2026, 10, 23, 12, 0)) }, want: []string{"Overnight shift"}},
+			{name: "morning after a night", query: func() ([]Todo, error) { return store.GetDateTodos(day(2026, 10, 24, 12, 0)) }, want: []string{"Overnight shift"}},
+			{name: "range", query: func() ([]Todo, error) { return store.GetRangeTodos(day(2026, 10, 21, 0, 0), day(2026, 10, 24, 0, 0)) }, want: []string{"Conference", "Overnight shift"}},
+		}
+		for _, tt := range tests {
+			todos, err := tt.query()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(titles(todos), tt.want) {
+				t.Errorf("%s %s: got %q, want %q", name, tt.name, titles(todos), tt.want)
+			}
+		}
+
+		if todo, _ := store.GetTodo(1); !todo.AllDay {
+			t.Errorf("%s: all-day flag not stored", name)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// relativeMove matches shifts like "+1d", "-2h" or "+1 week"
+var relativeMove = regexp.MustCompile(`^([+-])\s*(\S.*)$`)
+
+// shiftBlock moves a todo's block by a number of days, keeping its clock
+// times even across a daylight saving change
+func shiftBlock(todo Todo, days int) *TimeBlock {
+	start := todo.ScheduledStart.AddDate(0, 0, days)
+	end := todo.ScheduledEnd.AddDate(0, 0, days)
+	return &TimeBlock{Start: &start, End: &end, AllDay: todo.AllDay}
+}
+
+// MoveBlock works out where a todo's block lands when moved to target.
+// Targets are shifts ("+1d", "-2h", "+1w"), days ("friday", "dec 24")
+// which keep the block's clock times and length, or times ("friday 2pm")
+// which keep its length unless they give their own end.
+func MoveBlock(todo Todo, target string, clock Clock) (*TimeBlock, error) {
+	if todo.ScheduledStart == nil || todo.ScheduledEnd == nil {
+		return nil, fmt.Errorf("todo %d isn't scheduled", todo.ID)
+	}
+	target = strings.TrimSpace(target)
+	length := todo.ScheduledEnd.Sub(*todo.ScheduledStart)
+
+	if m := relativeMove.FindStringSubmatch(target); m != nil {
+		offset, err := ParseDuration(m[2])
+		if err != nil {
+			return nil, err
+		}
+		if m[1] == "-" {
+			offset = -offset
+		}
+		if offset%(24*time.Hour) == 0 {
+			return shiftBlock(todo, int(offset/(24*time.Hour))), nil
+		}
+		if todo.AllDay {
+			return nil, fmt.Errorf("all-day blocks move by whole days")
+		}
+		start := todo.ScheduledStart.Add(offset)
+		end := start.Add(length)
+		return &TimeBlock{Start: &start, End: &end}, nil
+	}
+
+	expr, _, err := parseDateExpr(target, clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// A day alone keeps the block's times
+	if expr.start == nil && expr.exact == nil && expr.end == nil && expr.duration == 0 && expr.lastDate == nil && !expr.allDay {
+		day := expr.day(clock.Now())
+		days := int(startOfDay(day).Sub(startOfDay(*todo.ScheduledStart)).Round(24*time.Hour) / (24 * time.Hour))
+		return shiftBlock(todo, days), nil
+	}
+
+	if todo.AllDay {
+		return ParseTimeBlock(target, clock)
+	}
+	return ParseTimeBlockFor(target, clock, length)
+}
+
+// isOverdue reports whether an unfinished todo was due before now or its
+// block ended before now
+func isOverdue(todo Todo, now time.Time) bool {
+	if todo.Done {
+		return false
+	}
+	if todo.DueDate != nil && todo.DueDate.Before(now) {
+		return true
+	}
+	return todo.ScheduledEnd != nil && !todo.ScheduledEnd.After(now)
+}
+
+// Postponed describes a todo moved by Postpone
+type Postponed struct {
+	Todo  Todo
+	Due   *time.Time // The new due date, if it moved
+	Block *TimeBlock // The new block, if it moved
+}
+
+// Postpone moves whatever has slipped on todo to day: a past block moves
+// there keeping its times, and a past due date keeps its time of day.
+// With all set, the block and due date move even if they haven't slipped.
+func Postpone(db Store, todo Todo, day, now time.Time, all bool) (*Postponed, error) {
+	postponed := &Postponed{Todo: todo}
+	due := todo.DueDate
+	if due != nil && (all || due.Before(now)) {
+		moved := time.Date(day.Year(), day.Month(), day.Day(), due.Hour(), due.Minute(), due.Second(), 0, day.Location())
+		due = &moved
+		postponed.Due = due
+	}
+
+	start, end := todo.ScheduledStart, todo.ScheduledEnd
+	if end != nil && (all || !end.After(now)) {
+		days := int(startOfDay(day).Sub(startOfDay(*start)).Round(24*time.Hour) / (24 * time.Hour))
+		postponed.Block = shiftBlock(todo, days)
+		start, end = postponed.Block.Start, postponed.Block.End
+	}
+
+	if postponed.Due == nil && postponed.Block == nil {
+		return nil, nil
+	}
+
+	err := db.UpdateTodo(todo.ID, todo.Title, todo.Description, due, start, end, todo.AllDay, todo.Estimate)
+	if err != nil {
+		return nil, err
+	}
+	return postponed, nil
+}
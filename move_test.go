@@ -0,0 +1,129 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMoveBlock(t *testing.T) {
+	// A Monday 2-3pm block, with testNow on the same Monday morning
+	todo := Todo{ID: 1, Title: "Review", ScheduledStart: at(2026, 10, 19, 14, 0), ScheduledEnd: at(2026, 10, 19, 15, 0)}
+
+	tests := []struct {
+		target     string
+		start, end time.Time
+	}{
+		{target: "+1d", start: day(2026, 10, 20, 14, 0), end: day(2026, 10, 20, 15, 0)},
+		{target: "-2h", start: day(2026, 10, 19, 12, 0), end: day(2026, 10, 19, 13, 0)},
+		{target: "+ 1 week", start: day(2026, 10, 26, 14, 0), end: day(2026, 10, 26, 15, 0)},
+		{target: "friday", start: day(2026, 10, 23, 14, 0), end: day(2026, 10, 23, 15, 0)},
+		{target: "friday 9am", start: day(2026, 10, 23, 9, 0), end: day(2026, 10, 23, 10, 0)},
+		{target: "friday 9-11am", start: day(2026, 10, 23, 9, 0), end: day(2026, 10, 23, 11, 0)},
+	}
+	for _, tt := range tests {
+		block, err := MoveBlock(todo, tt.target, NewFixedClock(testNow))
+		if err != nil {
+			t.Errorf("MoveBlock(%q): %v", tt.target, err)
+			continue
+		}
+		if !block.Start.Equal(tt.start) || !block.End.Equal(tt.end) {
+			t.Errorf("MoveBlock(%q) = %v-%v, want %v-%v", tt.target, block.Start, block.End, tt.start, tt.end)
+		}
+	}
+
+	if _, err := MoveBlock(Todo{ID: 2}, "+1d", NewFixedClock(testNow)); err == nil {
+		t.Error("moving an unscheduled todo should fail")
+	}
+	allDay := Todo{ID: 3, ScheduledStart: at(2026, 10, 19, 0, 0), ScheduledEnd: at(2026, 10, 20, 0, 0), AllDay: true}
+	if _, err := MoveBlock(allDay, "+2h", NewFixedClock(testNow)); err == nil {
+		t.Error("moving an all-day block by hours should fail")
+	}
+}
+
+func TestMoveBlockKeepsClockTimesAcrossDST(t *testing.T) {
+	newYork := loadLocation(t, "America/New_York")
+	start := time.Date(2026, 3, 7, 9, 0, 0, 0, newYork)
+	end := start.Add(time.Hour)
+	todo := Todo{ID: 1, ScheduledStart: &start, ScheduledEnd: &end}
+
+	block, err := MoveBlock(todo, "+1d", NewFixedClock(start))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if block.Start.Hour() != 9 || block.End.Hour() != 10 || block.Start.Day() != 8 {
+		t.Errorf("moved over the clock change to %v-%v, want 9-10am on Mar 8", block.Start, block.End)
+	}
+}
+
+func TestPostpone(t *testing.T) {
+	store := newTestStore()
+	addScheduled(t, store, "Slipped", at(2026, 10, 16, 9, 0), at(2026, 10, 16, 10, 0))
+	addScheduled(t, store, "Upcoming", at(2026, 10, 21, 9, 0), at(2026, 10, 21, 10, 0))
+	if err := store.AddTodo(Todo{Title: "Late", DueDate: at(2026, 10, 17, 17, 0)}); err != nil {
+		t.Fatal(err)
+	}
+	tomorrow := *at(2026, 10, 20, 0, 0)
+
+	for id := 1; id <= 3; id++ {
+		todo, _ := store.GetTodo(id)
+		postponed, err := Postpone(store, *todo, tomorrow, testNow, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (postponed == nil) != (id == 2) {
+			t.Errorf("todo %d postponed = %+v; only slipped todos should move", id, postponed)
+		}
+	}
+
+	slipped, _ := store.GetTodo(1)
+	if !slipped.ScheduledStart.Equal(*at(2026, 10, 20, 9, 0)) || !slipped.ScheduledEnd.Equal(*at(2026, 10, 20, 10, 0)) {
+		t.Errorf("slipped block moved to %v-%v, want 9-10am tomorrow", slipped.ScheduledStart, slipped.ScheduledEnd)
+	}
+	late, _ := store.GetTodo(3)
+	if !late.DueDate.Equal(*at(2026, 10, 20, 17, 0)) {
+		t.Errorf("late todo due %v, want 5pm tomorrow", late.DueDate)
+	}
+
+	// With all set, blocks that haven't slipped move too
+	upcoming, _ := store.GetTodo(2)
+	if _, err := Postpone(store, *upcoming, *at(2026, 10, 23, 0, 0), testNow, true); err != nil {
+		t.Fatal(err)
+	}
+	upcoming, _ = store.GetTodo(2)
+	if !upcoming.ScheduledStart.Equal(*at(2026, 10, 23, 9, 0)) {
+		t.Errorf("upcoming block moved to %v, want 9am Friday", upcoming.ScheduledStart)
+	}
+}
+
+func TestCLIMoveAndPostpone(t *testing.T) {
+	c, store, out := newTestCLI()
+	addScheduled(t, store, "Review", at(2026, 10, 19, 14, 0), at(2026, 10, 19, 15, 0))
+	addScheduled(t, store, "Slipped", at(2026, 10, 16, 9, 0), at(2026, 10, 16, 10, 0))
+
+	assertContains(t, run(c, out, "move", "1", "+1d"), "Oct 20 2:00pm-3:00pm")
+	assertContains(t, run(c, out, "postpone", "--overdue", "tomorrow"), "[2] Slipped", "Postponed 1 todos")
+	assertContains(t, run(c, out, "postpone", "--overdue", "tomorrow"), "Nothing is overdue")
+
+	assertContains(t, run(c, out, "unschedule", "1"), "back to the inbox")
+	if todo, _ := store.GetTodo(1); todo.ScheduledStart != nil {
+		t.Error("unschedule should clear the block")
+	}
+}
+
+func TestTuiMoveKeys(t *testing.T) {
+	store := newTestStore()
+	addScheduled(t, store, "Review", at(2026, 10, 19, 14, 0), at(2026, 10, 19, 15, 0))
+	addScheduled(t, store, "Inbox item", nil, nil)
+	m := newTestTui(store)
+
+	// Moving an inbox todo has nothing to shift
+	press(m, "i", ">")
+
+	m = press(m, "t", ">")
+	if todo, _ := store.GetTodo(1); !todo.ScheduledStart.Equal(*at(2026, 10, 20, 14, 0)) {
+		t.Errorf("> moved the block to %v, want tomorrow", todo.ScheduledStart)
+	}
+	if len(m.todos) != 0 {
+		t.Errorf("the moved todo should leave today's list, got %q", titles(m.todos))
+	}
+}
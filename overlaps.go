@@ -0,0 +1,91 @@
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+// Overlap is a pair of scheduled todos whose blocks share some time
+type Overlap struct {
+	First  Todo
+	Second Todo
+}
+
+// isTimedBlock reports whether a todo holds a block that can be
+// double-booked. Finished todos, all-day blocks and blocks without an end
+// don't take up time.
+func isTimedBlock(todo Todo) bool {
+	return !todo.Done && !todo.AllDay &&
+		todo.ScheduledStart != nil && todo.ScheduledEnd != nil &&
+		todo.ScheduledEnd.After(*todo.ScheduledStart)
+}
+
+// blocksOverlap reports whether two todos' timed blocks overlap
+func blocksOverlap(a, b Todo) bool {
+	if !isTimedBlock(a) || !isTimedBlock(b) {
+		return false
+	}
+	return a.ScheduledStart.Before(*b.ScheduledEnd) && b.ScheduledStart.Before(*a.ScheduledEnd)
+}
+
+// FindOverlaps returns every overlapping pair of blocks among todos,
+// ordered by when the overlap begins
+func FindOverlaps(todos []Todo) []Overlap {
+	var blocks []Todo
+	for _, todo := range todos {
+		if isTimedBlock(todo) {
+			blocks = append(blocks, todo)
+		}
+	}
+	sort.SliceStable(blocks, func(i, j int) bool {
+		return blocks[i].ScheduledStart.Before(*blocks[j].ScheduledStart)
+	})
+
+	var overlaps []Overlap
+	for i, first := range blocks {
+		for _, second := range blocks[i+1:] {
+			// Later blocks start even later, so none of them can overlap
+			if !second.ScheduledStart.Before(*first.ScheduledEnd) {
+				break
+			}
+			overlaps = append(overlaps, Overlap{First: first, Second: second})
+		}
+	}
+
+	sort.SliceStable(overlaps, func(i, j int) bool {
+		return overlaps[i].Second.ScheduledStart.Before(*overlaps[j].Second.ScheduledStart)
+	})
+	return overlaps
+}
+
+// overlappingIDs returns the IDs of the todos that overlap another of todos
+func overlappingIDs(todos []Todo) map[int]bool {
+	ids := make(map[int]bool)
+	for _, overlap := range FindOverlaps(todos) {
+		ids[overlap.First.ID] = true
+		ids[overlap.Second.ID] = true
+	}
+	return ids
+}
+
+// ScheduleConflicts returns the todos whose blocks would overlap todo id if
+// it were scheduled from start to end
+func ScheduleConflicts(db Store, id int, start, end *time.Time, allDay bool) ([]Todo, error) {
+	block := Todo{ID: id, ScheduledStart: start, ScheduledEnd: end, AllDay: allDay}
+	if !isTimedBlock(block) {
+		return nil, nil
+	}
+
+	todos, err := db.GetRangeTodos(startOfDay(*start), startOfDay(*end))
+	if err != nil {
+		return nil, err
+	}
+
+	var conflicts []Todo
+	for _, todo := range todos {
+		if todo.ID != id && blocksOverlap(block, todo) {
+			conflicts = append(conflicts, todo)
+		}
+	}
+	return conflicts, nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFindOverlaps(t *testing.T) {
+	todos := []Todo{
+		{ID: 1, Title: "Standup", ScheduledStart: at(2026, 10, 19, 9, 0), ScheduledEnd: at(2026, 10, 19, 10, 0)},
+		{ID: 2, Title: "Review", ScheduledStart: at(2026, 10, 19, 9, 30), ScheduledEnd: at(2026, 10, 19, 11, 0)},
+		{ID: 3, Title: "Lunch", ScheduledStart: at(2026, 10, 19, 11, 0), ScheduledEnd: at(2026, 10, 19, 12, 0)},
+		{ID: 4, Title: "Deep work", ScheduledStart: at(2026, 10, 19, 8, 0), ScheduledEnd: at(2026, 10, 19, 12, 0)},
+		{ID: 5, Title: "Done call", ScheduledStart: at(2026, 10, 19, 9, 0), ScheduledEnd: at(2026, 10, 19, 10, 0), Done: true},
+		{ID: 6, Title: "Holiday", ScheduledStart: at(2026, 10, 19, 0, 0), ScheduledEnd: at(2026, 10, 20, 0, 0), AllDay: true},
+		{ID: 7, Title: "Reminder", ScheduledStart: at(2026, 10, 19, 9, 15)},
+	}
+
+	var got [][2]int
+	for _, overlap := range FindOverlaps(todos) {
+		got = append(got, [2]int{overlap.First.ID, overlap.Second.ID})
+	}
+
+	// Ordered by when the overlap begins; back-to-back blocks don't overlap
+	want := [][2]int{{4, 1}, {4, 2}, {1, 2}, {4, 3}}
+	if !slices.Equal(got, want) {
+		t.Errorf("FindOverlaps = %v, want %v", got, want)
+	}
+
+	if ids := overlappingIDs(todos[1:3]); len(ids) != 0 {
+		t.Errorf("adjacent blocks reported as overlapping: %v", ids)
+	}
+}
+
+func TestScheduleConflicts(t *testing.T) {
+	store := newTestStore()
+	addScheduled(t, store, "Standup", at(2026, 10, 19, 9, 0), at(2026, 10, 19, 10, 0))
+	addScheduled(t, store, "Late night", at(2026, 10, 19, 23, 0), at(2026, 10, 20, 1, 0))
+
+	conflicts, err := ScheduleConflicts(store, 1, at(2026, 10, 19, 9, 0), at(2026, 10, 19, 10, 0), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conflicts) != 0 {
+		t.Errorf("a block shouldn't conflict with itself: %v", titles(conflicts))
+	}
+
+	conflicts, err = ScheduleConflicts(store, 3, at(2026, 10, 20, 0, 30), at(2026, 10, 20, 2, 0), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := titles(conflicts); !slices.Equal(got, []string{"Late night"}) {
+		t.Errorf("conflicts = %v, want the block running past midnight", got)
+	}
+
+	conflicts, err = ScheduleConflicts(store, 3, at(2026, 10, 19, 0, 0), at(2026, 10, 20, 0, 0), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conflicts) != 0 {
+		t.Errorf("all-day blocks shouldn't conflict: %v", titles(conflicts))
+	}
+}
+
+func TestCLIScheduleRefusesOverlaps(t *testing.T) {
+	c, store, out := newTestCLI()
+	addScheduled(t, store, "Standup", at(2026, 10, 19, 9, 0), at(2026, 10, 19, 10, 0))
+	if err := store.AddTodo(Todo{Title: "Review"}); err != nil {
+		t.Fatal(err)
+	}
+
+	output := run(c, out, "schedule", "2", "9:30am-11am")
+	assertContains(t, output, "overlaps", "[1] Standup", "--force")
+	if todo, _ := store.GetTodo(2); todo.ScheduledStart != nil {
+		t.Error("an overlapping block should not be scheduled without --force")
+	}
+
+	output = run(c, out, "schedule", "2", "9:30am-11am", "--force")
+	assertContains(t, output, "Scheduled todo 2")
+	if todo, _ := store.GetTodo(2); todo.ScheduledStart == nil {
+		t.Error("--force should schedule the block")
+	}
+
+	output = run(c, out, "overlaps", "--day")
+	assertContains(t, output, "Overlapping blocks", "Standup", "Review")
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+// defaultPlanLength is how long a block the planner gives a todo without
+// an estimate
+const defaultPlanLength = time.Hour
+
+// PlannedBlock is a time block proposed for an inbox todo
+type PlannedBlock struct {
+	Todo  Todo
+	Start time.Time
+	End   time.Time
+}
+
+// Late reports whether the block ends after the todo is due
+func (b PlannedBlock) Late() bool {
+	return b.Todo.DueDate != nil && b.End.After(*b.Todo.DueDate)
+}
+
+// Plan is a proposed schedule for the inbox
+type Plan struct {
+	Blocks   []PlannedBlock
+	Unplaced []Todo // Todos with no free slot long enough
+}
+
+// planLength returns how long a block the planner gives todo
+func planLength(todo Todo) time.Duration {
+	if todo.Estimate > 0 {
+		return todo.Estimate
+	}
+	return defaultPlanLength
+}
+
+// sortForPlan orders todos the way the planner places them: soonest due
+// first, then highest priority, then oldest
+func sortForPlan(todos []Todo) {
+	sort.SliceStable(todos, func(i, j int) bool {
+		a, b := todos[i], todos[j]
+		switch {
+		case a.DueDate != nil && b.DueDate == nil:
+			return true
+		case a.DueDate == nil && b.DueDate != nil:
+			return false
+		case a.DueDate != nil && !a.DueDate.Equal(*b.DueDate):
+			return a.DueDate.Before(*b.DueDate)
+		case a.Priority != b.Priority:
+			return a.Priority > b.Priority
+		}
+		return a.ID < b.ID
+	})
+}
+
+// BuildPlan packs the unfinished inbox todos into free working time between
+// from and to. Each todo, in sortForPlan order, takes the earliest free slot
+// it fits in, so the same inbox and calendar always give the same plan.
+func BuildPlan(db Store, from, to time.Time, options CalendarOptions) (*Plan, error) {
+	inbox, err := db.GetInboxTodos()
+	if err != nil {
+		return nil, err
+	}
+
+	busy, err := db.GetRangeTodos(startOfDay(from), to)
+	if err != nil {
+		return nil, err
+	}
+
+	var todos []Todo
+	for _, todo := range inbox {
+		if !todo.Done {
+			todos = append(todos, todo)
+		}
+	}
+	sortForPlan(todos)
+
+	plan := &Plan{}
+	for _, todo := range todos {
+		length := planLength(todo)
+		slots := FreeSlots(busy, from, to, options, length)
+		if len(slots) == 0 {
+			plan.Unplaced = append(plan.Unplaced, todo)
+			continue
+		}
+
+		block := PlannedBlock{Todo: todo, Start: slots[0].Start, End: slots[0].Start.Add(length)}
+		plan.Blocks = append(plan.Blocks, block)
+
+		// Later todos have to fit around this one
+		placed := todo
+		placed.ScheduledStart = &block.Start
+		placed.ScheduledEnd = &block.End
+		busy = append(busy, placed)
+	}
+
+	sort.SliceStable(plan.Blocks, func(i, j int) bool {
+		return plan.Blocks[i].Start.Before(plan.Blocks[j].Start)
+	})
+	return plan, nil
+}
+
+// Apply schedules every block in the plan
+func (p *Plan) Apply(db Store) error {
+	for _, block := range p.Blocks {
+		start, end := block.Start, block.End
+		if err := db.ScheduleTodo(block.Todo.ID, &start, &end, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// addPlanInbox fills store with a busy block at 10-11am on testNow's day
+// and an inbox of todos in need of a plan
+func addPlanInbox(t *testing.T, store Store) {
+	t.Helper()
+	addScheduled(t, store, "Standup", at(2026, 10, 19, 10, 0), at(2026, 10, 19, 11, 0))
+	for _, todo := range []Todo{
+		{Title: "Low"},
+		{Title: "High", Priority: PriorityHigh},
+		{Title: "Due soon", DueDate: at(2026, 10, 19, 11, 30)},
+		{Title: "Done"},
+	} {
+		if err := store.AddTodo(todo); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.ToggleTodo(5); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuildPlan(t *testing.T) {
+	store := newTestStore()
+	addPlanInbox(t, store)
+
+	plan, err := BuildPlan(store, testNow, *at(2026, 10, 20, 0, 0), DefaultCalendarOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, block := range plan.Blocks {
+		got = append(got, block.Todo.Title+" "+block.Start.Format("15:04")+"-"+block.End.Format("15:04"))
+	}
+	// Soonest due first, then highest priority, each after the busy block
+	want := []string{"Due soon 11:00-12:00", "High 12:00-13:00", "Low 13:00-14:00"}
+	if !slices.Equal(got, want) {
+		t.Errorf("plan = %v, want %v", got, want)
+	}
+	if !plan.Blocks[0].Late() || plan.Blocks[1].Late() {
+		t.Error("only the block ending after its due date should be late")
+	}
+
+	// Only two hours left in the day: the last todo doesn't fit
+	plan, err = BuildPlan(store, *at(2026, 10, 19, 15, 0), *at(2026, 10, 20, 0, 0), DefaultCalendarOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Blocks) != 3 || len(plan.Unplaced) != 0 {
+		t.Fatalf("plan from 3pm = %d blocks, %d unplaced", len(plan.Blocks), len(plan.Unplaced))
+	}
+	plan, err = BuildPlan(store, *at(2026, 10, 19, 16, 0), *at(2026, 10, 20, 0, 0), DefaultCalendarOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := titles(plan.Unplaced); !slices.Equal(got, []string{"Low"}) {
+		t.Errorf("unplaced = %v, want [Low]", got)
+	}
+}
+
+func TestCLIPlanAsksBeforeApplying(t *testing.T) {
+	c, store, out := newTestCLI()
+	addPlanInbox(t, store)
+
+	c.in = strings.NewReader("n\n")
+	output := run(c, out, "plan")
+	assertContains(t, output, "Plan for", "+ [4] Due soon", "(after due", "Plan not applied")
+	if inbox, _ := store.GetInboxTodos(); len(inbox) != 4 {
+		t.Errorf("declining the plan left %d inbox todos, want 4", len(inbox))
+	}
+
+	output = run(c, out, "plan", "--apply")
+	assertContains(t, output, "Scheduled 3 todos")
+	if inbox, _ := store.GetInboxTodos(); len(inbox) != 1 {
+		t.Errorf("applying the plan left %d inbox todos, want only the done one", len(inbox))
+	}
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReminderAnchor identifies what a reminder's time is measured from
+type ReminderAnchor string
+
+const (
+	ReminderAtTime      ReminderAnchor = "at"    // Fires at an absolute time
+	ReminderBeforeStart ReminderAnchor = "start" // Fires an offset before the scheduled start
+	ReminderBeforeDue   ReminderAnchor = "due"   // Fires an offset before the due date
+)
+
+// Reminder represents an alert attached to a single todo
+type Reminder struct {
+	ID         int
+	TodoID     int
+	Anchor     ReminderAnchor
+	Offset     time.Duration // How long before the anchor to fire (relative reminders)
+	RemindAt   *time.Time    // When to fire (absolute reminders)
+	NotifiedAt *time.Time    // Set once the reminder has been delivered
+	CreatedAt  time.Time
+}
+
+// DueReminder pairs a reminder that should fire with its todo
+type DueReminder struct {
+	Reminder Reminder
+	Todo     Todo
+	FireAt   time.Time
+}
+
+// FireTime returns when the reminder should fire for the given todo,
+// or nil if the todo has no value for the reminder's anchor
+func (r Reminder) FireTime(todo Todo) *time.Time {
+	var anchor *time.Time
+
+	switch r.Anchor {
+	case ReminderAtTime:
+		return r.RemindAt
+	case ReminderBeforeStart:
+		anchor = todo.ScheduledStart
+	case ReminderBeforeDue:
+		anchor = todo.DueDate
+	}
+
+	if anchor == nil {
+		return nil
+	}
+
+	fireAt := anchor.Add(-r.Offset)
+	return &fireAt
+}
+
+// selectDueReminders keeps the unsent reminders whose fire time is at or
+// before now, ordered by fire time
+func selectDueReminders(pending []DueReminder, now time.Time) []DueReminder {
+	var due []DueReminder
+	for _, item := range pending {
+		// Relative reminders whose anchor is unset (e.g. an unscheduled todo) never fire
+		fireAt := item.Reminder.FireTime(item.Todo)
+		if fireAt == nil || fireAt.After(now) {
+			continue
+		}
+
+		item.FireAt = *fireAt
+		due = append(due, item)
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].FireAt.Before(due[j].FireAt)
+	})
+
+	return due
+}
+
+// ParseReminder parses reminder expressions like:
+// "15m before start", "1 day before due", "at start", "tomorrow 8am"
+func ParseReminder(input string, clock Clock) (*Reminder, error) {
+	input = strings.TrimSpace(strings.ToLower(input))
+	if input == "" {
+		return nil, fmt.Errorf("reminder is required")
+	}
+
+	// Pattern: "AMOUNT UNIT before start|due"
+	offsetPattern := regexp.MustCompile(`^(\d+)\s*(minute|min|m|hour|hr|h|day|d|week|w)s?\s+before\s+(start|due)$`)
+
+	// Pattern: "at start" or "at due"
+	anchorPattern := regexp.MustCompile(`^at\s+(start|due)$`)
+
+	if matches := offsetPattern.FindStringSubmatch(input); matches != nil {
+		amount, err := strconv.Atoi(matches[1])
+		if err != nil {
+			return nil, err
+		}
+
+		var unit time.Duration
+		switch matches[2][0] {
+		case 'm':
+			unit = time.Minute
+		case 'h':
+			unit = time.Hour
+		case 'd':
+			unit = 24 * time.Hour
+		case 'w':
+			unit = 7 * 24 * time.Hour
+		}
+
+		return &Reminder{
+			Anchor: ReminderAnchor(matches[3]),
+			Offset: time.Duration(amount) * unit,
+		}, nil
+	}
+
+	if matches := anchorPattern.FindStringSubmatch(input); matches != nil {
+		return &Reminder{Anchor: ReminderAnchor(matches[1])}, nil
+	}
+
+	// Otherwise treat it as an absolute "DATE TIME"
+	remindAt, err := parseDateTime(input, clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Reminder{Anchor: ReminderAtTime, RemindAt: remindAt}, nil
+}
+
+// parseDateTime parses a date and time, e.g. "tomorrow 8am" or "in 2h".
+// A lone time is taken to mean today.
+func parseDateTime(input string, now time.Time) (*time.Time, error) {
+	expr, p, err := parseDateExpr(input, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if expr.end != nil {
+		return nil, p.errorAt(expr.endToken, "expected a single time but got a range:")
+	}
+	if expr.exact != nil {
+		return expr.exact, nil
+	}
+	if expr.start == nil {
+		return nil, fmt.Errorf("a time is required, e.g. %q", "tomorrow 8am")
+	}
+
+	at := expr.start.resolve().on(expr.day(now))
+	return &at, nil
+}
+
+// FormatReminder formats a reminder for display
+func FormatReminder(reminder Reminder) string {
+	var text string
+
+	switch reminder.Anchor {
+	case ReminderAtTime:
+		if reminder.RemindAt == nil {
+			return ""
+		}
+		text = activeLocale.DateTime(*reminder.RemindAt)
+	case ReminderBeforeStart, ReminderBeforeDue:
+		if reminder.Offset == 0 {
+			text = fmt.Sprintf("at %s", reminder.Anchor)
+		} else {
+			text = fmt.Sprintf("%s before %s", formatOffset(reminder.Offset), reminder.Anchor)
+		}
+	}
+
+	if reminder.NotifiedAt != nil {
+		text += " (sent)"
+	}
+
+	return text
+}
+
+// formatOffset formats a reminder offset using the largest whole unit
+func formatOffset(offset time.Duration) string {
+	switch {
+	case offset%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", int(offset/(24*time.Hour)))
+	case offset%time.Hour == 0:
+		return fmt.Sprintf("%dh", int(offset/time.Hour))
+	default:
+		return fmt.Sprintf("%dm", int(offset/time.Minute))
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseReminder(t *testing.T) {
+	clock := NewFixedClock(testNow)
+
+	tests := []struct {
+		input    string
+		anchor   ReminderAnchor
+		offset   time.Duration
+		remindAt *time.Time
+	}{
+		{input: "15m before start", anchor: ReminderBeforeStart, offset: 15 * time.Minute},
+		{input: "2 hours before start", anchor: ReminderBeforeStart, offset: 2 * time.Hour},
+		{input: "1 day before due", anchor: ReminderBeforeDue, offset: 24 * time.Hour},
+		{input: "1w before due", anchor: ReminderBeforeDue, offset: 7 * 24 * time.Hour},
+		{input: "At Start", anchor: ReminderBeforeStart},
+		{input: "tomorrow 8am", anchor: ReminderAtTime, remindAt: at(2026, 10, 20, 8, 0)},
+		{input: "3pm", anchor: ReminderAtTime, remindAt: at(2026, 10, 19, 15, 0)},
+	}
+	for _, tt := range tests {
+		reminder, err := ParseReminder(tt.input, clock)
+		if err != nil {
+			t.Errorf("ParseReminder(%q): %v", tt.input, err)
+			continue
+		}
+		if reminder.Anchor != tt.anchor || reminder.Offset != tt.offset {
+			t.Errorf("ParseReminder(%q) = %s %v, want %s %v", tt.input, reminder.Anchor, reminder.Offset, tt.anchor, tt.offset)
+		}
+		if (reminder.RemindAt == nil) != (tt.remindAt == nil) || (tt.remindAt != nil && !reminder.RemindAt.Equal(*tt.remindAt)) {
+			t.Errorf("ParseReminder(%q) fires at %v, want %v", tt.input, reminder.RemindAt, tt.remindAt)
+		}
+	}
+
+	for _, input := range []string{"", "5 fortnights before due", "15m after start", "2pm-3pm"} {
+		if _, err := ParseReminder(input, clock); err == nil {
+			t.Errorf("ParseReminder(%q) should fail", input)
+		}
+	}
+}
+
+func TestDueReminders(t *testing.T) {
+	for name, store := range map[string]Store{"memory": newTestStore(), "database": newTestDB(t)} {
+		t.Run(name, func(t *testing.T) {
+			addScheduled(t, store, "Standup", at(2026, 10, 19, 10, 30), at(2026, 10, 19, 11, 0))
+			addScheduled(t, store, "Inbox item", nil, nil)
+
+			reminders := []struct {
+				todoID   int
+				reminder Reminder
+			}{
+				{todoID: 1, reminder: Reminder{Anchor: ReminderBeforeStart, Offset: 15 * time.Minute}},
+				{todoID: 1, reminder: Reminder{Anchor: ReminderAtTime, RemindAt: at(2026, 10, 19, 9, 0)}},
+				// No due date, so it never fires
+				{todoID: 2, reminder: Reminder{Anchor: ReminderBeforeDue, Offset: time.Hour}},
+			}
+			for _, r := range reminders {
+				if err := store.AddReminder(r.todoID, r.reminder); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := store.AddReminder(99, Reminder{Anchor: ReminderBeforeStart}); err == nil {
+				t.Error("a reminder for a missing todo should be refused")
+			}
+
+			due, err := store.DueReminders(testNow)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(due) != 1 || due[0].Reminder.Anchor != ReminderAtTime || due[0].Todo.Title != "Standup" {
+				t.Fatalf("due at 10am = %+v, want only the 9am reminder", due)
+			}
+
+			if err := store.MarkReminderNotified(due[0].Reminder.ID, testNow); err != nil {
+				t.Fatal(err)
+			}
+			due, err = store.DueReminders(testNow.Add(20 * time.Minute))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(due) != 1 || due[0].Reminder.Anchor != ReminderBeforeStart || !due[0].FireAt.Equal(*at(2026, 10, 19, 10, 15)) {
+				t.Errorf("due at 10:20am = %+v, want the reminder 15m before Standup", due)
+			}
+		})
+	}
+}
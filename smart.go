@@ -0,0 +1,19 @@
+package main
+
+import "time"
+
+// upcomingDays is how far ahead the upcoming view looks
+const upcomingDays = 7
+
+// overdueSince returns when an overdue todo slipped: the earlier of its
+// past due date and the end of its past block
+func overdueSince(todo Todo, now time.Time) *time.Time {
+	var since *time.Time
+	if todo.DueDate != nil && todo.DueDate.Before(now) {
+		since = todo.DueDate
+	}
+	if todo.ScheduledEnd != nil && !todo.ScheduledEnd.After(now) && (since == nil || todo.ScheduledEnd.Before(*since)) {
+		since = todo.ScheduledEnd
+	}
+	return since
+}
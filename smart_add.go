@@ -0,0 +1,236 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// SmartAdd is a todo parsed from one line of smart-add syntax, e.g.
+// "Call dentist #health +personal !high ~30m due:fri @ tomorrow 2pm"
+type SmartAdd struct {
+	Title    string // What is left once the markers are removed
+	Tags     []string
+	Project  string
+	Priority Priority
+	Estimate time.Duration
+	DueDate  *time.Time
+	Block    *TimeBlock
+}
+
+var (
+	smartTagPattern     = regexp.MustCompile(`^#([A-Za-z][\w/-]*)$`)
+	smartProjectPattern = regexp.MustCompile(`^\+([A-Za-z][\w/-]*)$`)
+)
+
+// ParseSmartAdd extracts tags (#tag), a project (+project), a priority
+// (!high), an estimate (~2h), a due date (due:fri) and a time block
+// (@ tomorrow 2pm-3pm) from a todo title. Due dates and time blocks take
+// the following words that clearly belong to them, so the rest of the
+// title can come after. A block with only a start time lasts for the
+// estimate.
+func ParseSmartAdd(input string, clock Clock) (*SmartAdd, error) {
+	words := strings.Fields(input)
+	result := &SmartAdd{}
+	var title []string
+
+	// The estimate is needed before the block, wherever it is written
+	for _, word := range words {
+		if estimate, ok := parseEstimateMarker(word); ok {
+			result.Estimate = estimate
+		}
+	}
+
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		lower := strings.ToLower(word)
+
+		if matches := smartTagPattern.FindStringSubmatch(word); matches != nil {
+			if !containsFold(result.Tags, matches[1]) {
+				result.Tags = append(result.Tags, matches[1])
+			}
+			continue
+		}
+
+		if matches := smartProjectPattern.FindStringSubmatch(word); matches != nil {
+			result.Project = matches[1]
+			continue
+		}
+
+		if strings.HasPrefix(word, "!") && len(word) > 1 {
+			if priority, err := ParsePriority(word[1:]); err == nil {
+				result.Priority = priority
+				continue
+			}
+		}
+
+		if _, ok := parseEstimateMarker(word); ok {
+			continue
+		}
+
+		if strings.HasPrefix(lower, "due:") {
+			phrase := append([]string{word[len("due:"):]}, markerSpan(words[i+1:])...)
+			if phrase[0] == "" {
+				phrase = phrase[1:]
+			}
+
+			used, err := longestParse(phrase, func(s string) error {
+				due, err := ParseDueDate(s, clock)
+				if err == nil {
+					result.DueDate = due
+				}
+				return err
+			})
+			if err != nil {
+				return nil, fmt.Errorf("due date: %w", err)
+			}
+
+			// Skip the words the date used; an attached value is this word
+			i += used
+			if word[len("due:"):] != "" {
+				i--
+			}
+			continue
+		}
+
+		if strings.HasPrefix(word, "@") {
+			phrase := append([]string{word[1:]}, markerSpan(words[i+1:])...)
+			if phrase[0] == "" {
+				phrase = phrase[1:]
+			}
+
+			used, err := longestParse(phrase, func(s string) error {
+				block, err := ParseTimeBlockFor(s, clock, result.Estimate)
+				if err == nil {
+					result.Block = block
+				}
+				return err
+			})
+			if err == nil {
+				i += used
+				if word[1:] != "" {
+					i--
+				}
+				continue
+			}
+			// Not a time block, so keep it as part of the title ("email @sam")
+		}
+
+		title = append(title, word)
+	}
+
+	result.Title = strings.Join(title, " ")
+	return result, nil
+}
+
+// markerSpan returns the words before the next smart-add marker
+func markerSpan(words []string) []string {
+	for i, word := range words {
+		if isSmartMarker(word) {
+			return words[:i]
+		}
+	}
+	return words
+}
+
+// isSmartMarker reports whether a word starts a smart-add field
+func isSmartMarker(word string) bool {
+	if smartTagPattern.MatchString(word) || smartProjectPattern.MatchString(word) {
+		return true
+	}
+	if strings.HasPrefix(word, "!") && len(word) > 1 {
+		if _, err := ParsePriority(word[1:]); err == nil {
+			return true
+		}
+	}
+	if _, ok := parseEstimateMarker(word); ok {
+		return true
+	}
+	return strings.HasPrefix(word, "@") || strings.HasPrefix(strings.ToLower(word), "due:")
+}
+
+// parseEstimateMarker parses an estimate marker like "~2h" or "~1h30m"
+func parseEstimateMarker(word string) (time.Duration, bool) {
+	if !strings.HasPrefix(word, "~") || len(word) == 1 {
+		return 0, false
+	}
+	estimate, err := ParseDuration(word[1:])
+	if err != nil || estimate <= 0 {
+		return 0, false
+	}
+	return estimate, true
+}
+
+// longestParse finds how many words at the start of a phrase make up a
+// date or time. The shortest prefix that parses is always taken; longer
+// ones only while the extra words are clearly part of it, so the 3 in
+// "due:fri 3 copies" stays in the title while "due:fri 3pm" and
+// "due:fri at 3" are read as times. If no prefix parses, the error for the
+// whole phrase is returned since it points at the first word that could
+// not be understood.
+func longestParse(words []string, parse func(string) error) (int, error) {
+	if len(words) == 0 {
+		return 0, fmt.Errorf("a date or time is required")
+	}
+
+	used := 0
+	for n := 1; n <= len(words); n++ {
+		if parse(strings.Join(words[:n], " ")) == nil {
+			used = n
+			break
+		}
+	}
+	if used == 0 {
+		return 0, parse(strings.Join(words, " "))
+	}
+
+	longest := used
+	for n := used + 1; n <= len(words) && isClearDateWord(words, n-1); n++ {
+		if parse(strings.Join(words[:n], " ")) == nil {
+			longest = n
+		}
+	}
+
+	// Parse the chosen words last so the result is theirs
+	return longest, parse(strings.Join(words[:longest], " "))
+}
+
+// isClearDateWord reports whether words[i] can extend a date without
+// being mistaken for the title. A bare day or hour number only counts
+// after "at".
+func isClearDateWord(words []string, i int) bool {
+	if !dayNumberPattern.MatchString(words[i]) {
+		return true
+	}
+	return i > 0 && strings.EqualFold(words[i-1], "at")
+}
+
+// containsFold reports whether values contains value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Todo builds the todo described by the smart-add line
+func (s *SmartAdd) Todo(description string) Todo {
+	todo := Todo{
+		Title:       s.Title,
+		Description: description,
+		Tags:        s.Tags,
+		Project:     s.Project,
+		Priority:    s.Priority,
+		Estimate:    s.Estimate,
+		DueDate:     s.DueDate,
+	}
+	if s.Block != nil {
+		todo.ScheduledStart = s.Block.Start
+		todo.ScheduledEnd = s.Block.End
+		todo.AllDay = s.Block.AllDay
+	}
+	return todo
+}
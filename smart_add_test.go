@@ -0,0 +1,92 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSmartAdd(t *testing.T) {
+	// testNow is Monday 19 October 2026 at 10am
+	tests := []struct {
+		input      string
+		title      string
+		tags       []string
+		project    string
+		priority   Priority
+		due        *time.Time
+		start, end *time.Time
+	}{
+		{
+			input: "Call dentist #health +personal !high due:fri @ tomorrow 2pm-2:30pm",
+			title: "Call dentist", tags: []string{"health"}, project: "personal", priority: PriorityHigh,
+			due: ptr(endOfDayUTC(2026, 10, 23)), start: ptr(day(2026, 10, 20, 14, 0)), end: ptr(day(2026, 10, 20, 14, 30)),
+		},
+		{input: "Plain title", title: "Plain title"},
+		{input: "Buy due:fri 3 copies", title: "Buy 3 copies", due: ptr(endOfDayUTC(2026, 10, 23))},
+		{input: "Buy due:fri 3pm copies", title: "Buy copies", due: ptr(day(2026, 10, 23, 15, 0))},
+		{input: "Buy due:fri at 3 copies", title: "Buy copies", due: ptr(day(2026, 10, 23, 15, 0))},
+		{input: "Pay rent due:next friday now", title: "Pay rent now", due: ptr(endOfDayUTC(2026, 10, 30))},
+		{input: "Pay rent due: in 3 days", title: "Pay rent", due: ptr(endOfDayUTC(2026, 10, 22))},
+		{input: "Trip due:dec 24 2027 to Oslo", title: "Trip to Oslo", due: ptr(endOfDayUTC(2027, 12, 24))},
+		{input: "Order 2 pizzas @fri 6-7pm for 4 people", title: "Order 2 pizzas for 4 people", start: ptr(day(2026, 10, 23, 18, 0)), end: ptr(day(2026, 10, 23, 19, 0))},
+		{input: "Lunch @ noon for 1h with Sam", title: "Lunch with Sam", start: ptr(day(2026, 10, 19, 12, 0)), end: ptr(day(2026, 10, 19, 13, 0))},
+		{input: "email @sam about #work #Work #work", title: "email @sam about", tags: []string{"work"}},
+		{input: "Read !low +books", title: "Read", project: "books", priority: PriorityLow},
+		{input: "Shout !!!", title: "Shout !!!"},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSmartAdd(tt.input, NewFixedClock(testNow))
+		if err != nil {
+			t.Errorf("ParseSmartAdd(%q): %v", tt.input, err)
+			continue
+		}
+		if got.Title != tt.title || !slices.Equal(got.Tags, tt.tags) || got.Project != tt.project || got.Priority != tt.priority {
+			t.Errorf("ParseSmartAdd(%q) = %q %q +%q !%v, want %q %q +%q !%v",
+				tt.input, got.Title, got.Tags, got.Project, got.Priority, tt.title, tt.tags, tt.project, tt.priority)
+		}
+		if !sameTime(got.DueDate, tt.due) {
+			t.Errorf("ParseSmartAdd(%q) due %v, want %v", tt.input, got.DueDate, tt.due)
+		}
+		var start, end *time.Time
+		if got.Block != nil {
+			start, end = got.Block.Start, got.Block.End
+		}
+		if !sameTime(start, tt.start) || !sameTime(end, tt.end) {
+			t.Errorf("ParseSmartAdd(%q) block %v - %v, want %v - %v", tt.input, start, end, tt.start, tt.end)
+		}
+	}
+}
+
+func TestParseSmartAddErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "Pay rent due:", want: "a date or time is required"},
+		{input: "Pay rent due:someday", want: "unable to parse \"someday\""},
+		{input: "Pay rent due:feb 30", want: "no such date"},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseSmartAdd(tt.input, NewFixedClock(testNow))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ParseSmartAdd(%q) = %v, want error %q", tt.input, err, tt.want)
+		}
+	}
+}
+
+// ptr returns a pointer to v
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// sameTime reports whether two optional times are the same instant
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
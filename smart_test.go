@@ -0,0 +1,110 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+// addSmartViewTodos fills store with todos for each smart view around
+// testNow, Monday 19 October 2026 at 10am
+func addSmartViewTodos(t *testing.T, store Store) {
+	t.Helper()
+	for _, todo := range []Todo{
+		{Title: "Slipped block", ScheduledStart: at(2026, 10, 16, 9, 0), ScheduledEnd: at(2026, 10, 16, 10, 0)},
+		{Title: "Late", DueDate: at(2026, 10, 17, 17, 0)},
+		{Title: "Start only", ScheduledStart: at(2026, 10, 20, 9, 0)},
+		{Title: "Block tomorrow", ScheduledStart: at(2026, 10, 20, 14, 0), ScheduledEnd: at(2026, 10, 20, 15, 0)},
+		{Title: "Due friday", DueDate: at(2026, 10, 23, 17, 0)},
+		{Title: "Due later", DueDate: at(2026, 10, 30, 17, 0)},
+		{Title: "Someday"},
+		{Title: "Finished"},
+	} {
+		if err := store.AddTodo(todo); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.ToggleTodo(8); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSmartViews(t *testing.T) {
+	for name, store := range map[string]Store{"memory": newTestStore(), "database": newTestDB(t)} {
+		t.Run(name, func(t *testing.T) {
+			addSmartViewTodos(t, store)
+
+			overdue, err := store.GetOverdueTodos(testNow)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := titles(overdue); !slices.Equal(got, []string{"Slipped block", "Late"}) {
+				t.Errorf("overdue = %q", got)
+			}
+
+			// A block with only a start counts as upcoming at its start
+			upcoming, err := store.GetUpcomingTodos(testNow, *at(2026, 10, 26, 0, 0))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := titles(upcoming); !slices.Equal(got, []string{"Start only", "Block tomorrow", "Due friday"}) {
+				t.Errorf("upcoming = %q", got)
+			}
+
+			someday, err := store.GetSomedayTodos()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := titles(someday); !slices.Equal(got, []string{"Someday"}) {
+				t.Errorf("someday = %q", got)
+			}
+		})
+	}
+}
+
+func TestOverdueSince(t *testing.T) {
+	todo := Todo{DueDate: at(2026, 10, 17, 17, 0), ScheduledStart: at(2026, 10, 16, 9, 0), ScheduledEnd: at(2026, 10, 16, 10, 0)}
+	if since := overdueSince(todo, testNow); !since.Equal(*at(2026, 10, 16, 10, 0)) {
+		t.Errorf("overdueSince = %v, want the earlier block end", since)
+	}
+
+	todo.ScheduledEnd = at(2026, 10, 20, 10, 0)
+	if since := overdueSince(todo, testNow); !since.Equal(*at(2026, 10, 17, 17, 0)) {
+		t.Errorf("overdueSince = %v, want the due date once the block is still ahead", since)
+	}
+}
+
+func TestCLISmartViews(t *testing.T) {
+	c, store, out := newTestCLI()
+	addSmartViewTodos(t, store)
+
+	assertContains(t, run(c, out, "overdue"), "Overdue", "Slipped block", "Late", "li postpone --overdue tomorrow")
+	assertContains(t, run(c, out, "upcoming"), "Upcoming (next 7 days)", "Start only", "Due friday")
+	assertContains(t, run(c, out, "upcoming", "14"), "Upcoming (next 14 days)", "Due later")
+	assertContains(t, run(c, out, "upcoming", "0"), "Invalid number of days")
+	assertContains(t, run(c, out, "someday"), "Someday")
+}
+
+func TestTuiSmartViews(t *testing.T) {
+	store := newTestStore()
+	addSmartViewTodos(t, store)
+	m := newTestTui(store)
+
+	tests := []struct {
+		key   string
+		state tuiState
+		todos []string
+	}{
+		{key: "o", state: tuiOverdueView, todos: []string{"Slipped block", "Late"}},
+		{key: "p", state: tuiUpcomingView, todos: []string{"Start only", "Block tomorrow", "Due friday"}},
+		{key: "z", state: tuiSomedayView, todos: []string{"Someday"}},
+	}
+	for _, tt := range tests {
+		m = press(m, tt.key)
+		if m.state != tt.state {
+			t.Errorf("%q: got state %v, want %v", tt.key, m.state, tt.state)
+		}
+		if !slices.Equal(titles(m.todos), tt.todos) {
+			t.Errorf("%q: listed %q, want %q", tt.key, titles(m.todos), tt.todos)
+		}
+	}
+}
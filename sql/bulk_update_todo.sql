@@ -0,0 +1,3 @@
+UPDATE todos 
+SET done = ?, tags = ?, project = ?, scheduled_start = ?, scheduled_end = ?, all_day = ?, timezone = ?, updated_at = CURRENT_TIMESTAMP 
+WHERE id = ?
@@ -0,0 +1 @@
+DELETE FROM pending_ops WHERE id <= ?
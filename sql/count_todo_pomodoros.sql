@@ -0,0 +1,3 @@
+SELECT COUNT(*) 
+FROM pomodoros 
+WHERE todo_id = ?
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS sync_ops (
+    seq INTEGER PRIMARY KEY AUTOINCREMENT,
+    op_id TEXT NOT NULL UNIQUE,
+    todo_uuid TEXT NOT NULL,
+    field TEXT NOT NULL,
+    value TEXT NOT NULL,
+    version INTEGER NOT NULL,
+    device TEXT NOT NULL
+)
@@ -0,0 +1 @@
+DELETE FROM saved_filters WHERE name = ?
@@ -0,0 +1 @@
+DELETE FROM pomodoros WHERE todo_id = ?
@@ -0,0 +1 @@
+DELETE FROM reminders WHERE todo_id = ?
@@ -0,0 +1 @@
+DELETE FROM time_entries WHERE todo_id = ?
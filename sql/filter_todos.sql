@@ -0,0 +1,4 @@
+SELECT id, uuid, title, description, done, due_date, scheduled_start, scheduled_end, all_day, tags, project, priority, estimate_minutes, timezone, created_at, updated_at 
+FROM todos 
+WHERE %s 
+ORDER BY created_at DESC, id DESC
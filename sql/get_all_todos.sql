@@ -1,3 +1,3 @@
-SELECT id, title, description, done, due_date, scheduled_start, scheduled_end, created_at, updated_at 
+SELECT id, uuid, title, description, done, due_date, scheduled_start, scheduled_end, all_day, tags, project, priority, estimate_minutes, timezone, created_at, updated_at 
 FROM todos 
-ORDER BY created_at DESC
+ORDER BY created_at DESC, id DESC
@@ -0,0 +1,6 @@
+SELECT id, uuid, title, description, done, due_date, scheduled_start, scheduled_end, all_day, tags, project, priority, estimate_minutes, timezone, created_at, updated_at 
+FROM todos 
+WHERE due_date IS NOT NULL 
+  AND datetime(due_date) >= datetime(?) 
+  AND datetime(due_date) < datetime(?)
+ORDER BY due_date ASC
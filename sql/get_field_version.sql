@@ -0,0 +1 @@
+SELECT version, device FROM field_versions WHERE todo_uuid = ? AND field = ?
@@ -0,0 +1,6 @@
+SELECT id, uuid, title, description, done, due_date, scheduled_start, scheduled_end, all_day, tags, project, priority, estimate_minutes, timezone, created_at, updated_at 
+FROM todos 
+WHERE done = FALSE 
+  AND ((due_date IS NOT NULL AND datetime(due_date) < datetime(?)) 
+    OR (scheduled_end IS NOT NULL AND datetime(scheduled_end) <= datetime(?)))
+ORDER BY datetime(COALESCE(scheduled_start, due_date)) ASC
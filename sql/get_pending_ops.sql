@@ -0,0 +1,3 @@
+SELECT id, op_id, todo_uuid, field, value, version, device 
+FROM pending_ops 
+ORDER BY id ASC
@@ -0,0 +1,5 @@
+SELECT r.id, r.todo_id, r.anchor, r.offset_minutes, r.remind_at, r.notified_at, r.created_at,
+       t.id, t.uuid, t.title, t.description, t.done, t.due_date, t.scheduled_start, t.scheduled_end, t.all_day, t.tags, t.project, t.priority, t.estimate_minutes, t.timezone, t.created_at, t.updated_at 
+FROM reminders r 
+JOIN todos t ON t.id = r.todo_id 
+WHERE r.notified_at IS NULL AND t.done = FALSE
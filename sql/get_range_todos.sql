@@ -1,6 +1,6 @@
-SELECT id, title, description, done, due_date, scheduled_start, scheduled_end, created_at, updated_at 
+SELECT id, uuid, title, description, done, due_date, scheduled_start, scheduled_end, all_day, tags, project, priority, estimate_minutes, timezone, created_at, updated_at 
 FROM todos 
 WHERE scheduled_start IS NOT NULL 
-  AND DATE(scheduled_start) >= DATE(?) 
-  AND DATE(scheduled_start) <= DATE(?)
+  AND datetime(scheduled_start) < datetime(?) 
+  AND (datetime(scheduled_start) >= datetime(?) OR datetime(scheduled_end) > datetime(?))
 ORDER BY scheduled_start ASC
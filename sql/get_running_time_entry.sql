@@ -0,0 +1,5 @@
+SELECT id, todo_id, started_at, stopped_at 
+FROM time_entries 
+WHERE stopped_at IS NULL 
+ORDER BY started_at DESC 
+LIMIT 1
@@ -0,0 +1,3 @@
+SELECT name, query 
+FROM saved_filters 
+ORDER BY name ASC
@@ -0,0 +1,6 @@
+SELECT id, uuid, title, description, done, due_date, scheduled_start, scheduled_end, all_day, tags, project, priority, estimate_minutes, timezone, created_at, updated_at 
+FROM todos 
+WHERE done = FALSE 
+  AND scheduled_start IS NULL 
+  AND due_date IS NULL 
+ORDER BY created_at DESC, id DESC
@@ -0,0 +1,5 @@
+SELECT c.id, c.todo_uuid, COALESCE(t.id, 0), COALESCE(t.title, ''), c.field, c.local_value, c.remote_value, c.winner, c.created_at 
+FROM sync_conflicts c 
+LEFT JOIN todos t ON t.uuid = c.todo_uuid 
+WHERE c.resolved = FALSE 
+ORDER BY c.created_at ASC, c.id ASC
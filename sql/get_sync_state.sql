@@ -0,0 +1 @@
+SELECT value FROM sync_state WHERE key = ?
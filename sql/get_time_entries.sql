@@ -0,0 +1,4 @@
+SELECT id, todo_id, started_at, stopped_at 
+FROM time_entries 
+WHERE datetime(started_at) < datetime(?) AND (stopped_at IS NULL OR datetime(stopped_at) > datetime(?)) 
+ORDER BY started_at ASC
@@ -0,0 +1,3 @@
+SELECT id, COALESCE(uuid, ''), title, description, done, due_date, scheduled_start, scheduled_end, all_day, tags, project, priority, estimate_minutes, timezone, created_at, updated_at 
+FROM todos 
+WHERE id = ?
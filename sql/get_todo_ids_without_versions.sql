@@ -0,0 +1 @@
+SELECT id FROM todos WHERE uuid NOT IN (SELECT todo_uuid FROM field_versions)
@@ -0,0 +1,4 @@
+SELECT id, todo_id, anchor, offset_minutes, remind_at, notified_at, created_at 
+FROM reminders 
+WHERE todo_id = ? 
+ORDER BY created_at ASC
@@ -0,0 +1,7 @@
+SELECT id, uuid, title, description, done, due_date, scheduled_start, scheduled_end, all_day, tags, project, priority, estimate_minutes, timezone, created_at, updated_at 
+FROM todos 
+WHERE done = FALSE 
+  AND ((scheduled_start IS NOT NULL AND datetime(scheduled_start) < datetime(?) 
+      AND (datetime(scheduled_start) >= datetime(?) OR datetime(scheduled_end) > datetime(?))) 
+    OR (due_date IS NOT NULL AND datetime(due_date) >= datetime(?) AND datetime(due_date) < datetime(?)))
+ORDER BY datetime(COALESCE(scheduled_start, due_date)) ASC
@@ -0,0 +1,2 @@
+INSERT INTO pending_ops (op_id, todo_uuid, field, value, version, device) 
+VALUES (?, ?, ?, ?, ?, ?)
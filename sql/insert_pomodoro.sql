@@ -0,0 +1,2 @@
+INSERT INTO pomodoros (todo_id, started_at, completed_at) 
+VALUES (?, ?, ?)
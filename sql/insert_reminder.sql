@@ -0,0 +1,2 @@
+INSERT INTO reminders (todo_id, anchor, offset_minutes, remind_at) 
+VALUES (?, ?, ?, ?)
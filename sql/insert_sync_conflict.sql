@@ -0,0 +1,2 @@
+INSERT INTO sync_conflicts (todo_uuid, field, local_value, remote_value, winner) 
+VALUES (?, ?, ?, ?, ?)
@@ -0,0 +1 @@
+INSERT INTO todos (uuid, title) VALUES (?, '')
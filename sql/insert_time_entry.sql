@@ -0,0 +1,2 @@
+INSERT INTO time_entries (todo_id, started_at) 
+VALUES (?, ?)
@@ -1,2 +1,2 @@
-INSERT INTO todos (title, description, due_date, scheduled_start, scheduled_end) 
-VALUES (?, ?, ?, ?, ?)
+INSERT INTO todos (uuid, title, description, due_date, scheduled_start, scheduled_end, all_day, tags, project, priority, estimate_minutes, timezone) 
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
@@ -0,0 +1,3 @@
+UPDATE reminders 
+SET notified_at = ? 
+WHERE id = ?
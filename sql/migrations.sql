@@ -6,3 +6,20 @@ ALTER TABLE todos ADD COLUMN scheduled_start DATETIME;
 
 -- Migration to add scheduled_end column
 ALTER TABLE todos ADD COLUMN scheduled_end DATETIME;
+
+-- Migration to add uuid column (backfilled on startup)
+ALTER TABLE todos ADD COLUMN uuid TEXT;
+
+-- Migrations to add smart-add labels
+ALTER TABLE todos ADD COLUMN tags TEXT DEFAULT '';
+ALTER TABLE todos ADD COLUMN project TEXT DEFAULT '';
+ALTER TABLE todos ADD COLUMN priority INTEGER DEFAULT 0;
+
+-- Migration to record the zone a todo was scheduled from
+ALTER TABLE todos ADD COLUMN timezone TEXT DEFAULT '';
+
+-- Migration to mark time blocks that span whole days
+ALTER TABLE todos ADD COLUMN all_day BOOLEAN DEFAULT FALSE;
+
+-- Migration to add time estimates
+ALTER TABLE todos ADD COLUMN estimate_minutes INTEGER DEFAULT 0;
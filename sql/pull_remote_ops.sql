@@ -0,0 +1,4 @@
+SELECT seq, op_id, todo_uuid, field, value, version, device 
+FROM sync_ops 
+WHERE seq > ? 
+ORDER BY seq ASC
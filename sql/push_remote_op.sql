@@ -0,0 +1,2 @@
+INSERT OR IGNORE INTO sync_ops (op_id, todo_uuid, field, value, version, device) 
+VALUES (?, ?, ?, ?, ?, ?)
@@ -0,0 +1 @@
+UPDATE sync_conflicts SET resolved = TRUE WHERE id = ?
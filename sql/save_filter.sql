@@ -0,0 +1,3 @@
+INSERT INTO saved_filters (name, query) 
+VALUES (?, ?) 
+ON CONFLICT(name) DO UPDATE SET query = excluded.query
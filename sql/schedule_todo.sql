@@ -1,3 +1,3 @@
 UPDATE todos 
-SET scheduled_start = ?, scheduled_end = ?, updated_at = CURRENT_TIMESTAMP 
+SET scheduled_start = ?, scheduled_end = ?, all_day = ?, timezone = ?, updated_at = CURRENT_TIMESTAMP 
 WHERE id = ?
@@ -0,0 +1,2 @@
+INSERT INTO field_versions (todo_uuid, field, version, device) VALUES (?, ?, ?, ?) 
+ON CONFLICT(todo_uuid, field) DO UPDATE SET version = excluded.version, device = excluded.device
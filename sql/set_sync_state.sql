@@ -0,0 +1,2 @@
+INSERT INTO sync_state (key, value) VALUES (?, ?) 
+ON CONFLICT(key) DO UPDATE SET value = excluded.value
@@ -0,0 +1 @@
+UPDATE todos SET uuid = ? WHERE id = ?
@@ -0,0 +1,3 @@
+UPDATE time_entries 
+SET stopped_at = ? 
+WHERE stopped_at IS NULL
@@ -1,3 +1,3 @@
 UPDATE todos 
-SET title = ?, description = ?, due_date = ?, scheduled_start = ?, scheduled_end = ?, updated_at = CURRENT_TIMESTAMP 
+SET title = ?, description = ?, due_date = ?, scheduled_start = ?, scheduled_end = ?, all_day = ?, estimate_minutes = ?, timezone = ?, updated_at = CURRENT_TIMESTAMP 
 WHERE id = ?
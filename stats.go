@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// EstimateRow compares a todo's estimate with the time it was given and
+// the time actually tracked on it
+type EstimateRow struct {
+	Todo      Todo
+	Estimate  time.Duration
+	Scheduled time.Duration
+	Tracked   time.Duration
+}
+
+// EstimateReport compares estimates with scheduled and tracked time for
+// every todo that has an estimate
+type EstimateReport struct {
+	Rows      []EstimateRow
+	Estimated time.Duration // Total of the estimates
+	Scheduled time.Duration // Total scheduled for the same todos
+	Tracked   time.Duration // Total tracked on the same todos
+}
+
+// scheduledLength returns how long a todo's block lasts. All-day blocks
+// count as full working days, as in the year view.
+func scheduledLength(todo Todo, options CalendarOptions) time.Duration {
+	if todo.ScheduledStart == nil || todo.ScheduledEnd == nil {
+		return 0
+	}
+	if todo.AllDay {
+		days := int(todo.ScheduledEnd.Sub(*todo.ScheduledStart).Round(24*time.Hour) / (24 * time.Hour))
+		return time.Duration(days*(options.WorkEnd-options.WorkStart)) * time.Hour
+	}
+	return max(todo.ScheduledEnd.Sub(*todo.ScheduledStart), 0)
+}
+
+// BuildEstimateReport collects the todos with estimates, with running
+// timers counted up to now
+func BuildEstimateReport(db Store, options CalendarOptions, now time.Time) (*EstimateReport, error) {
+	todos, err := db.GetAllTodos()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := db.GetTimeEntries(time.Time{}, now)
+	if err != nil {
+		return nil, err
+	}
+	tracked := make(map[int]time.Duration)
+	for _, entry := range entries {
+		tracked[entry.TodoID] += entry.Duration(now)
+	}
+
+	report := &EstimateReport{}
+	for _, todo := range todos {
+		if todo.Estimate == 0 {
+			continue
+		}
+		row := EstimateRow{
+			Todo:      todo,
+			Estimate:  todo.Estimate,
+			Scheduled: scheduledLength(todo, options),
+			Tracked:   tracked[todo.ID],
+		}
+		report.Rows = append(report.Rows, row)
+		report.Estimated += row.Estimate
+		report.Scheduled += row.Scheduled
+		report.Tracked += row.Tracked
+	}
+
+	return report, nil
+}
+
+// formatDifference formats actual minus estimated time, e.g. "+30m"
+func formatDifference(estimate, actual time.Duration) string {
+	switch diff := actual - estimate; {
+	case diff > 0:
+		return "+" + formatDuration(diff)
+	case diff < 0:
+		return "-" + formatDuration(-diff)
+	}
+	return "0m"
+}
+
+// Render formats the report as a table
+func (r *EstimateReport) Render() string {
+	var s strings.Builder
+
+	s.WriteString(titleStyle.Render("📊 Estimates vs. scheduled and tracked time"))
+	s.WriteString("\n\n")
+
+	if len(r.Rows) == 0 {
+		s.WriteString(descStyle.Render("No todos have estimates yet. Add one with ~2h when adding a todo."))
+		s.WriteString("\n")
+		return s.String()
+	}
+
+	header := lipgloss.NewStyle().Foreground(lipgloss.Color(ColorBlue)).Bold(true)
+	s.WriteString(header.Render(fmt.Sprintf("  %-6s %-30s %9s %10s %8s %8s", "ID", "Todo", "Estimate", "Scheduled", "Tracked", "Diff")))
+	s.WriteString("\n")
+
+	// Only todos with tracked time count towards the total difference
+	var trackedEstimate time.Duration
+	for _, row := range r.Rows {
+		scheduled, tracked, diff := "-", "-", ""
+		if row.Scheduled > 0 {
+			scheduled = formatDuration(row.Scheduled)
+		}
+		if row.Tracked > 0 {
+			tracked = formatDuration(row.Tracked)
+			diff = formatDifference(row.Estimate, row.Tracked)
+			trackedEstimate += row.Estimate
+		}
+		line := fmt.Sprintf("  %-6s %-30s %9s %10s %8s %8s",
+			fmt.Sprintf("[%d]", row.Todo.ID),
+			fitCell(row.Todo.Title, 30),
+			formatDuration(row.Estimate),
+			scheduled,
+			tracked,
+			diff)
+		if row.Todo.Done {
+			line = completedStyle.Render(line)
+		}
+		s.WriteString(line)
+		s.WriteString("\n")
+	}
+
+	totalDiff := ""
+	if r.Tracked > 0 {
+		totalDiff = formatDifference(trackedEstimate, r.Tracked)
+	}
+
+	s.WriteString("\n")
+	s.WriteString(commandStyle.Render(fmt.Sprintf("  %-6s %-30s %9s %10s %8s %8s", "", "Total",
+		formatDuration(r.Estimated),
+		formatDuration(r.Scheduled),
+		formatDuration(r.Tracked),
+		totalDiff)))
+	s.WriteString("\n")
+	s.WriteString(descStyle.Render("  Diff is tracked time minus the estimate, for todos with tracked time."))
+	s.WriteString("\n")
+
+	return s.String()
+}
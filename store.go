@@ -0,0 +1,55 @@
+package main
+
+import "time"
+
+// Store is the todo storage used by the CLI, TUI and calendar. DB is the
+// SQLite-backed implementation; MemoryStore keeps everything in memory.
+type Store interface {
+	AddTodo(todo Todo) error
+	GetTodo(id int) (*Todo, error)
+	GetAllTodos() ([]Todo, error)
+	GetInboxTodos() ([]Todo, error)
+	GetDateTodos(date time.Time) ([]Todo, error)
+	GetRangeTodos(startDate, endDate time.Time) ([]Todo, error)
+	GetMonthTodos(date time.Time) ([]Todo, error)
+	GetDueTodos(startDate, endDate time.Time) ([]Todo, error)
+	GetOverdueTodos(now time.Time) ([]Todo, error)
+	GetUpcomingTodos(from, to time.Time) ([]Todo, error)
+	GetSomedayTodos() ([]Todo, error)
+	UpdateTodo(id int, title, description string, dueDate, scheduledStart, scheduledEnd *time.Time, allDay bool, estimate time.Duration) error
+	DeleteTodo(id int) error
+	ToggleTodo(id int) error
+	ScheduleTodo(id int, scheduledStart, scheduledEnd *time.Time, allDay bool) error
+	ApplyBulkEdit(ids []int, edit BulkEdit) error
+
+	AddReminder(todoID int, reminder Reminder) error
+	GetTodoReminders(todoID int) ([]Reminder, error)
+	DueReminders(now time.Time) ([]DueReminder, error)
+	MarkReminderNotified(id int, notifiedAt time.Time) error
+
+	StartTimer(todoID int, at time.Time) error
+	StopTimer(at time.Time) (*TimeEntry, error)
+	GetRunningTimer() (*TimeEntry, error)
+	GetTimeEntries(from, to time.Time) ([]TimeEntry, error)
+
+	LogPomodoro(todoID int, startedAt, completedAt time.Time) error
+	CountPomodoros(todoID int) (int, error)
+
+	FilterTodos(filter *Filter) ([]Todo, error)
+	SaveFilter(name, query string) error
+	GetSavedFilters() ([]SavedFilter, error)
+	DeleteFilter(name string) error
+
+	SyncEnabled() bool
+	Sync() (SyncResult, error)
+	GetConflicts() ([]SyncConflict, error)
+	ResolveConflict(id int, keep string) error
+
+	Close() error
+}
+
+// Both implementations must satisfy Store
+var (
+	_ Store = (*DB)(nil)
+	_ Store = (*MemoryStore)(nil)
+)
@@ -60,6 +60,14 @@ var (
 	idStyle = lipgloss.NewStyle().
 		Foreground(lipgloss.Color(ColorBlue)).
 		Bold(true)
+
+	// Blocks that overlap another block
+	overlapStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color(ColorRed))
+
+	// Due dates and blocks that have slipped
+	overdueStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color(ColorRed))
 )
 
 // TUI Styles
@@ -77,6 +85,10 @@ var (
 	tuiDoneStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color(ColorGray))
 
+	tuiMarkedStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color(ColorOrange)).
+			Bold(true)
+
 	tuiHelpStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color(ColorGray)).
 			Padding(1, 0)
@@ -118,3 +130,17 @@ func createStatusStyle(done bool) (string, lipgloss.Color) {
 	}
 	return "[ ]", lipgloss.Color(ColorOrange)
 }
+
+// Helper function to render a priority in its color
+func stylePriority(priority Priority) string {
+	color := ColorGray
+	switch priority {
+	case PriorityHigh:
+		color = ColorRed
+	case PriorityMedium:
+		color = ColorOrange
+	case PriorityLow:
+		color = ColorBlue
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Render(priority.String())
+}
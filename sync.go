@@ -0,0 +1,783 @@
+package main
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Synced todo fields. Each is versioned independently so that edits to
+// different fields on different devices merge without conflict.
+const (
+	fieldTitle       = "title"
+	fieldDescription = "description"
+	fieldDone        = "done"
+	fieldDueDate     = "due_date"
+	fieldSchedule    = "schedule" // Start, end and all-day together, so a block never mixes two edits
+	fieldTags        = "tags"
+	fieldProject     = "project"
+	fieldPriority    = "priority"
+	fieldEstimate    = "estimate_minutes"
+	fieldTimeZone    = "timezone"
+	fieldDeleted     = "deleted" // Tombstone; a deleted todo is never recreated
+)
+
+// syncedColumns maps synced fields to their todos column. The schedule
+// spans three columns and is applied separately; remote ops for any other
+// field not listed here are ignored.
+var syncedColumns = map[string]string{
+	fieldTitle:       "title",
+	fieldDescription: "description",
+	fieldDone:        "done",
+	fieldDueDate:     "due_date",
+	fieldTags:        "tags",
+	fieldProject:     "project",
+	fieldPriority:    "priority",
+	fieldEstimate:    "estimate_minutes",
+	fieldTimeZone:    "timezone",
+}
+
+// Conflict winners
+const (
+	conflictLocal  = "local"
+	conflictRemote = "remote"
+)
+
+// Keys in the sync_state table
+const (
+	syncStateDevice = "device_id"
+	syncStateClock  = "clock"
+	syncStateCursor = "cursor"
+	syncStateUTC    = "utc_timestamps" // Set once older zoned times are rewritten in UTC
+)
+
+// SyncOp is a single field change exchanged between devices. Values are
+// JSON encoded so that every field round-trips through a TEXT column.
+type SyncOp struct {
+	Seq      int64 // Local pending_ops ID or remote sequence number
+	OpID     string
+	TodoUUID string
+	Field    string
+	Value    string
+	Version  int64 // Hybrid logical clock timestamp
+	Device   string
+}
+
+// newerThan reports whether the op wins over a field last written at
+// version by device. Ties on version are broken by device ID so every
+// device picks the same winner.
+func (op SyncOp) newerThan(version int64, device string) bool {
+	if op.Version != version {
+		return op.Version > version
+	}
+	return op.Device > device
+}
+
+// SyncBackend exchanges field operations with other devices
+type SyncBackend interface {
+	// Push stores ops remotely. Pushing an op twice must be harmless.
+	Push(ops []SyncOp) error
+	// Pull returns ops stored after cursor and the cursor to resume from.
+	Pull(cursor string) ([]SyncOp, string, error)
+}
+
+// SyncConflict records a field edited on two devices before they synced
+type SyncConflict struct {
+	ID          int
+	TodoUUID    string
+	TodoID      int    // 0 if the todo has since been deleted
+	TodoTitle   string // Empty if the todo has since been deleted
+	Field       string
+	LocalValue  string
+	RemoteValue string
+	Winner      string // Which side last-writer-wins kept: "local" or "remote"
+	CreatedAt   time.Time
+}
+
+// newUUID returns a random RFC 4122 version 4 UUID
+func newUUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("failed to generate uuid: %v", err))
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+func encodeString(s string) string {
+	encoded, _ := json.Marshal(s)
+	return string(encoded)
+}
+
+func encodeBool(b bool) string {
+	return strconv.FormatBool(b)
+}
+
+func encodeInt(i int) string {
+	return strconv.Itoa(i)
+}
+
+func encodeTime(t *time.Time) string {
+	if t == nil {
+		return "null"
+	}
+	return encodeString(t.UTC().Format(time.RFC3339Nano))
+}
+
+// syncedBlock is the encoded value of the schedule field
+type syncedBlock struct {
+	Start  *time.Time `json:"start"`
+	End    *time.Time `json:"end"`
+	AllDay bool       `json:"all_day"`
+}
+
+func encodeBlock(start, end *time.Time, allDay bool) string {
+	block := syncedBlock{Start: utcPtr(start), End: utcPtr(end), AllDay: allDay}
+	encoded, _ := json.Marshal(block)
+	return string(encoded)
+}
+
+// utcPtr returns t in UTC, or nil if t is nil
+func utcPtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
+// todoFieldValues encodes every synced field of a todo
+func todoFieldValues(todo Todo) map[string]string {
+	return map[string]string{
+		fieldTitle:       encodeString(todo.Title),
+		fieldDescription: encodeString(todo.Description),
+		fieldDone:        encodeBool(todo.Done),
+		fieldDueDate:     encodeTime(todo.DueDate),
+		fieldSchedule:    encodeBlock(todo.ScheduledStart, todo.ScheduledEnd, todo.AllDay),
+		fieldTags:        encodeString(joinTags(todo.Tags)),
+		fieldProject:     encodeString(todo.Project),
+		fieldPriority:    encodeInt(int(todo.Priority)),
+		fieldEstimate:    encodeInt(int(todo.Estimate / time.Minute)),
+		fieldTimeZone:    encodeString(todo.TimeZone),
+	}
+}
+
+// changedFieldValues encodes the synced fields that differ between two versions of a todo
+func changedFieldValues(before, after Todo) map[string]string {
+	oldValues := todoFieldValues(before)
+	changes := make(map[string]string)
+	for field, value := range todoFieldValues(after) {
+		if oldValues[field] != value {
+			changes[field] = value
+		}
+	}
+	return changes
+}
+
+// decodeFieldValue converts an encoded value into the type stored in its column
+func decodeFieldValue(field, value string) (interface{}, error) {
+	switch field {
+	case fieldDone, fieldDeleted:
+		return strconv.ParseBool(value)
+	case fieldPriority, fieldEstimate:
+		return strconv.Atoi(value)
+	case fieldSchedule:
+		var block syncedBlock
+		if err := json.Unmarshal([]byte(value), &block); err != nil {
+			return nil, err
+		}
+		return TimeBlock{Start: utcPtr(block.Start), End: utcPtr(block.End), AllDay: block.AllDay}, nil
+	case fieldDueDate:
+		var s *string
+		if err := json.Unmarshal([]byte(value), &s); err != nil {
+			return nil, err
+		}
+		if s == nil {
+			return nil, nil
+		}
+		t, err := time.Parse(time.RFC3339Nano, *s)
+		return t.UTC(), err
+	default:
+		var s string
+		err := json.Unmarshal([]byte(value), &s)
+		return s, err
+	}
+}
+
+// FormatFieldValue formats an encoded field value for display, showing
+// times in loc
+func FormatFieldValue(field, value string, loc *time.Location) string {
+	decoded, err := decodeFieldValue(field, value)
+	if err != nil {
+		return value
+	}
+
+	switch v := decoded.(type) {
+	case nil:
+		return "(none)"
+	case bool:
+		if field == fieldDone {
+			if v {
+				return "done"
+			}
+			return "not done"
+		}
+		return strconv.FormatBool(v)
+	case int:
+		if field == fieldPriority {
+			return Priority(v).String()
+		}
+		return strconv.Itoa(v)
+	case time.Time:
+		v = v.In(loc)
+		return activeLocale.DateYear(v) + " " + activeLocale.Clock(v)
+	case TimeBlock:
+		if v.Start == nil {
+			return "(none)"
+		}
+		start := v.Start.In(loc)
+		if v.AllDay {
+			return activeLocale.DateYear(start) + " (" + activeLocale.AllDay + ")"
+		}
+		formatted := activeLocale.DateYear(start) + " " + activeLocale.Clock(start)
+		if v.End != nil {
+			formatted += "-" + activeLocale.Clock(v.End.In(loc))
+		}
+		return formatted
+	case string:
+		if v == "" {
+			return "(empty)"
+		}
+		return fmt.Sprintf("%q", v)
+	}
+
+	return value
+}
+
+// getSyncState reads a sync_state value, returning "" if it is unset
+func getSyncState(q queryer, key string) (string, error) {
+	query, err := loadSQL("get_sync_state.sql")
+	if err != nil {
+		return "", err
+	}
+
+	var value string
+	err = q.QueryRow(query, key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return value, err
+}
+
+func setSyncState(q queryer, key, value string) error {
+	query, err := loadSQL("set_sync_state.sql")
+	if err != nil {
+		return err
+	}
+
+	_, err = q.Exec(query, key, value)
+	return err
+}
+
+// queryer is satisfied by both *sql.DB and *sql.Tx
+type queryer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// loadDeviceID returns this database's device ID, creating one on first use
+func (db *DB) loadDeviceID() (string, error) {
+	deviceID, err := getSyncState(db.conn, syncStateDevice)
+	if err != nil || deviceID != "" {
+		return deviceID, err
+	}
+
+	deviceID = newUUID()
+	return deviceID, setSyncState(db.conn, syncStateDevice, deviceID)
+}
+
+// observeVersion advances the hybrid logical clock past version and
+// returns the clock's new value. Passing 0 ticks the clock for a local edit.
+func (db *DB) observeVersion(tx *sql.Tx, version int64) (int64, error) {
+	clockStr, err := getSyncState(tx, syncStateClock)
+	if err != nil {
+		return 0, err
+	}
+
+	var clock int64
+	if clockStr != "" {
+		clock, err = strconv.ParseInt(clockStr, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid sync clock %q: %w", clockStr, err)
+		}
+	}
+
+	next := time.Now().UnixNano()
+	if next <= clock {
+		next = clock + 1
+	}
+	if version >= next {
+		next = version
+	}
+
+	return next, setSyncState(tx, syncStateClock, strconv.FormatInt(next, 10))
+}
+
+// recordChanges stamps each changed field with a new version and appends
+// it to the pending-ops log for the next sync. Without a backend there is
+// nothing to sync with, so nothing is recorded.
+func (db *DB) recordChanges(tx *sql.Tx, todoUUID string, changes map[string]string) error {
+	if len(changes) == 0 || db.backend == nil {
+		return nil
+	}
+
+	setVersionSQL, err := loadSQL("set_field_version.sql")
+	if err != nil {
+		return err
+	}
+	insertOpSQL, err := loadSQL("insert_pending_op.sql")
+	if err != nil {
+		return err
+	}
+
+	// Record fields in a stable order so op logs are reproducible
+	fields := make([]string, 0, len(changes))
+	for field := range changes {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		version, err := db.observeVersion(tx, 0)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(setVersionSQL, todoUUID, field, version, db.deviceID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(insertOpSQL, newUUID(), todoUUID, field, changes[field], version, db.deviceID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// backfillUUIDs gives todos created before sync support a UUID
+func (db *DB) backfillUUIDs() error {
+	setUUIDSQL, err := loadSQL("set_todo_uuid.sql")
+	if err != nil {
+		return err
+	}
+
+	ids, err := db.queryTodoIDs("get_todo_ids_without_uuid.sql")
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if _, err := db.conn.Exec(setUUIDSQL, newUUID(), id); err != nil {
+			return fmt.Errorf("failed to assign uuid to todo %d: %w", id, err)
+		}
+	}
+
+	return nil
+}
+
+// shareUnsyncedTodos logs the current values of todos that have never been
+// recorded, such as those made before sync was set up, so that the next
+// sync shares them
+func (db *DB) shareUnsyncedTodos() error {
+	ids, err := db.queryTodoIDs("get_todo_ids_without_versions.sql")
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		err := db.withTx(func(tx *sql.Tx) error {
+			todo, err := db.getTodoTx(tx, id)
+			if err != nil {
+				return err
+			}
+			return db.recordChanges(tx, todo.UUID, todoFieldValues(*todo))
+		})
+		if err != nil {
+			return fmt.Errorf("failed to share todo %d: %w", id, err)
+		}
+	}
+
+	return nil
+}
+
+// queryTodoIDs runs a query from the named file that selects todo IDs
+func (db *DB) queryTodoIDs(name string) ([]int, error) {
+	query, err := loadSQL(name)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.conn.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// getPendingOps returns the local changes not yet pushed, oldest first
+func (db *DB) getPendingOps() ([]SyncOp, error) {
+	query, err := loadSQL("get_pending_ops.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.conn.Query(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanSyncOps(rows)
+}
+
+// scanSyncOps reads op rows selected as seq, op_id, todo_uuid, field, value, version, device
+func scanSyncOps(rows *sql.Rows) ([]SyncOp, error) {
+	defer rows.Close()
+
+	var ops []SyncOp
+	for rows.Next() {
+		var op SyncOp
+		err := rows.Scan(&op.Seq, &op.OpID, &op.TodoUUID, &op.Field, &op.Value, &op.Version, &op.Device)
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, op)
+	}
+
+	return ops, rows.Err()
+}
+
+// syncOps pushes pending local changes to the backend, then pulls and
+// merges changes from other devices with per-field last-writer-wins.
+// A field edited both locally and remotely since the last sync is also
+// recorded as a conflict so the losing value can be restored.
+func (db *DB) syncOps() (SyncResult, error) {
+	var result SyncResult
+
+	pending, err := db.getPendingOps()
+	if err != nil {
+		return result, fmt.Errorf("failed to read pending changes: %w", err)
+	}
+
+	if len(pending) > 0 {
+		if err := db.backend.Push(pending); err != nil {
+			return result, fmt.Errorf("failed to push changes: %w", err)
+		}
+		result.OpsPushed = len(pending)
+	}
+
+	cursor, err := getSyncState(db.conn, syncStateCursor)
+	if err != nil {
+		return result, err
+	}
+
+	remoteOps, nextCursor, err := db.backend.Pull(cursor)
+	if err != nil {
+		return result, fmt.Errorf("failed to pull changes: %w", err)
+	}
+
+	// The latest unsynced local value of each field, for conflict detection
+	localEdits := make(map[string]SyncOp)
+	var lastPending int64
+	for _, op := range pending {
+		localEdits[op.TodoUUID+"/"+op.Field] = op
+		lastPending = op.Seq
+	}
+
+	err = db.withTx(func(tx *sql.Tx) error {
+		for _, op := range remoteOps {
+			if op.Device == db.deviceID {
+				continue
+			}
+
+			conflicted, err := db.applyRemoteOp(tx, op, localEdits)
+			if err != nil {
+				return fmt.Errorf("failed to apply change to %s: %w", op.TodoUUID, err)
+			}
+
+			result.OpsPulled++
+			if conflicted {
+				result.Conflicts++
+			}
+		}
+
+		if lastPending > 0 {
+			clearSQL, err := loadSQL("clear_pending_ops.sql")
+			if err != nil {
+				return err
+			}
+			if _, err := tx.Exec(clearSQL, lastPending); err != nil {
+				return err
+			}
+		}
+
+		return setSyncState(tx, syncStateCursor, nextCursor)
+	})
+
+	return result, err
+}
+
+// applyRemoteOp merges one remote field change, reporting whether it
+// conflicted with an unsynced local edit
+func (db *DB) applyRemoteOp(tx *sql.Tx, op SyncOp, localEdits map[string]SyncOp) (bool, error) {
+	if _, err := db.observeVersion(tx, op.Version); err != nil {
+		return false, err
+	}
+
+	getVersionSQL, err := loadSQL("get_field_version.sql")
+	if err != nil {
+		return false, err
+	}
+
+	var localVersion int64
+	var localDevice string
+	err = tx.QueryRow(getVersionSQL, op.TodoUUID, op.Field).Scan(&localVersion, &localDevice)
+	hasLocal := true
+	if errors.Is(err, sql.ErrNoRows) {
+		hasLocal = false
+	} else if err != nil {
+		return false, err
+	}
+
+	remoteWins := !hasLocal || op.newerThan(localVersion, localDevice)
+
+	conflicted := false
+	if local, ok := localEdits[op.TodoUUID+"/"+op.Field]; ok && local.Value != op.Value {
+		winner := conflictLocal
+		if remoteWins {
+			winner = conflictRemote
+		}
+
+		conflictSQL, err := loadSQL("insert_sync_conflict.sql")
+		if err != nil {
+			return false, err
+		}
+		if _, err := tx.Exec(conflictSQL, op.TodoUUID, op.Field, local.Value, op.Value, winner); err != nil {
+			return false, err
+		}
+		conflicted = true
+	}
+
+	if !remoteWins {
+		return conflicted, nil
+	}
+
+	setVersionSQL, err := loadSQL("set_field_version.sql")
+	if err != nil {
+		return false, err
+	}
+	if _, err := tx.Exec(setVersionSQL, op.TodoUUID, op.Field, op.Version, op.Device); err != nil {
+		return false, err
+	}
+
+	return conflicted, db.applyFieldValue(tx, op.TodoUUID, op.Field, op.Value)
+}
+
+// applyFieldValue writes a synced field value to the todos table,
+// creating the todo if this is the first field seen for it
+func (db *DB) applyFieldValue(tx *sql.Tx, todoUUID, field, value string) error {
+	decoded, err := decodeFieldValue(field, value)
+	if err != nil {
+		return fmt.Errorf("invalid value for %s: %w", field, err)
+	}
+
+	if field == fieldDeleted {
+		if deleted, _ := decoded.(bool); !deleted {
+			return nil
+		}
+
+		id, err := db.todoID(tx, todoUUID)
+		if err != nil || id == 0 {
+			return err
+		}
+		return db.removeTodoRows(tx, id)
+	}
+
+	var assignments string
+	var args []interface{}
+	if field == fieldSchedule {
+		block, _ := decoded.(TimeBlock)
+		assignments = "scheduled_start = ?, scheduled_end = ?, all_day = ?"
+		args = []interface{}{block.Start, block.End, block.AllDay}
+	} else {
+		column, ok := syncedColumns[field]
+		if !ok {
+			return nil
+		}
+		assignments = column + " = ?"
+		args = []interface{}{decoded}
+	}
+
+	exists, err := db.todoExists(tx, todoUUID)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		// Edits that arrive after a delete must not resurrect the todo
+		deleted, err := db.hasFieldVersion(tx, todoUUID, fieldDeleted)
+		if err != nil || deleted {
+			return err
+		}
+
+		insertSQL, err := loadSQL("insert_synced_todo.sql")
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(insertSQL, todoUUID); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(fmt.Sprintf("UPDATE todos SET %s, updated_at = CURRENT_TIMESTAMP WHERE uuid = ?", assignments), append(args, todoUUID)...)
+	return err
+}
+
+func (db *DB) todoExists(tx *sql.Tx, todoUUID string) (bool, error) {
+	id, err := db.todoID(tx, todoUUID)
+	return id != 0, err
+}
+
+// todoID returns the local ID of a synced todo, or 0 if there is none
+func (db *DB) todoID(tx *sql.Tx, todoUUID string) (int, error) {
+	query, err := loadSQL("get_todo_id_by_uuid.sql")
+	if err != nil {
+		return 0, err
+	}
+
+	var id int
+	err = tx.QueryRow(query, todoUUID).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	return id, err
+}
+
+func (db *DB) hasFieldVersion(tx *sql.Tx, todoUUID, field string) (bool, error) {
+	query, err := loadSQL("get_field_version.sql")
+	if err != nil {
+		return false, err
+	}
+
+	var version int64
+	var device string
+	err = tx.QueryRow(query, todoUUID, field).Scan(&version, &device)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// GetConflicts returns unresolved sync conflicts, oldest first
+func (db *DB) GetConflicts() ([]SyncConflict, error) {
+	query, err := loadSQL("get_sync_conflicts.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.conn.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var conflicts []SyncConflict
+	for rows.Next() {
+		var conflict SyncConflict
+		err := rows.Scan(
+			&conflict.ID,
+			&conflict.TodoUUID,
+			&conflict.TodoID,
+			&conflict.TodoTitle,
+			&conflict.Field,
+			&conflict.LocalValue,
+			&conflict.RemoteValue,
+			&conflict.Winner,
+			&conflict.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		conflicts = append(conflicts, conflict)
+	}
+
+	return conflicts, rows.Err()
+}
+
+// ResolveConflict settles a conflict by keeping the "local" or "remote"
+// value. Keeping the value that lost is recorded as a new edit so that it
+// propagates to other devices on the next sync.
+func (db *DB) ResolveConflict(id int, keep string) error {
+	if keep != conflictLocal && keep != conflictRemote {
+		return fmt.Errorf("invalid choice %q: must be %q or %q", keep, conflictLocal, conflictRemote)
+	}
+
+	conflicts, err := db.GetConflicts()
+	if err != nil {
+		return err
+	}
+
+	var conflict *SyncConflict
+	for i := range conflicts {
+		if conflicts[i].ID == id {
+			conflict = &conflicts[i]
+			break
+		}
+	}
+	if conflict == nil {
+		return fmt.Errorf("conflict %d not found", id)
+	}
+
+	resolveSQL, err := loadSQL("resolve_sync_conflict.sql")
+	if err != nil {
+		return err
+	}
+
+	return db.withTx(func(tx *sql.Tx) error {
+		if keep != conflict.Winner && conflict.TodoID != 0 {
+			value := conflict.LocalValue
+			if keep == conflictRemote {
+				value = conflict.RemoteValue
+			}
+
+			if err := db.applyFieldValue(tx, conflict.TodoUUID, conflict.Field, value); err != nil {
+				return err
+			}
+			if err := db.recordChanges(tx, conflict.TodoUUID, map[string]string{conflict.Field: value}); err != nil {
+				return err
+			}
+		}
+
+		_, err := tx.Exec(resolveSQL, id)
+		return err
+	})
+}
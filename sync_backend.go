@@ -0,0 +1,130 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// libsqlOpsBackend exchanges sync ops through a shared sync_ops table in
+// a remote libsql database. Each op gets a sequence number there, which
+// devices use as their pull cursor.
+type libsqlOpsBackend struct {
+	url  string
+	conn *sql.DB // Opened on first use so that working offline never fails at startup
+}
+
+// newLibsqlOpsBackend creates a backend for the database at dbURL.
+// Any URL the libsql driver accepts works, including file: URLs.
+func newLibsqlOpsBackend(dbURL string, authToken *string) (*libsqlOpsBackend, error) {
+	u, err := url.Parse(dbURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sync url: %w", err)
+	}
+
+	if authToken != nil {
+		query := u.Query()
+		query.Set("authToken", *authToken)
+		u.RawQuery = query.Encode()
+	}
+
+	return &libsqlOpsBackend{url: u.String()}, nil
+}
+
+// connect opens the remote database and makes sure the ops table exists
+func (b *libsqlOpsBackend) connect() (*sql.DB, error) {
+	if b.conn != nil {
+		return b.conn, nil
+	}
+
+	conn, err := sql.Open("libsql", b.url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open remote database: %w", err)
+	}
+
+	createSQL, err := loadSQL("create_remote_ops.sql")
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if _, err := conn.Exec(createSQL); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to prepare remote database: %w", err)
+	}
+
+	b.conn = conn
+	return conn, nil
+}
+
+func (b *libsqlOpsBackend) Push(ops []SyncOp) error {
+	conn, err := b.connect()
+	if err != nil {
+		return err
+	}
+
+	pushSQL, err := loadSQL("push_remote_op.sql")
+	if err != nil {
+		return err
+	}
+
+	tx, err := conn.Begin()
+	if err != nil {
+		return err
+	}
+
+	for _, op := range ops {
+		_, err := tx.Exec(pushSQL, op.OpID, op.TodoUUID, op.Field, op.Value, op.Version, op.Device)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (b *libsqlOpsBackend) Pull(cursor string) ([]SyncOp, string, error) {
+	conn, err := b.connect()
+	if err != nil {
+		return nil, cursor, err
+	}
+
+	var after int64
+	if cursor != "" {
+		after, err = strconv.ParseInt(cursor, 10, 64)
+		if err != nil {
+			return nil, cursor, fmt.Errorf("invalid sync cursor %q: %w", cursor, err)
+		}
+	}
+
+	pullSQL, err := loadSQL("pull_remote_ops.sql")
+	if err != nil {
+		return nil, cursor, err
+	}
+
+	rows, err := conn.Query(pullSQL, after)
+	if err != nil {
+		return nil, cursor, err
+	}
+
+	ops, err := scanSyncOps(rows)
+	if err != nil {
+		return nil, cursor, err
+	}
+
+	if len(ops) > 0 {
+		after = ops[len(ops)-1].Seq
+	}
+
+	return ops, strconv.FormatInt(after, 10), nil
+}
+
+// Close releases the remote connection if one was opened
+func (b *libsqlOpsBackend) Close() error {
+	if b.conn == nil {
+		return nil
+	}
+	return b.conn.Close()
+}
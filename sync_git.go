@@ -0,0 +1,531 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// gitTodoDir is the directory inside the repository holding one file per todo
+const gitTodoDir = "todos"
+
+// gitBackend syncs through a git repository of plain files, one Markdown
+// file per todo with its fields and per-field versions in YAML front
+// matter. Every local change is committed as it happens, and syncing
+// fetches, merges field by field and pushes against the remote.
+type gitBackend struct {
+	dir    string // Working repository
+	remote string // Remote URL or path; empty for a local-only history
+	branch string
+}
+
+// todoFile is the front matter of a todo file. The description is stored
+// as the Markdown body.
+type todoFile struct {
+	UUID            string                  `yaml:"uuid"`
+	Title           string                  `yaml:"title"`
+	Done            bool                    `yaml:"done"`
+	DueDate         *time.Time              `yaml:"due_date,omitempty"`
+	ScheduledStart  *time.Time              `yaml:"scheduled_start,omitempty"`
+	ScheduledEnd    *time.Time              `yaml:"scheduled_end,omitempty"`
+	AllDay          bool                    `yaml:"all_day,omitempty"`
+	Tags            []string                `yaml:"tags,omitempty"`
+	Project         string                  `yaml:"project,omitempty"`
+	Priority        int                     `yaml:"priority,omitempty"`
+	EstimateMinutes int                     `yaml:"estimate_minutes,omitempty"`
+	TimeZone        string                  `yaml:"timezone,omitempty"`
+	Deleted         bool                    `yaml:"deleted,omitempty"`
+	Versions        map[string]fieldVersion `yaml:"versions"`
+	Description     string                  `yaml:"-"`
+}
+
+// fieldVersion records which device last wrote a field, and when
+type fieldVersion struct {
+	Version int64  `yaml:"version"`
+	Device  string `yaml:"device"`
+}
+
+// newGitBackend opens the repository at dir, initialising it with remote
+// as origin if it does not exist yet
+func newGitBackend(dir, remote, branch string) (*gitBackend, error) {
+	if branch == "" {
+		branch = "main"
+	}
+	b := &gitBackend{dir: dir, remote: remote, branch: branch}
+
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		if err := b.init(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Commits are made automatically, so fall back to a local identity
+	// rather than failing when git has none configured
+	if email, _ := b.git("config", "user.email"); email == "" {
+		if _, err := b.git("config", "user.email", "lithium@localhost"); err != nil {
+			return nil, err
+		}
+		if _, err := b.git("config", "user.name", "Lithium"); err != nil {
+			return nil, err
+		}
+	}
+
+	return b, nil
+}
+
+// init creates the repository and points origin at the remote
+func (b *gitBackend) init() error {
+	if err := os.MkdirAll(b.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create git directory: %w", err)
+	}
+
+	if _, err := b.git("init", "--initial-branch", b.branch); err != nil {
+		return err
+	}
+
+	if b.remote != "" {
+		if _, err := b.git("remote", "add", "origin", b.remote); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// git runs a git command in the repository and returns its trimmed output
+func (b *gitBackend) git(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = b.dir
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s: %s", strings.Join(args, " "), strings.TrimSpace(stderr.String()+" "+err.Error()))
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// todoPath returns the repository-relative path of a todo's file
+func todoPath(todoUUID string) string {
+	return path.Join(gitTodoDir, todoUUID+".md")
+}
+
+// parseTodoFile parses a todo file's front matter and body
+func parseTodoFile(data []byte) (*todoFile, error) {
+	content := string(data)
+	if !strings.HasPrefix(content, "---\n") {
+		return nil, errors.New("missing front matter")
+	}
+
+	parts := strings.SplitN(content[len("---\n"):], "\n---\n", 2)
+	if len(parts) != 2 {
+		return nil, errors.New("unterminated front matter")
+	}
+
+	var file todoFile
+	if err := yaml.Unmarshal([]byte(parts[0]), &file); err != nil {
+		return nil, err
+	}
+
+	file.Description = strings.TrimSuffix(strings.TrimPrefix(parts[1], "\n"), "\n")
+	if file.Versions == nil {
+		file.Versions = make(map[string]fieldVersion)
+	}
+
+	return &file, nil
+}
+
+// render formats the todo file as Markdown with YAML front matter
+func (f *todoFile) render() ([]byte, error) {
+	frontMatter, err := yaml.Marshal(f)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	buf.Write(frontMatter)
+	buf.WriteString("---\n")
+	if f.Description != "" {
+		buf.WriteString("\n")
+		buf.WriteString(f.Description)
+		buf.WriteString("\n")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// fieldValue returns the encoded value of a synced field
+func (f *todoFile) fieldValue(field string) string {
+	switch field {
+	case fieldTitle:
+		return encodeString(f.Title)
+	case fieldDescription:
+		return encodeString(f.Description)
+	case fieldDone:
+		return encodeBool(f.Done)
+	case fieldDueDate:
+		return encodeTime(f.DueDate)
+	case fieldSchedule:
+		return encodeBlock(f.ScheduledStart, f.ScheduledEnd, f.AllDay)
+	case fieldTags:
+		return encodeString(joinTags(f.Tags))
+	case fieldProject:
+		return encodeString(f.Project)
+	case fieldPriority:
+		return encodeInt(f.Priority)
+	case fieldEstimate:
+		return encodeInt(f.EstimateMinutes)
+	case fieldTimeZone:
+		return encodeString(f.TimeZone)
+	case fieldDeleted:
+		return encodeBool(f.Deleted)
+	}
+	return ""
+}
+
+// applyOp sets a field from an op if the op is newer than the file's
+// version of that field, reporting whether the file changed
+func (f *todoFile) applyOp(op SyncOp) (bool, error) {
+	if current, ok := f.Versions[op.Field]; ok && !op.newerThan(current.Version, current.Device) {
+		return false, nil
+	}
+
+	decoded, err := decodeFieldValue(op.Field, op.Value)
+	if err != nil {
+		return false, err
+	}
+
+	switch op.Field {
+	case fieldTitle:
+		f.Title, _ = decoded.(string)
+	case fieldDescription:
+		f.Description, _ = decoded.(string)
+	case fieldDone:
+		f.Done, _ = decoded.(bool)
+	case fieldTags:
+		tags, _ := decoded.(string)
+		f.Tags = splitTags(tags)
+	case fieldProject:
+		f.Project, _ = decoded.(string)
+	case fieldPriority:
+		f.Priority, _ = decoded.(int)
+	case fieldEstimate:
+		f.EstimateMinutes, _ = decoded.(int)
+	case fieldTimeZone:
+		f.TimeZone, _ = decoded.(string)
+	case fieldDeleted:
+		f.Deleted, _ = decoded.(bool)
+	case fieldDueDate:
+		if t, ok := decoded.(time.Time); ok {
+			f.DueDate = &t
+		} else {
+			f.DueDate = nil
+		}
+	case fieldSchedule:
+		block, _ := decoded.(TimeBlock)
+		f.ScheduledStart, f.ScheduledEnd, f.AllDay = block.Start, block.End, block.AllDay
+	default:
+		return false, nil
+	}
+
+	f.Versions[op.Field] = fieldVersion{Version: op.Version, Device: op.Device}
+	return true, nil
+}
+
+// ops returns the file's fields as ops, optionally only those whose
+// version differs from base
+func (f *todoFile) ops(base *todoFile) []SyncOp {
+	fields := make([]string, 0, len(f.Versions))
+	for field := range f.Versions {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	var ops []SyncOp
+	for _, field := range fields {
+		version := f.Versions[field]
+		if base != nil && base.Versions[field] == version {
+			continue
+		}
+
+		ops = append(ops, SyncOp{
+			OpID:     fmt.Sprintf("%s/%s/%d", f.UUID, field, version.Version),
+			TodoUUID: f.UUID,
+			Field:    field,
+			Value:    f.fieldValue(field),
+			Version:  version.Version,
+			Device:   version.Device,
+		})
+	}
+
+	return ops
+}
+
+// readWorkingFile loads a todo file from the working tree, or a new empty
+// one if it does not exist yet
+func (b *gitBackend) readWorkingFile(todoUUID string) (*todoFile, error) {
+	data, err := os.ReadFile(filepath.Join(b.dir, todoPath(todoUUID)))
+	if errors.Is(err, os.ErrNotExist) {
+		return &todoFile{UUID: todoUUID, Versions: make(map[string]fieldVersion)}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseTodoFile(data)
+}
+
+func (b *gitBackend) writeWorkingFile(file *todoFile) error {
+	data, err := file.render()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Join(b.dir, gitTodoDir), 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(b.dir, todoPath(file.UUID)), data, 0644)
+}
+
+// readCommitFile loads a todo file as of a commit, or nil if it does not exist there
+func (b *gitBackend) readCommitFile(commit, filePath string) (*todoFile, error) {
+	if commit == "" {
+		return nil, nil
+	}
+
+	if _, err := b.git("cat-file", "-e", commit+":"+filePath); err != nil {
+		return nil, nil
+	}
+
+	data, err := b.git("show", commit+":"+filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseTodoFile([]byte(data + "\n"))
+}
+
+// commitAll commits every change in the working tree, doing nothing if it is clean
+func (b *gitBackend) commitAll(message string) error {
+	if _, err := b.git("add", "-A"); err != nil {
+		return err
+	}
+
+	status, err := b.git("status", "--porcelain")
+	if err != nil || status == "" {
+		return err
+	}
+
+	_, err = b.git("commit", "-m", message)
+	return err
+}
+
+// Push writes ops into the todo files and commits them. It is also called
+// after every local change, so each mutation gets its own commit.
+func (b *gitBackend) Push(ops []SyncOp) error {
+	files := make(map[string]*todoFile)
+	added := make(map[string]bool) // Todos with no file until now
+	var changed []string
+
+	for _, op := range ops {
+		file, ok := files[op.TodoUUID]
+		if !ok {
+			var err error
+			file, err = b.readWorkingFile(op.TodoUUID)
+			if err != nil {
+				return err
+			}
+			files[op.TodoUUID] = file
+			added[op.TodoUUID] = len(file.Versions) == 0
+		}
+
+		updated, err := file.applyOp(op)
+		if err != nil {
+			return err
+		}
+		if updated {
+			changed = append(changed, op.TodoUUID)
+		}
+	}
+
+	if len(changed) == 0 {
+		return nil
+	}
+
+	written := make(map[string]bool)
+	var summary []string
+	for _, todoUUID := range changed {
+		if written[todoUUID] {
+			continue
+		}
+		written[todoUUID] = true
+
+		file := files[todoUUID]
+		if err := b.writeWorkingFile(file); err != nil {
+			return err
+		}
+
+		verb := "update"
+		switch {
+		case file.Deleted:
+			verb = "delete"
+		case added[todoUUID]:
+			verb = "add"
+		}
+		summary = append(summary, verb+" "+file.Title)
+	}
+
+	message := strings.Join(summary, ", ")
+	return b.commitAll(strings.ToUpper(message[:1]) + message[1:])
+}
+
+// Pull fetches the remote branch, returns the field changes made there
+// since cursor (the last remote commit pulled), then merges and pushes
+// so that the remote holds both sides' changes
+func (b *gitBackend) Pull(cursor string) ([]SyncOp, string, error) {
+	if b.remote == "" {
+		return nil, cursor, nil
+	}
+
+	if _, err := b.git("fetch", "origin"); err != nil {
+		return nil, cursor, err
+	}
+
+	remoteRef := "origin/" + b.branch
+	remoteHead, err := b.git("rev-parse", "--verify", "--quiet", remoteRef)
+	if err != nil || remoteHead == "" {
+		// Nothing pushed yet: publish our history
+		return nil, cursor, b.push()
+	}
+
+	ops, err := b.changesBetween(cursor, remoteHead)
+	if err != nil {
+		return nil, cursor, err
+	}
+
+	if err := b.merge(remoteHead); err != nil {
+		return nil, cursor, err
+	}
+
+	return ops, remoteHead, b.push()
+}
+
+// changesBetween returns ops for fields whose version changed between two commits
+func (b *gitBackend) changesBetween(from, to string) ([]SyncOp, error) {
+	var listing string
+	var err error
+	if from == "" {
+		listing, err = b.git("ls-tree", "-r", "--name-only", to, gitTodoDir)
+	} else {
+		listing, err = b.git("diff", "--name-only", from, to, "--", gitTodoDir)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ops []SyncOp
+	for _, filePath := range strings.Fields(listing) {
+		after, err := b.readCommitFile(to, filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", filePath, err)
+		}
+		if after == nil {
+			continue
+		}
+
+		before, err := b.readCommitFile(from, filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", filePath, err)
+		}
+
+		ops = append(ops, after.ops(before)...)
+	}
+
+	return ops, nil
+}
+
+// merge combines the remote commit into the local branch. Files changed on
+// both sides are merged field by field with last-writer-wins, so the
+// result is the same whichever device merges.
+func (b *gitBackend) merge(remoteHead string) error {
+	if _, err := b.git("rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+		// No local commits yet: start from the remote history
+		_, err := b.git("reset", "--hard", remoteHead)
+		return err
+	}
+
+	if _, err := b.git("merge-base", "--is-ancestor", remoteHead, "HEAD"); err == nil {
+		return nil
+	}
+
+	if _, err := b.git("merge-base", "--is-ancestor", "HEAD", remoteHead); err == nil {
+		_, err := b.git("merge", "--ff-only", remoteHead)
+		return err
+	}
+
+	base, err := b.git("merge-base", "HEAD", remoteHead)
+	if err != nil {
+		return err
+	}
+
+	remoteOps, err := b.changesBetween(base, remoteHead)
+	if err != nil {
+		return err
+	}
+
+	// Keep our tree, then fold in the remote's field changes
+	if _, err := b.git("merge", "--no-commit", "--no-ff", "-s", "ours", remoteHead); err != nil {
+		return err
+	}
+
+	files := make(map[string]*todoFile)
+	for _, op := range remoteOps {
+		file, ok := files[op.TodoUUID]
+		if !ok {
+			file, err = b.readWorkingFile(op.TodoUUID)
+			if err != nil {
+				return err
+			}
+			files[op.TodoUUID] = file
+		}
+
+		if _, err := file.applyOp(op); err != nil {
+			return err
+		}
+	}
+
+	for _, file := range files {
+		if err := b.writeWorkingFile(file); err != nil {
+			return err
+		}
+	}
+
+	if _, err := b.git("add", "-A"); err != nil {
+		return err
+	}
+
+	_, err = b.git("commit", "--no-edit", "-m", "Merge changes from "+b.remote)
+	return err
+}
+
+func (b *gitBackend) push() error {
+	if _, err := b.git("rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+		return nil // Nothing committed yet
+	}
+
+	_, err := b.git("push", "origin", "HEAD:refs/heads/"+b.branch)
+	return err
+}
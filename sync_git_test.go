@@ -0,0 +1,194 @@
+package main
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newBareRemote creates an empty bare repository to sync through
+func newBareRemote(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	remote := filepath.Join(t.TempDir(), "remote.git")
+	if out, err := exec.Command("git", "init", "--bare", remote).CombinedOutput(); err != nil {
+		t.Fatalf("git init --bare: %v: %s", err, out)
+	}
+	return remote
+}
+
+// newGitTestDB opens a database that syncs through git with remote
+func newGitTestDB(t *testing.T, remote string) *DB {
+	t.Helper()
+	dir := t.TempDir()
+	db, err := NewDB(&Config{
+		DatabasePath: "file:" + filepath.Join(dir, "tasks.db"),
+		Timezone:     "UTC",
+		SyncMode:     SyncModeGit,
+		GitDir:       filepath.Join(dir, "git"),
+		GitRemote:    remote,
+	})
+	if err != nil {
+		t.Fatalf("opening database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// gitOutput runs git in dir and returns its trimmed output
+func gitOutput(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v: %s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestGitCommitMessages(t *testing.T) {
+	db := newGitTestDB(t, "")
+	dir := db.backend.(*gitBackend).dir
+
+	if err := db.AddTodo(Todo{Title: "Buy milk"}); err != nil {
+		t.Fatal(err)
+	}
+	todo := onlyTodo(t, db)
+	if err := db.UpdateTodo(todo.ID, "Buy oat milk", "", nil, nil, nil, false, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.DeleteTodo(todo.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	got := strings.Split(gitOutput(t, dir, "log", "--reverse", "--format=%s"), "\n")
+	want := []string{"Add Buy milk", "Update Buy oat milk", "Delete Buy oat milk"}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got commits %q, want %q", got, want)
+	}
+}
+
+func TestGitSyncPushesAndPulls(t *testing.T) {
+	remote := newBareRemote(t)
+	a, b := newGitTestDB(t, remote), newGitTestDB(t, remote)
+
+	if err := a.AddTodo(Todo{Title: "Buy milk", Description: "Two litres", Tags: []string{"errand"}}); err != nil {
+		t.Fatal(err)
+	}
+	mustSync(t, a)
+
+	uuid := onlyTodo(t, a).UUID
+	file := gitOutput(t, remote, "show", "main:"+todoPath(uuid))
+	if !strings.Contains(file, "title: Buy milk") || !strings.HasSuffix(file, "Two litres") {
+		t.Errorf("remote file:\n%s", file)
+	}
+
+	result := mustSync(t, b)
+	if result.OpsPulled == 0 {
+		t.Error("second device pulled nothing")
+	}
+	todo := onlyTodo(t, b)
+	if todo.UUID != uuid || todo.Title != "Buy milk" || todo.Description != "Two litres" || !containsFold(todo.Tags, "errand") {
+		t.Errorf("second device got %+v", todo)
+	}
+
+	// Changes flow back the other way too
+	if err := b.ToggleTodo(todo.ID); err != nil {
+		t.Fatal(err)
+	}
+	mustSync(t, b)
+	mustSync(t, a)
+	if !onlyTodo(t, a).Done {
+		t.Error("first device didn't pull the toggle")
+	}
+}
+
+func TestGitSyncMergesDivergedHistories(t *testing.T) {
+	remote := newBareRemote(t)
+	a, b := newGitTestDB(t, remote), newGitTestDB(t, remote)
+
+	if err := a.AddTodo(Todo{Title: "Buy milk"}); err != nil {
+		t.Fatal(err)
+	}
+	mustSync(t, a)
+	mustSync(t, b)
+
+	// Both devices commit before either syncs: one renames, one completes
+	if err := a.UpdateTodo(onlyTodo(t, a).ID, "Buy oat milk", "", nil, nil, nil, false, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.ToggleTodo(onlyTodo(t, b).ID); err != nil {
+		t.Fatal(err)
+	}
+
+	mustSync(t, a)
+	if result := mustSync(t, b); result.Conflicts != 0 {
+		t.Errorf("got %d conflicts, want none for different fields", result.Conflicts)
+	}
+	mustSync(t, a)
+
+	for name, db := range map[string]*DB{"a": a, "b": b} {
+		todo := onlyTodo(t, db)
+		if todo.Title != "Buy oat milk" || !todo.Done {
+			t.Errorf("%s: got %q done=%v, want both edits", name, todo.Title, todo.Done)
+		}
+	}
+
+	dir := b.backend.(*gitBackend).dir
+	if subject := gitOutput(t, dir, "log", "-1", "--format=%s"); !strings.HasPrefix(subject, "Merge changes from") {
+		t.Errorf("got %q, want a merge commit", subject)
+	}
+	file := gitOutput(t, remote, "show", "main:"+todoPath(onlyTodo(t, b).UUID))
+	if !strings.Contains(file, "title: Buy oat milk") || !strings.Contains(file, "done: true") {
+		t.Errorf("remote file missing an edit:\n%s", file)
+	}
+}
+
+func TestGitSyncConflictingEdits(t *testing.T) {
+	remote := newBareRemote(t)
+	a, b := newGitTestDB(t, remote), newGitTestDB(t, remote)
+
+	if err := a.AddTodo(Todo{Title: "Buy milk"}); err != nil {
+		t.Fatal(err)
+	}
+	mustSync(t, a)
+	mustSync(t, b)
+
+	if err := a.UpdateTodo(onlyTodo(t, a).ID, "From A", "", nil, nil, nil, false, 0); err != nil {
+		t.Fatal(err)
+	}
+	// B edits later, so it has the newer version and wins
+	time.Sleep(time.Millisecond)
+	if err := b.UpdateTodo(onlyTodo(t, b).ID, "From B", "", nil, nil, nil, false, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	mustSync(t, a)
+	if result := mustSync(t, b); result.Conflicts != 1 {
+		t.Fatalf("got %d conflicts, want 1", result.Conflicts)
+	}
+	mustSync(t, a)
+
+	for name, db := range map[string]*DB{"a": a, "b": b} {
+		if title := onlyTodo(t, db).Title; title != "From B" {
+			t.Errorf("%s: title %q, want %q", name, title, "From B")
+		}
+	}
+	file := gitOutput(t, remote, "show", "main:"+todoPath(onlyTodo(t, a).UUID))
+	if !strings.Contains(file, "title: From B") {
+		t.Errorf("remote kept the losing edit:\n%s", file)
+	}
+
+	conflicts, err := b.GetConflicts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conflicts) != 1 || conflicts[0].Field != fieldTitle || conflicts[0].Winner != conflictLocal {
+		t.Errorf("got conflicts %+v, want one title conflict won locally", conflicts)
+	}
+}
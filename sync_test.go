@@ -0,0 +1,283 @@
+package main
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+// memoryBackend is a SyncBackend shared by test databases in place of a
+// remote server
+type memoryBackend struct {
+	ops  []SyncOp
+	seen map[string]bool
+}
+
+func (b *memoryBackend) Push(ops []SyncOp) error {
+	if b.seen == nil {
+		b.seen = make(map[string]bool)
+	}
+	for _, op := range ops {
+		if b.seen[op.OpID] {
+			continue
+		}
+		b.seen[op.OpID] = true
+		op.Seq = int64(len(b.ops) + 1)
+		b.ops = append(b.ops, op)
+	}
+	return nil
+}
+
+func (b *memoryBackend) Pull(cursor string) ([]SyncOp, string, error) {
+	after := 0
+	if cursor != "" {
+		var err error
+		if after, err = strconv.Atoi(cursor); err != nil {
+			return nil, cursor, err
+		}
+	}
+	return b.ops[after:], strconv.Itoa(len(b.ops)), nil
+}
+
+// newSyncedPair opens two databases syncing through one backend, with a
+// todo both of them have already synced
+func newSyncedPair(t *testing.T) (*DB, *DB, string) {
+	t.Helper()
+	backend := &memoryBackend{}
+	a, b := newTestDB(t), newTestDB(t)
+	a.backend, b.backend = backend, backend
+
+	if err := a.AddTodo(Todo{Title: "Buy milk"}); err != nil {
+		t.Fatal(err)
+	}
+	mustSync(t, a)
+	mustSync(t, b)
+
+	todo := onlyTodo(t, b)
+	if todo.Title != "Buy milk" {
+		t.Fatalf("second device got %q, want the synced todo", todo.Title)
+	}
+	return a, b, todo.UUID
+}
+
+func mustSync(t *testing.T, db *DB) SyncResult {
+	t.Helper()
+	result, err := db.Sync()
+	if err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	return result
+}
+
+// onlyTodo returns the single todo in db
+func onlyTodo(t *testing.T, db *DB) Todo {
+	t.Helper()
+	todos, err := db.GetAllTodos()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(todos) != 1 {
+		t.Fatalf("got %d todos, want 1", len(todos))
+	}
+	return todos[0]
+}
+
+func TestSyncMergesEditsToDifferentFields(t *testing.T) {
+	a, b, _ := newSyncedPair(t)
+
+	todoA, todoB := onlyTodo(t, a), onlyTodo(t, b)
+	if err := a.UpdateTodo(todoA.ID, "Buy oat milk", "", nil, nil, nil, false, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.ToggleTodo(todoB.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	mustSync(t, a)
+	if result := mustSync(t, b); result.Conflicts != 0 {
+		t.Errorf("got %d conflicts, want none for different fields", result.Conflicts)
+	}
+	mustSync(t, a)
+
+	for name, db := range map[string]*DB{"a": a, "b": b} {
+		todo := onlyTodo(t, db)
+		if todo.Title != "Buy oat milk" || !todo.Done {
+			t.Errorf("%s: got %q done=%v, want both edits", name, todo.Title, todo.Done)
+		}
+	}
+}
+
+func TestSyncConcurrentEditsConvergeAndRecordConflict(t *testing.T) {
+	a, b, uuid := newSyncedPair(t)
+
+	if err := a.UpdateTodo(onlyTodo(t, a).ID, "From A", "", nil, nil, nil, false, 0); err != nil {
+		t.Fatal(err)
+	}
+	// B edits later, so it has the newer version and wins
+	time.Sleep(time.Millisecond)
+	if err := b.UpdateTodo(onlyTodo(t, b).ID, "From B", "", nil, nil, nil, false, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	mustSync(t, a)
+	if result := mustSync(t, b); result.Conflicts != 1 {
+		t.Fatalf("got %d conflicts, want 1", result.Conflicts)
+	}
+	mustSync(t, a)
+
+	for name, db := range map[string]*DB{"a": a, "b": b} {
+		if title := onlyTodo(t, db).Title; title != "From B" {
+			t.Errorf("%s: title %q, want the last write %q", name, title, "From B")
+		}
+	}
+
+	conflicts, err := b.GetConflicts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conflicts) != 1 {
+		t.Fatalf("got %d conflicts, want 1", len(conflicts))
+	}
+	conflict := conflicts[0]
+	if conflict.TodoUUID != uuid || conflict.Field != fieldTitle || conflict.Winner != conflictLocal {
+		t.Errorf("got conflict %+v, want a title conflict won locally", conflict)
+	}
+	if conflict.LocalValue != encodeString("From B") || conflict.RemoteValue != encodeString("From A") {
+		t.Errorf("got values %s / %s", conflict.LocalValue, conflict.RemoteValue)
+	}
+
+	// Keeping the losing value makes it the newest edit everywhere
+	if err := b.ResolveConflict(conflict.ID, conflictRemote); err != nil {
+		t.Fatal(err)
+	}
+	mustSync(t, b)
+	mustSync(t, a)
+	for name, db := range map[string]*DB{"a": a, "b": b} {
+		if title := onlyTodo(t, db).Title; title != "From A" {
+			t.Errorf("%s: title %q after resolving, want %q", name, title, "From A")
+		}
+	}
+	if conflicts, _ := b.GetConflicts(); len(conflicts) != 0 {
+		t.Errorf("got %d conflicts after resolving, want none", len(conflicts))
+	}
+}
+
+func TestSyncKeepsBlocksWhole(t *testing.T) {
+	a, b, _ := newSyncedPair(t)
+	id := onlyTodo(t, a).ID
+	if err := a.ScheduleTodo(id, at(2026, 10, 19, 9, 0), at(2026, 10, 19, 10, 0), false); err != nil {
+		t.Fatal(err)
+	}
+	mustSync(t, a)
+	mustSync(t, b)
+
+	// B moves the block, then A extends it. Taken field by field, A's
+	// newer end would land after B's start and leave a block ending before
+	// it begins.
+	if err := b.ScheduleTodo(onlyTodo(t, b).ID, at(2026, 10, 19, 14, 0), at(2026, 10, 19, 15, 0), false); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond)
+	if err := a.ScheduleTodo(id, at(2026, 10, 19, 9, 0), at(2026, 10, 19, 11, 0), false); err != nil {
+		t.Fatal(err)
+	}
+
+	mustSync(t, b)
+	if result := mustSync(t, a); result.Conflicts != 1 {
+		t.Fatalf("got %d conflicts, want 1 for the block", result.Conflicts)
+	}
+	mustSync(t, b)
+
+	for name, db := range map[string]*DB{"a": a, "b": b} {
+		todo := onlyTodo(t, db)
+		if !todo.ScheduledStart.Equal(*at(2026, 10, 19, 9, 0)) || !todo.ScheduledEnd.Equal(*at(2026, 10, 19, 11, 0)) {
+			t.Errorf("%s: block %v-%v, want A's 9-11am whole", name, todo.ScheduledStart, todo.ScheduledEnd)
+		}
+	}
+
+	conflicts, err := a.GetConflicts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conflicts) != 1 || conflicts[0].Field != fieldSchedule {
+		t.Fatalf("got conflicts %+v, want one on the schedule", conflicts)
+	}
+	if got, want := FormatFieldValue(fieldSchedule, conflicts[0].RemoteValue, time.UTC), "Oct 19, 2026 2:00pm-3:00pm"; got != want {
+		t.Errorf("remote block shown as %q, want %q", got, want)
+	}
+}
+
+func TestSyncRecordsNothingWithoutBackend(t *testing.T) {
+	db := newTestDB(t)
+	if err := db.AddTodo(Todo{Title: "Buy milk"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.ToggleTodo(1); err != nil {
+		t.Fatal(err)
+	}
+	if pending, _ := db.getPendingOps(); len(pending) != 0 {
+		t.Fatalf("got %d pending ops without a backend, want none", len(pending))
+	}
+
+	// Turning sync on later still shares what was made before
+	db.backend = &memoryBackend{}
+	if err := db.shareUnsyncedTodos(); err != nil {
+		t.Fatal(err)
+	}
+	other := newTestDB(t)
+	other.backend = db.backend
+	mustSync(t, db)
+	mustSync(t, other)
+	if todo := onlyTodo(t, other); todo.Title != "Buy milk" || !todo.Done {
+		t.Errorf("got %q done=%v on the other device, want the todo as it was", todo.Title, todo.Done)
+	}
+}
+
+func TestSyncDeleteWinsOverEdit(t *testing.T) {
+	a, b, _ := newSyncedPair(t)
+
+	todoB := onlyTodo(t, b)
+	now := time.Now()
+	if err := b.StartTimer(todoB.ID, now.Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.StopTimer(now); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.LogPomodoro(todoB.ID, now.Add(-25*time.Minute), now); err != nil {
+		t.Fatal(err)
+	}
+	mustSync(t, b)
+
+	if err := a.DeleteTodo(onlyTodo(t, a).ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.UpdateTodo(todoB.ID, "Buy oat milk", "", nil, nil, nil, false, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	mustSync(t, a)
+	mustSync(t, b)
+	mustSync(t, a)
+
+	for name, db := range map[string]*DB{"a": a, "b": b} {
+		todos, err := db.GetAllTodos()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(todos) != 0 {
+			t.Errorf("%s: got %v, want the edit not to resurrect the deleted todo", name, todos)
+		}
+	}
+
+	// The synced delete cleans up like a local one
+	for _, table := range []string{"reminders", "time_entries", "pomodoros"} {
+		var count int
+		if err := b.conn.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&count); err != nil {
+			t.Fatal(err)
+		}
+		if count != 0 {
+			t.Errorf("%d %s left behind by the synced delete", count, table)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// TimeEntry is a stretch of time tracked against a todo
+type TimeEntry struct {
+	ID        int
+	TodoID    int
+	StartedAt time.Time
+	StoppedAt *time.Time // Nil while the timer is running
+}
+
+// Running reports whether the entry's timer is still going
+func (e TimeEntry) Running() bool {
+	return e.StoppedAt == nil
+}
+
+// Duration returns how long the entry lasts, counting a running entry up
+// to now
+func (e TimeEntry) Duration(now time.Time) time.Duration {
+	end := now
+	if e.StoppedAt != nil {
+		end = *e.StoppedAt
+	}
+	return max(end.Sub(e.StartedAt), 0)
+}
+
+// within returns how much of the entry falls between from and to
+func (e TimeEntry) within(from, to, now time.Time) time.Duration {
+	start, end := e.StartedAt, now
+	if e.StoppedAt != nil {
+		end = *e.StoppedAt
+	}
+	if start.Before(from) {
+		start = from
+	}
+	if end.After(to) {
+		end = to
+	}
+	return max(end.Sub(start), 0)
+}
+
+// formatTimer formats elapsed time like a stopwatch, e.g. "1:05:09"
+func formatTimer(d time.Duration) string {
+	d = d.Truncate(time.Second)
+	return fmt.Sprintf("%d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+}
+
+// TimesheetTodo is the time tracked on one todo in a day
+type TimesheetTodo struct {
+	Todo     Todo
+	Duration time.Duration
+}
+
+// TimesheetProject is the time tracked on one project in a day
+type TimesheetProject struct {
+	Project  string // Empty for todos without a project
+	Duration time.Duration
+	Todos    []TimesheetTodo
+}
+
+// TimesheetDay is the time tracked in one day
+type TimesheetDay struct {
+	Date     time.Time
+	Duration time.Duration
+	Projects []TimesheetProject
+}
+
+// Timesheet is tracked time grouped by day, then project, then todo
+type Timesheet struct {
+	From  time.Time
+	To    time.Time // Exclusive
+	Days  []TimesheetDay
+	Total time.Duration
+}
+
+// BuildTimesheet totals the time tracked between from and to, splitting
+// entries that cross midnight between their days
+func BuildTimesheet(db Store, from, to, now time.Time) (*Timesheet, error) {
+	entries, err := db.GetTimeEntries(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	todos := make(map[int]Todo)
+	tracked := make(map[string]map[int]time.Duration) // Day key to todo ID to time
+	for _, entry := range entries {
+		if _, ok := todos[entry.TodoID]; !ok {
+			todo, err := db.GetTodo(entry.TodoID)
+			if err != nil {
+				return nil, err
+			}
+			todos[entry.TodoID] = *todo
+		}
+
+		for day := startOfDay(entry.StartedAt); day.Before(to); day = day.AddDate(0, 0, 1) {
+			dayFrom, dayTo := day, day.AddDate(0, 0, 1)
+			if dayFrom.Before(from) {
+				dayFrom = from
+			}
+			if dayTo.After(to) {
+				dayTo = to
+			}
+			if d := entry.within(dayFrom, dayTo, now); d > 0 {
+				key := day.Format("2006-01-02")
+				if tracked[key] == nil {
+					tracked[key] = make(map[int]time.Duration)
+				}
+				tracked[key][entry.TodoID] += d
+			}
+			if entry.StoppedAt != nil && !entry.StoppedAt.After(dayTo) {
+				break
+			}
+		}
+	}
+
+	sheet := &Timesheet{From: from, To: to}
+	for day := startOfDay(from); day.Before(to); day = day.AddDate(0, 0, 1) {
+		byTodo := tracked[day.Format("2006-01-02")]
+		if len(byTodo) == 0 {
+			continue
+		}
+
+		projects := make(map[string]*TimesheetProject)
+		timesheetDay := TimesheetDay{Date: day}
+		for id, d := range byTodo {
+			todo := todos[id]
+			project, ok := projects[todo.Project]
+			if !ok {
+				project = &TimesheetProject{Project: todo.Project}
+				projects[todo.Project] = project
+			}
+			project.Todos = append(project.Todos, TimesheetTodo{Todo: todo, Duration: d})
+			project.Duration += d
+			timesheetDay.Duration += d
+		}
+
+		for _, project := range projects {
+			sort.Slice(project.Todos, func(i, j int) bool {
+				return project.Todos[i].Todo.ID < project.Todos[j].Todo.ID
+			})
+			timesheetDay.Projects = append(timesheetDay.Projects, *project)
+		}
+		// Named projects in order, then todos without one
+		sort.Slice(timesheetDay.Projects, func(i, j int) bool {
+			a, b := timesheetDay.Projects[i].Project, timesheetDay.Projects[j].Project
+			if (a == "") != (b == "") {
+				return b == ""
+			}
+			return a < b
+		})
+
+		sheet.Days = append(sheet.Days, timesheetDay)
+		sheet.Total += timesheetDay.Duration
+	}
+
+	return sheet, nil
+}
+
+// projectLabel names a project for display
+func projectLabel(project string) string {
+	if project == "" {
+		return "(no project)"
+	}
+	return "+" + project
+}
+
+// Render formats the timesheet for display
+func (t *Timesheet) Render() string {
+	var s strings.Builder
+
+	last := t.To.AddDate(0, 0, -1)
+	title := "🧾 Timesheet: " + activeLocale.DateYear(t.From)
+	if !startOfDay(last).Equal(startOfDay(t.From)) {
+		title = fmt.Sprintf("🧾 Timesheet: %s - %s", activeLocale.Date(t.From), activeLocale.DateYear(last))
+	}
+	s.WriteString(titleStyle.Render(title))
+	s.WriteString("\n\n")
+
+	if len(t.Days) == 0 {
+		s.WriteString(descStyle.Render("No time tracked. Start a timer with: ") + styleCommand("li start <id>"))
+		s.WriteString("\n")
+		return s.String()
+	}
+
+	dayStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(ColorBlue)).Bold(true)
+	projectStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(ColorOrange))
+	for _, day := range t.Days {
+		s.WriteString(dayStyle.Render(fmt.Sprintf("%-40s %8s", activeLocale.WeekdayDate(day.Date), formatDuration(day.Duration))))
+		s.WriteString("\n")
+		for _, project := range day.Projects {
+			s.WriteString(projectStyle.Render(fmt.Sprintf("  %-38s %8s", projectLabel(project.Project), formatDuration(project.Duration))))
+			s.WriteString("\n")
+			for _, todo := range project.Todos {
+				s.WriteString(fmt.Sprintf("    %-36s %8s\n",
+					fitCell(fmt.Sprintf("[%d] %s", todo.Todo.ID, todo.Todo.Title), 36),
+					formatDuration(todo.Duration)))
+			}
+		}
+		s.WriteString("\n")
+	}
+
+	s.WriteString(commandStyle.Render(fmt.Sprintf("%-40s %8s", "Total", formatDuration(t.Total))))
+	s.WriteString("\n")
+
+	return s.String()
+}
+
+// WriteCSV writes one row per todo per day, with hours in decimal for
+// billing
+func (t *Timesheet) WriteCSV(w io.Writer) error {
+	out := csv.NewWriter(w)
+	if err := out.Write([]string{"date", "project", "todo_id", "todo", "hours"}); err != nil {
+		return err
+	}
+
+	for _, day := range t.Days {
+		for _, project := range day.Projects {
+			for _, todo := range project.Todos {
+				err := out.Write([]string{
+					day.Date.Format("2006-01-02"),
+					project.Project,
+					fmt.Sprint(todo.Todo.ID),
+					todo.Todo.Title,
+					fmt.Sprintf("%.2f", todo.Duration.Hours()),
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
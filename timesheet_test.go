@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestBuildTimesheetSplitsAtMidnight(t *testing.T) {
+	newYork := loadLocation(t, "America/New_York")
+	local := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 10, day, hour, minute, 0, 0, newYork)
+	}
+
+	for name, store := range map[string]Store{"memory": newTestStore(), "database": newTestDBIn(t, "America/New_York")} {
+		t.Run(name, func(t *testing.T) {
+			for _, todo := range []Todo{{Title: "Deploy", Project: "work"}, {Title: "Read"}} {
+				if err := store.AddTodo(todo); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// A late deploy running past local midnight, then reading still
+			// going at now
+			now := local(20, 9, 0)
+			for _, entry := range []struct {
+				id          int
+				start, stop time.Time
+			}{
+				{id: 1, start: local(19, 23, 0), stop: local(20, 1, 30)},
+				{id: 2, start: local(20, 8, 0), stop: now},
+			} {
+				if err := store.StartTimer(entry.id, entry.start); err != nil {
+					t.Fatal(err)
+				}
+				if entry.stop.Before(now) {
+					if _, err := store.StopTimer(entry.stop); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			sheet, err := BuildTimesheet(store, local(19, 0, 0), local(21, 0, 0), now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(sheet.Days) != 2 {
+				t.Fatalf("got %d days, want 2", len(sheet.Days))
+			}
+
+			first, second := sheet.Days[0], sheet.Days[1]
+			if first.Date.Day() != 19 || first.Duration != time.Hour {
+				t.Errorf("first day = %v with %v, want Oct 19 with 1h", first.Date, first.Duration)
+			}
+			if second.Date.Day() != 20 || second.Duration != 2*time.Hour+30*time.Minute {
+				t.Errorf("second day = %v with %v, want Oct 20 with 2h30m", second.Date, second.Duration)
+			}
+			// Named projects come before todos without one
+			if len(second.Projects) != 2 || second.Projects[0].Project != "work" || second.Projects[0].Duration != 90*time.Minute {
+				t.Errorf("second day projects = %+v", second.Projects)
+			}
+			if sheet.Total != 3*time.Hour+30*time.Minute {
+				t.Errorf("total = %v, want 3h30m", sheet.Total)
+			}
+
+			var csv bytes.Buffer
+			if err := sheet.WriteCSV(&csv); err != nil {
+				t.Fatal(err)
+			}
+			assertContains(t, csv.String(), "2026-10-19,work,1,Deploy,1.00", "2026-10-20,work,1,Deploy,1.50", "2026-10-20,,2,Read,1.00")
+		})
+	}
+}
+
+func TestCLITimers(t *testing.T) {
+	c, store, out := newTestCLI()
+	addTodos(t, store, NewFixedClock(testNow), "Deploy +work")
+
+	assertContains(t, run(c, out, "start", "1"), "Deploy")
+	assertContains(t, run(c, out, "status"), "Deploy")
+	if entry, _ := store.GetRunningTimer(); entry == nil || !entry.StartedAt.Equal(testNow) {
+		t.Fatalf("running timer = %+v, want one started at now", entry)
+	}
+
+	c.clock = NewFixedClock(testNow.Add(45 * time.Minute))
+	run(c, out, "stop")
+	if entry, _ := store.GetRunningTimer(); entry != nil {
+		t.Errorf("timer still running after stop: %+v", entry)
+	}
+	assertContains(t, run(c, out, "timesheet", "--day"), "+work", "[1] Deploy", "45m")
+}
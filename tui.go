@@ -16,13 +16,31 @@ const (
 	tuiTodayView tuiState = iota
 	tuiInboxView
 	tuiCalendarView
+	tuiAgendaView
 	tuiCaptureView
 	tuiAddView
 	tuiEditView
+	tuiConflictsView
+	tuiFocusView
+	tuiOverdueView
+	tuiUpcomingView
+	tuiSomedayView
+	tuiFilterView
+	tuiBatchView
+)
+
+// batchAction is what the batch prompt does to the selected todos
+type batchAction int
+
+const (
+	batchTag batchAction = iota
+	batchSchedule
+	batchProject
 )
 
 type tuiModel struct {
-	db             *DB
+	db             Store
+	clock          Clock
 	todos          []Todo
 	cursor         int
 	state          tuiState
@@ -31,28 +49,97 @@ type tuiModel struct {
 	inputDesc      string
 	inputDue       string
 	inputScheduled string
+	inputEstimate  string
 	editingID      int
+	reminders      []Reminder // Reminders for the todo being edited
 	err            error
 	width          int
 	height         int
-	inputField     int // 0: title, 1: description, 2: due date, 3: scheduled time
+	inputField     int // 0: title, 1: description, 2: due date, 3: scheduled time, 4: estimate
 	calendar       *Calendar
+	agendaDays     int // Days shown in the agenda view
 	keys           keyMap
 	help           help.Model
+	syncInterval   time.Duration
+	sync           tuiSyncStatus
+	conflicts      []SyncConflict
+	timer          *TimeEntry // The running timer, if any
+	timerTitle     string     // Title of the todo being timed
+	focusOptions   FocusOptions
+	focus          *focusSession
+	focusOnly      bool // Quit when leaving focus, as launched by li focus
+	counts         smartCounts
+	savedFilters   []SavedFilter
+	filterIndex    int          // The saved filter shown in tuiFilterView
+	marked         map[int]bool // IDs of todos marked for a batch action
+	visual         bool         // Marking every todo between visualAnchor and the cursor
+	visualAnchor   int
+	batchAction    batchAction
+	batchTodos     []Todo // The todos the batch prompt acts on
+	batchErr       error
+}
+
+// smartCounts are the sizes of the smart views, shown on their tabs
+type smartCounts struct {
+	overdue  int
+	upcoming int
+	someday  int
+	filters  []int // Matches for each saved filter, -1 if it doesn't compile
+}
+
+// tuiSyncStatus tracks background replica syncing for the header indicator
+type tuiSyncStatus struct {
+	syncing    bool
+	lastSync   time.Time
+	lastResult SyncResult
+	err        error
+}
+
+// syncTickMsg is sent when the next periodic sync is due
+type syncTickMsg time.Time
+
+// timerTickMsg refreshes the running timer in the header. It carries the
+// entry's ID so that ticks for a stopped timer die out.
+type timerTickMsg int
+
+// focusTickMsg advances the focus countdown. Like timerTickMsg it carries
+// the loop it belongs to, so pausing and resuming don't double the ticks.
+type focusTickMsg int
+
+// syncResultMsg carries the outcome of a background sync
+type syncResultMsg struct {
+	result SyncResult
+	err    error
+	at     time.Time
 }
 
 type keyMap struct {
-	Today    key.Binding
-	Inbox    key.Binding
-	Calendar key.Binding
-	Capture  key.Binding
-	Up       key.Binding
-	Down     key.Binding
-	Toggle   key.Binding
-	New      key.Binding
-	Edit     key.Binding
-	Delete   key.Binding
-	Quit     key.Binding
+	Today      key.Binding
+	Inbox      key.Binding
+	Calendar   key.Binding
+	Agenda     key.Binding
+	Capture    key.Binding
+	Conflicts  key.Binding
+	Overdue    key.Binding
+	Upcoming   key.Binding
+	Someday    key.Binding
+	Filters    key.Binding
+	Up         key.Binding
+	Down       key.Binding
+	Toggle     key.Binding
+	New        key.Binding
+	Edit       key.Binding
+	Delete     key.Binding
+	Timer      key.Binding
+	Focus      key.Binding
+	Move       key.Binding
+	Unschedule key.Binding
+	Mark       key.Binding
+	Visual     key.Binding
+	Tag        key.Binding
+	Reschedule key.Binding
+	Project    key.Binding
+	Quit       key.Binding
 }
 
 var defaultKeyMap = keyMap{
@@ -68,10 +155,34 @@ var defaultKeyMap = keyMap{
 		key.WithKeys("c"),
 		key.WithHelp("c", "calendar"),
 	),
+	Agenda: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "agenda"),
+	),
 	Capture: key.NewBinding(
 		key.WithKeys("x"),
 		key.WithHelp("x", "capture"),
 	),
+	Conflicts: key.NewBinding(
+		key.WithKeys("!"),
+		key.WithHelp("!", "conflicts"),
+	),
+	Overdue: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "overdue"),
+	),
+	Upcoming: key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "upcoming"),
+	),
+	Someday: key.NewBinding(
+		key.WithKeys("z"),
+		key.WithHelp("z", "someday"),
+	),
+	Filters: key.NewBinding(
+		key.WithKeys("1", "2", "3", "4", "5", "6", "7", "8", "9"),
+		key.WithHelp("1-9", "saved filters"),
+	),
 	Up: key.NewBinding(
 		key.WithKeys("up", "k"),
 		key.WithHelp("↑/k", "up"),
@@ -81,8 +192,8 @@ var defaultKeyMap = keyMap{
 		key.WithHelp("↓/j", "down"),
 	),
 	Toggle: key.NewBinding(
-		key.WithKeys(" ", "enter"),
-		key.WithHelp("space/enter", "toggle"),
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "toggle"),
 	),
 	New: key.NewBinding(
 		key.WithKeys("n"),
@@ -96,6 +207,42 @@ var defaultKeyMap = keyMap{
 		key.WithKeys("d"),
 		key.WithHelp("d", "delete"),
 	),
+	Timer: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "start/stop timer"),
+	),
+	Focus: key.NewBinding(
+		key.WithKeys("f"),
+		key.WithHelp("f", "focus"),
+	),
+	Move: key.NewBinding(
+		key.WithKeys(">", "<"),
+		key.WithHelp(">/<", "move a day"),
+	),
+	Unschedule: key.NewBinding(
+		key.WithKeys("u"),
+		key.WithHelp("u", "unschedule"),
+	),
+	Mark: key.NewBinding(
+		key.WithKeys(" "),
+		key.WithHelp("space", "mark"),
+	),
+	Visual: key.NewBinding(
+		key.WithKeys("v", "V"),
+		key.WithHelp("v/V", "mark range/all"),
+	),
+	Tag: key.NewBinding(
+		key.WithKeys("#"),
+		key.WithHelp("#", "tag"),
+	),
+	Reschedule: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "reschedule"),
+	),
+	Project: key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "move to project"),
+	),
 	Quit: key.NewBinding(
 		key.WithKeys("q", "ctrl+c"),
 		key.WithHelp("q", "quit"),
@@ -104,48 +251,327 @@ var defaultKeyMap = keyMap{
 
 // ShortHelp returns keybindings to be shown in the mini help view
 func (k keyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Up, k.Down, k.Toggle, k.New, k.Today, k.Inbox, k.Calendar, k.Capture, k.Quit}
+	return []key.Binding{k.Up, k.Down, k.Toggle, k.Mark, k.New, k.Today, k.Inbox, k.Calendar, k.Agenda, k.Capture, k.Quit}
 }
 
 // FullHelp returns keybindings for the expanded help view
 func (k keyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Up, k.Down, k.Toggle, k.New, k.Edit, k.Delete},
-		{k.Today, k.Inbox, k.Calendar, k.Capture, k.Quit},
+		{k.Timer, k.Focus, k.Move, k.Unschedule},
+		{k.Mark, k.Visual, k.Tag, k.Reschedule, k.Project},
+		{k.Today, k.Inbox, k.Calendar, k.Agenda, k.Capture, k.Conflicts, k.Quit},
+		{k.Overdue, k.Upcoming, k.Someday, k.Filters},
 	}
 }
 
-func NewTuiModel(db *DB) tuiModel {
-	todos, err := db.GetTodayTodos()
+func NewTuiModel(db Store, clock Clock, options CalendarOptions, syncInterval time.Duration, focusOptions FocusOptions) tuiModel {
+	todos, err := db.GetDateTodos(clock.Now())
 	if err != nil {
-		return tuiModel{db: db, err: err}
+		return tuiModel{db: db, clock: clock, err: err}
 	}
 
-	return tuiModel{
-		db:       db,
-		todos:    todos,
-		state:    tuiTodayView,
-		calendar: NewCalendar(db, time.Now(), MonthView),
-		keys:     defaultKeyMap,
-		help:     help.New(),
+	conflicts, _ := db.GetConflicts()
+
+	m := tuiModel{
+		db:           db,
+		clock:        clock,
+		todos:        todos,
+		conflicts:    conflicts,
+		state:        tuiTodayView,
+		calendar:     NewCalendar(db, clock, options, clock.Now(), MonthView),
+		agendaDays:   defaultAgendaDays,
+		keys:         defaultKeyMap,
+		help:         help.New(),
+		syncInterval: syncInterval,
+		sync:         tuiSyncStatus{syncing: db.SyncEnabled()},
+		focusOptions: focusOptions,
 	}
+	m.loadTimer()
+	m.loadCounts()
+	return m
+}
+
+// loadCounts recounts the smart views and saved filters for their tabs
+func (m *tuiModel) loadCounts() {
+	now := m.clock.Now()
+	overdue, _ := m.db.GetOverdueTodos(now)
+	upcoming, _ := m.db.GetUpcomingTodos(now, startOfDay(now).AddDate(0, 0, upcomingDays))
+	someday, _ := m.db.GetSomedayTodos()
+	m.counts = smartCounts{overdue: len(overdue), upcoming: len(upcoming), someday: len(someday)}
+
+	// Filters can be saved from the CLI while the TUI is open
+	m.savedFilters, _ = m.db.GetSavedFilters()
+	for _, saved := range m.savedFilters {
+		count := -1
+		if todos, err := m.filterTodos(saved); err == nil {
+			count = len(todos)
+		}
+		m.counts.filters = append(m.counts.filters, count)
+	}
+}
+
+// filterTodos runs a saved filter
+func (m *tuiModel) filterTodos(saved SavedFilter) ([]Todo, error) {
+	filter, err := CompileFilter(saved.Query, m.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	return m.db.FilterTodos(filter)
 }
 
 func (m tuiModel) Init() tea.Cmd {
+	var cmds []tea.Cmd
+	if m.db.SyncEnabled() {
+		cmds = append(cmds, m.syncCmd())
+	}
+	if m.timer != nil {
+		cmds = append(cmds, timerTick(m.timer.ID))
+	}
+	if m.focus != nil {
+		cmds = append(cmds, focusTick(m.focus.ticks))
+	}
+	return tea.Batch(cmds...)
+}
+
+// loadTimer reloads the running timer and the title of its todo
+func (m *tuiModel) loadTimer() {
+	m.timer, _ = m.db.GetRunningTimer()
+	m.timerTitle = ""
+	if m.timer != nil {
+		if todo, err := m.db.GetTodo(m.timer.TodoID); err == nil {
+			m.timerTitle = todo.Title
+		}
+	}
+}
+
+// timerTick waits a second before refreshing the running timer
+func timerTick(entryID int) tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return timerTickMsg(entryID)
+	})
+}
+
+// toggleTimer stops the timer if it is running on todo, and otherwise
+// starts timing todo in place of whatever was running
+func (m *tuiModel) toggleTimer(todo Todo) tea.Cmd {
+	now := m.clock.Now()
+	if m.timer != nil && m.timer.TodoID == todo.ID {
+		m.db.StopTimer(now)
+		m.loadTimer()
+		return nil
+	}
+
+	m.db.StartTimer(todo.ID, now)
+	m.loadTimer()
+	if m.timer == nil {
+		return nil
+	}
+	return timerTick(m.timer.ID)
+}
+
+// focusTick waits a second before refreshing the focus countdown
+func focusTick(loop int) tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return focusTickMsg(loop)
+	})
+}
+
+// startFocus opens the focus screen on todo and starts its first
+// pomodoro, tracking time on the todo while it runs
+func (m *tuiModel) startFocus(todo Todo) tea.Cmd {
+	now := m.clock.Now()
+	total, _ := m.db.CountPomodoros(todo.ID)
+	m.focus = newFocusSession(todo, m.focusOptions, total, now)
+	m.previousState = m.state
+	m.state = tuiFocusView
+
+	m.db.StartTimer(todo.ID, now)
+	m.loadTimer()
+	return focusTick(m.focus.ticks)
+}
+
+// resumeFocus starts the next phase, or carries on after a pause
+func (m *tuiModel) resumeFocus() tea.Cmd {
+	now := m.clock.Now()
+	m.focus.resume(now)
+	if m.focus.phase == focusWork {
+		m.db.StartTimer(m.focus.todo.ID, now)
+		m.loadTimer()
+	}
+	m.focus.ticks++
+	return focusTick(m.focus.ticks)
+}
+
+// pauseFocus holds the countdown, stopping the timer during a pomodoro
+func (m *tuiModel) pauseFocus() {
+	now := m.clock.Now()
+	m.focus.pause(now)
+	if m.focus.phase == focusWork {
+		m.stopFocusTimer(now)
+	}
+}
+
+// finishFocusPhase ends the running phase. A pomodoro that ran its full
+// length is logged against the todo.
+func (m *tuiModel) finishFocusPhase(at time.Time, completed bool) {
+	if m.focus.phase == focusWork {
+		m.stopFocusTimer(at)
+		if completed {
+			m.db.LogPomodoro(m.focus.todo.ID, m.focus.workStart, at)
+			m.focus.completed++
+			m.focus.total++
+		}
+	}
+	m.focus.advance()
+}
+
+// stopFocusTimer stops the timer if it is still tracking the focused todo
+func (m *tuiModel) stopFocusTimer(at time.Time) {
+	if m.timer != nil && m.timer.TodoID == m.focus.todo.ID {
+		m.db.StopTimer(at)
+	}
+	m.loadTimer()
+}
+
+// leaveFocus closes the focus screen, stopping its timer
+func (m *tuiModel) leaveFocus() tea.Cmd {
+	if m.focus.running && m.focus.phase == focusWork {
+		m.stopFocusTimer(m.clock.Now())
+	}
+	m.focus = nil
+	if m.focusOnly {
+		return tea.Quit
+	}
+	m.returnToPreviousState()
 	return nil
 }
 
+// syncCmd syncs the replica in the background
+func (m tuiModel) syncCmd() tea.Cmd {
+	db, clock := m.db, m.clock
+	return func() tea.Msg {
+		result, err := db.Sync()
+		return syncResultMsg{result: result, err: err, at: clock.Now()}
+	}
+}
+
+// scheduleSync waits for the sync interval before triggering the next sync
+func (m tuiModel) scheduleSync() tea.Cmd {
+	if m.syncInterval <= 0 {
+		return nil
+	}
+	return tea.Tick(m.syncInterval, func(t time.Time) tea.Msg {
+		return syncTickMsg(t)
+	})
+}
+
+// refreshTodos reloads the todo list for the current view
+func (m *tuiModel) refreshTodos() {
+	switch m.state {
+	case tuiTodayView:
+		todos, _ := m.db.GetDateTodos(m.clock.Now())
+		m.todos = todos
+	case tuiInboxView:
+		todos, _ := m.db.GetInboxTodos()
+		m.todos = todos
+	case tuiOverdueView:
+		todos, _ := m.db.GetOverdueTodos(m.clock.Now())
+		m.todos = todos
+	case tuiUpcomingView:
+		now := m.clock.Now()
+		todos, _ := m.db.GetUpcomingTodos(now, startOfDay(now).AddDate(0, 0, upcomingDays))
+		m.todos = todos
+	case tuiSomedayView:
+		todos, _ := m.db.GetSomedayTodos()
+		m.todos = todos
+	case tuiFilterView:
+		m.todos = nil
+		if m.filterIndex < len(m.savedFilters) {
+			m.todos, _ = m.filterTodos(m.savedFilters[m.filterIndex])
+		}
+	default:
+		return
+	}
+
+	if m.cursor >= len(m.todos) {
+		m.cursor = len(m.todos) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+}
+
 func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := m.update(msg)
+
+	// Anything a key or a sync changes can move todos between smart views
+	switch msg.(type) {
+	case tea.KeyMsg, syncResultMsg:
+		if updated, ok := next.(tuiModel); ok {
+			updated.loadCounts()
+			next = updated
+		}
+	}
+
+	// Marks belong to the list they were made in
+	if updated, ok := next.(tuiModel); ok && updated.state != m.state && updated.state != tuiBatchView && m.state != tuiBatchView {
+		updated.clearSelection()
+		next = updated
+	}
+	return next, cmd
+}
+
+func (m tuiModel) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+	case syncTickMsg:
+		m.sync.syncing = true
+		return m, m.syncCmd()
+	case timerTickMsg:
+		if m.timer == nil || m.timer.ID != int(msg) {
+			return m, nil
+		}
+		return m, timerTick(m.timer.ID)
+	case focusTickMsg:
+		if m.focus == nil || !m.focus.running || m.focus.ticks != int(msg) {
+			return m, nil
+		}
+		if m.focus.left(m.clock.Now()) > 0 {
+			return m, focusTick(m.focus.ticks)
+		}
+		m.finishFocusPhase(m.focus.phaseEnd(), true)
+		return m, nil
+	case syncResultMsg:
+		m.sync.syncing = false
+		m.sync.err = msg.err
+		if msg.err == nil {
+			m.sync.lastSync = msg.at
+			m.sync.lastResult = msg.result
+			if msg.result.FramesSynced > 0 || msg.result.OpsPulled > 0 {
+				m.refreshTodos()
+			}
+			m.conflicts, _ = m.db.GetConflicts()
+		}
+		return m, m.scheduleSync()
 	case tea.KeyMsg:
+		// Focus mode has its own keys, so that a stray key can't leave it
+		if m.state == tuiFocusView {
+			return m.updateFocus(msg)
+		}
+
+		// The batch prompt takes any typed text
+		if m.state == tuiBatchView {
+			return m.updateBatch(msg)
+		}
+
 		// Handle global navigation keys first
 		switch {
 		case key.Matches(msg, m.keys.Today) && m.state != tuiAddView && m.state != tuiEditView && m.state != tuiCaptureView:
 			m.state = tuiTodayView
-			todos, _ := m.db.GetTodayTodos()
+			todos, _ := m.db.GetDateTodos(m.clock.Now())
 			m.todos = todos
 			m.cursor = 0
 			return m, nil
@@ -158,6 +584,39 @@ func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, m.keys.Calendar) && m.state != tuiAddView && m.state != tuiEditView && m.state != tuiCaptureView:
 			m.state = tuiCalendarView
 			return m, nil
+		case key.Matches(msg, m.keys.Agenda) && m.state != tuiAddView && m.state != tuiEditView && m.state != tuiCaptureView:
+			m.state = tuiAgendaView
+			return m, nil
+		case key.Matches(msg, m.keys.Conflicts) && m.state != tuiAddView && m.state != tuiEditView && m.state != tuiCaptureView:
+			m.state = tuiConflictsView
+			m.conflicts, _ = m.db.GetConflicts()
+			m.cursor = 0
+			return m, nil
+		case key.Matches(msg, m.keys.Overdue) && m.state != tuiAddView && m.state != tuiEditView && m.state != tuiCaptureView:
+			m.state = tuiOverdueView
+			m.cursor = 0
+			m.refreshTodos()
+			return m, nil
+		case key.Matches(msg, m.keys.Upcoming) && m.state != tuiAddView && m.state != tuiEditView && m.state != tuiCaptureView:
+			m.state = tuiUpcomingView
+			m.cursor = 0
+			m.refreshTodos()
+			return m, nil
+		case key.Matches(msg, m.keys.Someday) && m.state != tuiAddView && m.state != tuiEditView && m.state != tuiCaptureView:
+			m.state = tuiSomedayView
+			m.cursor = 0
+			m.refreshTodos()
+			return m, nil
+		case key.Matches(msg, m.keys.Filters) && m.state != tuiAddView && m.state != tuiEditView && m.state != tuiCaptureView:
+			index := int(msg.String()[0] - '1')
+			if index >= len(m.savedFilters) {
+				return m, nil
+			}
+			m.state = tuiFilterView
+			m.filterIndex = index
+			m.cursor = 0
+			m.refreshTodos()
+			return m, nil
 		case key.Matches(msg, m.keys.Capture) && m.state != tuiAddView && m.state != tuiEditView:
 			m.state = tuiCaptureView
 			m.input = ""
@@ -168,24 +627,33 @@ func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Handle state-specific keys
 		switch m.state {
-		case tuiInboxView:
-			return m.updateInbox(msg)
+		case tuiInboxView, tuiOverdueView, tuiUpcomingView, tuiSomedayView, tuiFilterView:
+			return m.updateList(msg)
 		case tuiTodayView:
 			return m.updateToday(msg)
 		case tuiCalendarView:
 			return m.updateCalendar(msg)
+		case tuiAgendaView:
+			return m.updateAgenda(msg)
 		case tuiCaptureView:
 			return m.updateCapture(msg)
 		case tuiAddView:
 			return m.updateAdd(msg)
 		case tuiEditView:
 			return m.updateEdit(msg)
+		case tuiConflictsView:
+			return m.updateConflicts(msg)
 		}
 	}
 	return m, nil
 }
 
-func (m tuiModel) updateInbox(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+// updateList handles keys for the inbox and the smart views
+func (m tuiModel) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.updateSelection(msg) {
+		return m, nil
+	}
+
 	switch msg.String() {
 	case "up", "k":
 		if m.cursor > 0 {
@@ -202,23 +670,29 @@ func (m tuiModel) updateInbox(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.inputDesc = ""
 		m.inputDue = ""
 		m.inputScheduled = ""
+		m.inputEstimate = ""
 		m.inputField = 0
-	case "enter", " ":
+	case "enter":
 		if len(m.todos) > 0 {
 			todo := m.todos[m.cursor]
 			m.db.ToggleTodo(todo.ID)
-			todos, _ := m.db.GetInboxTodos()
-			m.todos = todos
+			m.refreshTodos()
 		}
 	case "d":
 		if len(m.todos) > 0 {
 			todo := m.todos[m.cursor]
 			m.db.DeleteTodo(todo.ID)
-			todos, _ := m.db.GetInboxTodos()
-			m.todos = todos
-			if m.cursor >= len(m.todos) && len(m.todos) > 0 {
-				m.cursor = len(m.todos) - 1
-			}
+			m.refreshTodos()
+			// Deleting a todo also deletes its time entries
+			m.loadTimer()
+		}
+	case "s":
+		if len(m.todos) > 0 {
+			return m, m.toggleTimer(m.todos[m.cursor])
+		}
+	case "f":
+		if len(m.todos) > 0 {
+			return m, m.startFocus(m.todos[m.cursor])
 		}
 	case "e":
 		if len(m.todos) > 0 {
@@ -235,17 +709,14 @@ func (m tuiModel) updateInbox(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.inputDue = todo.DueDate.Format("2006-01-02")
 			}
 
-			m.inputScheduled = ""
-			if todo.ScheduledStart != nil {
-				if todo.ScheduledEnd != nil {
-					m.inputScheduled = fmt.Sprintf("%s-%s",
-						todo.ScheduledStart.Format("3:04pm"),
-						todo.ScheduledEnd.Format("3:04pm"))
-				} else {
-					m.inputScheduled = todo.ScheduledStart.Format("3:04pm")
-				}
+			m.inputScheduled = scheduleInput(todo)
+
+			m.inputEstimate = ""
+			if todo.Estimate > 0 {
+				m.inputEstimate = formatDuration(todo.Estimate)
 			}
 
+			m.reminders, _ = m.db.GetTodoReminders(todo.ID)
 			m.inputField = 0
 		}
 	}
@@ -253,6 +724,10 @@ func (m tuiModel) updateInbox(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m tuiModel) updateToday(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.updateSelection(msg) {
+		return m, nil
+	}
+
 	switch msg.String() {
 	case "up", "k":
 		if m.cursor > 0 {
@@ -269,23 +744,52 @@ func (m tuiModel) updateToday(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.inputDesc = ""
 		m.inputDue = ""
 		m.inputScheduled = ""
+		m.inputEstimate = ""
 		m.inputField = 0
-	case "enter", " ":
+	case "enter":
 		if len(m.todos) > 0 {
 			todo := m.todos[m.cursor]
 			m.db.ToggleTodo(todo.ID)
-			todos, _ := m.db.GetTodayTodos()
+			todos, _ := m.db.GetDateTodos(m.clock.Now())
 			m.todos = todos
 		}
 	case "d":
 		if len(m.todos) > 0 {
 			todo := m.todos[m.cursor]
 			m.db.DeleteTodo(todo.ID)
-			todos, _ := m.db.GetTodayTodos()
+			todos, _ := m.db.GetDateTodos(m.clock.Now())
 			m.todos = todos
 			if m.cursor >= len(m.todos) && len(m.todos) > 0 {
 				m.cursor = len(m.todos) - 1
 			}
+			// Deleting a todo also deletes its time entries
+			m.loadTimer()
+		}
+	case "s":
+		if len(m.todos) > 0 {
+			return m, m.toggleTimer(m.todos[m.cursor])
+		}
+	case "f":
+		if len(m.todos) > 0 {
+			return m, m.startFocus(m.todos[m.cursor])
+		}
+	case ">", "<":
+		if len(m.todos) > 0 {
+			todo := m.todos[m.cursor]
+			shift := "+1d"
+			if msg.String() == "<" {
+				shift = "-1d"
+			}
+			// Todos without a block have nothing to move
+			if block, err := MoveBlock(todo, shift, m.clock); err == nil {
+				m.db.ScheduleTodo(todo.ID, block.Start, block.End, block.AllDay)
+				m.reloadToday()
+			}
+		}
+	case "u":
+		if len(m.todos) > 0 {
+			m.db.ScheduleTodo(m.todos[m.cursor].ID, nil, nil, false)
+			m.reloadToday()
 		}
 	case "e":
 		if len(m.todos) > 0 {
@@ -302,43 +806,336 @@ func (m tuiModel) updateToday(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.inputDue = todo.DueDate.Format("2006-01-02")
 			}
 
-			m.inputScheduled = ""
-			if todo.ScheduledStart != nil {
-				if todo.ScheduledEnd != nil {
-					m.inputScheduled = fmt.Sprintf("%s-%s",
-						todo.ScheduledStart.Format("3:04pm"),
-						todo.ScheduledEnd.Format("3:04pm"))
-				} else {
-					m.inputScheduled = todo.ScheduledStart.Format("3:04pm")
-				}
+			m.inputScheduled = scheduleInput(todo)
+
+			m.inputEstimate = ""
+			if todo.Estimate > 0 {
+				m.inputEstimate = formatDuration(todo.Estimate)
 			}
 
+			m.reminders, _ = m.db.GetTodoReminders(todo.ID)
 			m.inputField = 0
 		}
 	}
 	return m, nil
 }
 
+// updateSelection handles the multi-select keys of the todo lists, and
+// the batch actions on the marked todos. It reports whether it used the key.
+func (m *tuiModel) updateSelection(msg tea.KeyMsg) bool {
+	selected := m.selection()
+
+	switch msg.String() {
+	case " ":
+		if len(m.todos) == 0 {
+			return true
+		}
+		id := m.todos[m.cursor].ID
+		if m.marked[id] {
+			delete(m.marked, id)
+		} else {
+			m.mark(id)
+		}
+		// Step down so that a run can be marked by holding space
+		if m.cursor < len(m.todos)-1 {
+			m.cursor++
+		}
+	case "v":
+		if m.visual {
+			for _, todo := range selected {
+				m.mark(todo.ID)
+			}
+			m.visual = false
+		} else if len(m.todos) > 0 {
+			m.visual = true
+			m.visualAnchor = m.cursor
+		}
+	case "V":
+		allMarked := len(m.todos) > 0 && len(selected) == len(m.todos)
+		m.clearSelection()
+		if !allMarked {
+			for _, todo := range m.todos {
+				m.mark(todo.ID)
+			}
+		}
+	case "esc":
+		m.clearSelection()
+	case "enter":
+		if len(selected) == 0 {
+			return false
+		}
+		m.applyBatch(selected, BulkEdit{Toggle: true})
+	case "d":
+		if len(selected) == 0 {
+			return false
+		}
+		m.applyBatch(selected, BulkEdit{Delete: true})
+		// Deleting a todo also deletes its time entries
+		m.loadTimer()
+	case "#":
+		m.startBatch(batchTag)
+	case "r":
+		m.startBatch(batchSchedule)
+	case "m":
+		m.startBatch(batchProject)
+	default:
+		return false
+	}
+	return true
+}
+
+// mark adds a todo to the selection
+func (m *tuiModel) mark(id int) {
+	if m.marked == nil {
+		m.marked = make(map[int]bool)
+	}
+	m.marked[id] = true
+}
+
+// clearSelection unmarks everything and leaves visual mode
+func (m *tuiModel) clearSelection() {
+	m.marked = nil
+	m.visual = false
+}
+
+// selection returns the marked todos, including the visual range, in list
+// order
+func (m tuiModel) selection() []Todo {
+	var selected []Todo
+	for i, todo := range m.todos {
+		if m.isSelected(i) {
+			selected = append(selected, todo)
+		}
+	}
+	return selected
+}
+
+// isSelected reports whether the todo at row i is marked or in the visual
+// range
+func (m tuiModel) isSelected(i int) bool {
+	if m.marked[m.todos[i].ID] {
+		return true
+	}
+	from, to := m.visualAnchor, m.cursor
+	if from > to {
+		from, to = to, from
+	}
+	return m.visual && i >= from && i <= to
+}
+
+// rowCursor is the cursor and selection mark at the start of row i
+func (m tuiModel) rowCursor(i int) string {
+	cursor := " "
+	if m.cursor == i {
+		cursor = ">"
+	}
+	if m.isSelected(i) {
+		return cursor + "●"
+	}
+	return cursor + " "
+}
+
+// startBatch opens the prompt for a batch action on the selection, or on
+// the todo under the cursor when nothing is marked
+func (m *tuiModel) startBatch(action batchAction) {
+	todos := m.selection()
+	if len(todos) == 0 && len(m.todos) > 0 {
+		todos = []Todo{m.todos[m.cursor]}
+	}
+	if len(todos) == 0 {
+		return
+	}
+
+	m.batchAction = action
+	m.batchTodos = todos
+	m.batchErr = nil
+	m.previousState = m.state
+	m.state = tuiBatchView
+
+	// A single todo starts from what it has now
+	m.input = ""
+	if len(todos) == 1 {
+		switch action {
+		case batchSchedule:
+			m.input = scheduleInput(todos[0])
+		case batchProject:
+			m.input = todos[0].Project
+		}
+	}
+}
+
+// applyBatch makes edit to todos and clears the selection
+func (m *tuiModel) applyBatch(todos []Todo, edit BulkEdit) {
+	ids := make([]int, len(todos))
+	for i, todo := range todos {
+		ids[i] = todo.ID
+	}
+	m.db.ApplyBulkEdit(ids, edit)
+
+	m.clearSelection()
+	m.refreshTodos()
+}
+
+func (m tuiModel) updateBatch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "esc":
+		m.state = m.previousState
+	case "enter":
+		edit, err := m.batchEdit()
+		if err != nil {
+			m.batchErr = err
+			return m, nil
+		}
+		m.state = m.previousState
+		m.applyBatch(m.batchTodos, edit)
+	case "backspace":
+		if len(m.input) > 0 {
+			m.input = m.input[:len(m.input)-1]
+		}
+	default:
+		if len(msg.String()) == 1 { // Only add printable characters
+			m.input += msg.String()
+		}
+	}
+	return m, nil
+}
+
+// batchEdit turns the batch prompt into an edit
+func (m tuiModel) batchEdit() (BulkEdit, error) {
+	input := strings.TrimSpace(m.input)
+
+	switch m.batchAction {
+	case batchTag:
+		add, remove, err := parseTagChanges(strings.Fields(input))
+		if err != nil {
+			return BulkEdit{}, err
+		}
+		if len(add) == 0 && len(remove) == 0 {
+			return BulkEdit{}, fmt.Errorf("type +tag to add or -tag to remove")
+		}
+		return BulkEdit{AddTags: add, RemoveTags: remove}, nil
+	case batchSchedule:
+		if input == "" {
+			return BulkEdit{Block: &TimeBlock{}}, nil
+		}
+		block, err := ParseTimeBlockFor(input, m.clock, sharedEstimate(m.batchTodos))
+		if err != nil {
+			return BulkEdit{}, err
+		}
+		return BulkEdit{Block: block}, nil
+	}
+
+	project := strings.TrimPrefix(input, "+")
+	if project != "" && !smartProjectPattern.MatchString("+"+project) {
+		return BulkEdit{}, fmt.Errorf("invalid project name %q", project)
+	}
+	return BulkEdit{Project: &project}, nil
+}
+
+func (m tuiModel) updateFocus(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		if m.focus.waiting {
+			return m, m.resumeFocus()
+		}
+	case " ", "p":
+		switch {
+		case m.focus.running:
+			m.pauseFocus()
+		case !m.focus.waiting:
+			return m, m.resumeFocus()
+		}
+	case "n":
+		// Skipping a pomodoro doesn't count it
+		m.finishFocusPhase(m.clock.Now(), false)
+	case "d":
+		if !m.focus.todo.Done {
+			m.db.ToggleTodo(m.focus.todo.ID)
+		}
+		return m, m.leaveFocus()
+	case "esc", "q", "ctrl+c":
+		return m, m.leaveFocus()
+	}
+	return m, nil
+}
+
 func (m tuiModel) updateCalendar(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "left", "h":
-		// Navigate to previous month
-		m.calendar = NewCalendar(m.db, m.calendar.GetDate().AddDate(0, -1, 0), m.calendar.GetView())
+		// Navigate to the previous month, week or day
+		m.calendar.Previous()
 		m.calendar.LoadTodos()
 	case "right", "l":
-		// Navigate to next month
-		m.calendar = NewCalendar(m.db, m.calendar.GetDate().AddDate(0, 1, 0), m.calendar.GetView())
+		// Navigate to the next month, week or day
+		m.calendar.Next()
 		m.calendar.LoadTodos()
 	case "m":
-		m.calendar = NewCalendar(m.db, m.calendar.GetDate(), MonthView)
+		m.calendar.SetView(MonthView)
 		m.calendar.LoadTodos()
 	case "w":
-		m.calendar = NewCalendar(m.db, m.calendar.GetDate(), WeekView)
+		m.calendar.SetView(WeekView)
+		m.calendar.LoadTodos()
+	case "d":
+		m.calendar.SetView(DayView)
+		m.calendar.LoadTodos()
+	case "y":
+		m.calendar.SetView(YearView)
+		m.calendar.LoadTodos()
+	case "n":
+		m.calendar.SetWeekNumbers(!m.calendar.WeekNumbers())
+	case "g":
+		// Switch the week between a list and a time grid
+		m.calendar.SetGrid(!m.calendar.Grid())
+		m.calendar.SetView(WeekView)
 		m.calendar.LoadTodos()
 	}
 	return m, nil
 }
 
+func (m tuiModel) updateAgenda(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "+", "=", "right", "l":
+		m.agendaDays++
+	case "-", "left", "h":
+		if m.agendaDays > 1 {
+			m.agendaDays--
+		}
+	}
+	return m, nil
+}
+
+func (m tuiModel) updateConflicts(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.conflicts)-1 {
+			m.cursor++
+		}
+	case "l", "r":
+		if len(m.conflicts) > 0 {
+			keep := conflictLocal
+			if msg.String() == "r" {
+				keep = conflictRemote
+			}
+
+			conflict := m.conflicts[m.cursor]
+			if err := m.db.ResolveConflict(conflict.ID, keep); err != nil {
+				m.err = err
+				return m, nil
+			}
+
+			m.conflicts, _ = m.db.GetConflicts()
+			if m.cursor >= len(m.conflicts) && len(m.conflicts) > 0 {
+				m.cursor = len(m.conflicts) - 1
+			}
+		}
+	}
+	return m, nil
+}
+
 func (m tuiModel) updateCapture(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "ctrl+c", "esc":
@@ -349,16 +1146,13 @@ func (m tuiModel) updateCapture(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	case "enter":
 		if strings.TrimSpace(m.input) != "" {
-			// Parse the input - look for " -- " separator for description
-			parts := strings.SplitN(m.input, " -- ", 2)
-			title := strings.TrimSpace(parts[0])
-			desc := ""
-			if len(parts) > 1 {
-				desc = strings.TrimSpace(parts[1])
+			// Lines that don't parse stay in the input so they can be fixed
+			parsed, desc, err := m.parseCapture()
+			if err != nil || parsed.Title == "" {
+				return m, nil
 			}
 
-			// Add todo to inbox (no scheduling)
-			m.db.AddTodo(title, desc, nil, nil, nil)
+			m.db.AddTodo(parsed.Todo(desc))
 			m.input = "" // Clear for next todo
 		}
 	case "backspace":
@@ -373,19 +1167,60 @@ func (m tuiModel) updateCapture(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// parseCapture splits the capture line into a smart-add title and an
+// optional description after " -- "
+func (m tuiModel) parseCapture() (*SmartAdd, string, error) {
+	parts := strings.SplitN(m.input, " -- ", 2)
+	desc := ""
+	if len(parts) > 1 {
+		desc = strings.TrimSpace(parts[1])
+	}
+
+	parsed, err := ParseSmartAdd(parts[0], m.clock)
+	return parsed, desc, err
+}
+
+// reloadToday reloads today's todos after one has left the list
+func (m *tuiModel) reloadToday() {
+	m.todos, _ = m.db.GetDateTodos(m.clock.Now())
+	if m.cursor >= len(m.todos) && len(m.todos) > 0 {
+		m.cursor = len(m.todos) - 1
+	}
+}
+
 // returnToPreviousState returns to the state before entering add/edit mode
 func (m *tuiModel) returnToPreviousState() {
 	m.state = m.previousState
-	switch m.previousState {
-	case tuiTodayView:
-		todos, _ := m.db.GetTodayTodos()
-		m.todos = todos
-	case tuiInboxView:
-		todos, _ := m.db.GetInboxTodos()
-		m.todos = todos
-	case tuiCalendarView:
-		// Calendar doesn't need todo reloading since it manages its own data
+	// The calendar doesn't need reloading since it manages its own data
+	m.refreshTodos()
+}
+
+// scheduleInput writes a todo's time block the way it would be typed, so
+// saving the edit form unchanged keeps the same block
+func scheduleInput(todo Todo) string {
+	if todo.ScheduledStart == nil {
+		return ""
+	}
+
+	start := *todo.ScheduledStart
+	day := start.Format("2006-01-02")
+	switch {
+	case todo.AllDay && todo.ScheduledEnd != nil && todo.ScheduledEnd.Sub(start) > 24*time.Hour:
+		return fmt.Sprintf("%s to %s", day, todo.ScheduledEnd.AddDate(0, 0, -1).Format("2006-01-02"))
+	case todo.AllDay:
+		return day + " all day"
+	case todo.ScheduledEnd == nil:
+		return fmt.Sprintf("%s %s", day, start.Format("3:04pm"))
+	case todo.ScheduledEnd.Sub(start) < 24*time.Hour:
+		return fmt.Sprintf("%s %s-%s", day, start.Format("3:04pm"), todo.ScheduledEnd.Format("3:04pm"))
+	}
+
+	length := todo.ScheduledEnd.Sub(start)
+	duration := fmt.Sprintf("%dh", int(length.Hours()))
+	if minutes := int(length.Minutes()) % 60; minutes != 0 {
+		duration += fmt.Sprintf("%dm", minutes)
 	}
+	return fmt.Sprintf("%s %s for %s", day, start.Format("3:04pm"), duration)
 }
 
 func (m tuiModel) updateAdd(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
@@ -399,26 +1234,43 @@ func (m tuiModel) updateAdd(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if strings.TrimSpace(m.input) != "" {
 			// Parse due date and scheduled time
 			var dueDate, scheduledStart, scheduledEnd *time.Time
+			allDay := false
+
+			var estimate time.Duration
+			if m.inputEstimate != "" {
+				if parsed, err := ParseDuration(m.inputEstimate); err == nil {
+					estimate = parsed
+				}
+			}
 
 			if m.inputDue != "" {
-				if parsed, err := ParseDueDate(m.inputDue); err == nil {
+				if parsed, err := ParseDueDate(m.inputDue, m.clock); err == nil {
 					dueDate = parsed
 				}
 			}
 
 			if m.inputScheduled != "" {
-				if timeBlock, err := ParseTimeBlock(m.inputScheduled); err == nil && timeBlock != nil {
+				if timeBlock, err := ParseTimeBlockFor(m.inputScheduled, m.clock, estimate); err == nil && timeBlock != nil {
 					scheduledStart = timeBlock.Start
 					scheduledEnd = timeBlock.End
+					allDay = timeBlock.AllDay
 				}
 			}
 
-			m.db.AddTodo(m.input, m.inputDesc, dueDate, scheduledStart, scheduledEnd)
+			m.db.AddTodo(Todo{
+				Title:          m.input,
+				Description:    m.inputDesc,
+				DueDate:        dueDate,
+				ScheduledStart: scheduledStart,
+				ScheduledEnd:   scheduledEnd,
+				AllDay:         allDay,
+				Estimate:       estimate,
+			})
 			// Return to previous view after adding
 			m.returnToPreviousState()
 		}
 	case "tab":
-		m.inputField = (m.inputField + 1) % 4
+		m.inputField = (m.inputField + 1) % 5
 	case "backspace":
 		switch m.inputField {
 		case 0:
@@ -437,6 +1289,10 @@ func (m tuiModel) updateAdd(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if len(m.inputScheduled) > 0 {
 				m.inputScheduled = m.inputScheduled[:len(m.inputScheduled)-1]
 			}
+		case 4:
+			if len(m.inputEstimate) > 0 {
+				m.inputEstimate = m.inputEstimate[:len(m.inputEstimate)-1]
+			}
 		}
 	default:
 		if len(msg.String()) == 1 { // Only add printable characters
@@ -449,6 +1305,8 @@ func (m tuiModel) updateAdd(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.inputDue += msg.String()
 			case 3:
 				m.inputScheduled += msg.String()
+			case 4:
+				m.inputEstimate += msg.String()
 			}
 		}
 	}
@@ -466,26 +1324,35 @@ func (m tuiModel) updateEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if strings.TrimSpace(m.input) != "" {
 			// Parse due date and scheduled time
 			var dueDate, scheduledStart, scheduledEnd *time.Time
+			allDay := false
+
+			var estimate time.Duration
+			if m.inputEstimate != "" {
+				if parsed, err := ParseDuration(m.inputEstimate); err == nil {
+					estimate = parsed
+				}
+			}
 
 			if m.inputDue != "" {
-				if parsed, err := ParseDueDate(m.inputDue); err == nil {
+				if parsed, err := ParseDueDate(m.inputDue, m.clock); err == nil {
 					dueDate = parsed
 				}
 			}
 
 			if m.inputScheduled != "" {
-				if timeBlock, err := ParseTimeBlock(m.inputScheduled); err == nil && timeBlock != nil {
+				if timeBlock, err := ParseTimeBlockFor(m.inputScheduled, m.clock, estimate); err == nil && timeBlock != nil {
 					scheduledStart = timeBlock.Start
 					scheduledEnd = timeBlock.End
+					allDay = timeBlock.AllDay
 				}
 			}
 
-			m.db.UpdateTodo(m.editingID, m.input, m.inputDesc, dueDate, scheduledStart, scheduledEnd)
+			m.db.UpdateTodo(m.editingID, m.input, m.inputDesc, dueDate, scheduledStart, scheduledEnd, allDay, estimate)
 			// Return to previous view after editing
 			m.returnToPreviousState()
 		}
 	case "tab":
-		m.inputField = (m.inputField + 1) % 4
+		m.inputField = (m.inputField + 1) % 5
 	case "backspace":
 		switch m.inputField {
 		case 0:
@@ -504,6 +1371,10 @@ func (m tuiModel) updateEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if len(m.inputScheduled) > 0 {
 				m.inputScheduled = m.inputScheduled[:len(m.inputScheduled)-1]
 			}
+		case 4:
+			if len(m.inputEstimate) > 0 {
+				m.inputEstimate = m.inputEstimate[:len(m.inputEstimate)-1]
+			}
 		}
 	default:
 		if len(msg.String()) == 1 { // Only add printable characters
@@ -516,6 +1387,8 @@ func (m tuiModel) updateEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.inputDue += msg.String()
 			case 3:
 				m.inputScheduled += msg.String()
+			case 4:
+				m.inputEstimate += msg.String()
 			}
 		}
 	}
@@ -535,12 +1408,28 @@ func (m tuiModel) View() string {
 		content = m.viewToday()
 	case tuiCalendarView:
 		content = m.viewCalendar()
+	case tuiAgendaView:
+		content = m.viewAgenda()
+	case tuiConflictsView:
+		content = m.viewConflicts()
+	case tuiOverdueView:
+		content = m.viewSmart("Nothing has slipped. ✅")
+	case tuiUpcomingView:
+		content = m.viewSmart(fmt.Sprintf("Nothing coming up in the next %d days.", upcomingDays))
+	case tuiSomedayView:
+		content = m.viewSmart("Every open todo has a date or a block.")
+	case tuiFilterView:
+		content = m.viewSmart(m.filterEmptyMessage())
 	case tuiCaptureView:
 		return m.viewCapture() // This view has its own help
+	case tuiBatchView:
+		return m.viewBatch() // This view has its own help
 	case tuiAddView:
 		return m.viewAdd() // These views have their own help
 	case tuiEditView:
 		return m.viewEdit() // These views have their own help
+	case tuiFocusView:
+		return m.focus.render(m.clock.Now())
 	}
 
 	// Add help at bottom for main views
@@ -555,6 +1444,7 @@ func (m tuiModel) renderTabHeader() string {
 	todayTab := "📅 Today"
 	inboxTab := "📥 Inbox"
 	calendarTab := "🗓️ Calendar"
+	agendaTab := "📋 Agenda"
 	captureTab := "🎯 Capture"
 
 	// Highlight active tab
@@ -565,16 +1455,104 @@ func (m tuiModel) renderTabHeader() string {
 		inboxTab = tuiSelectedStyle.Render(inboxTab)
 	case tuiCalendarView:
 		calendarTab = tuiSelectedStyle.Render(calendarTab)
+	case tuiAgendaView:
+		agendaTab = tuiSelectedStyle.Render(agendaTab)
 	case tuiCaptureView:
 		captureTab = tuiSelectedStyle.Render(captureTab)
 	}
 
-	tabs = append(tabs, todayTab, inboxTab, calendarTab, captureTab)
+	tabs = append(tabs, todayTab, inboxTab, calendarTab, agendaTab, captureTab)
+
+	smartTabs := []struct {
+		state tuiState
+		label string
+		count int
+	}{
+		{tuiOverdueView, "⏰ Overdue", m.counts.overdue},
+		{tuiUpcomingView, "🔭 Upcoming", m.counts.upcoming},
+		{tuiSomedayView, "💤 Someday", m.counts.someday},
+	}
+	for _, smart := range smartTabs {
+		tab := fmt.Sprintf("%s (%d)", smart.label, smart.count)
+		switch {
+		case m.state == smart.state:
+			tab = tuiSelectedStyle.Render(tab)
+		case smart.state == tuiOverdueView && smart.count > 0:
+			tab = errorStyle.Render(tab)
+		}
+		tabs = append(tabs, tab)
+	}
+
+	// Saved filters follow as custom tabs, numbered for their keys
+	for i, saved := range m.savedFilters {
+		if i >= 9 {
+			break
+		}
+		tab := fmt.Sprintf("%d 🔎 %s (%d)", i+1, saved.Name, m.counts.filters[i])
+		if m.counts.filters[i] < 0 {
+			tab = fmt.Sprintf("%d 🔎 %s (!)", i+1, saved.Name)
+		}
+		if m.state == tuiFilterView && m.filterIndex == i {
+			tab = tuiSelectedStyle.Render(tab)
+		}
+		tabs = append(tabs, tab)
+	}
+
+	// Only surface the conflicts tab when there is something to resolve
+	if len(m.conflicts) > 0 || m.state == tuiConflictsView {
+		conflictsTab := fmt.Sprintf("⚠️ Conflicts (%d)", len(m.conflicts))
+		if m.state == tuiConflictsView {
+			conflictsTab = tuiSelectedStyle.Render(conflictsTab)
+		}
+		tabs = append(tabs, conflictsTab)
+	}
 
 	header := strings.Join(tabs, " | ")
+	if syncStatus := m.renderSyncStatus(); syncStatus != "" {
+		header += "   " + syncStatus
+	}
+	if m.timer != nil {
+		header += "   " + successStyle.Render(fmt.Sprintf("⏱ %s %s", m.timerTitle, formatTimer(m.timer.Duration(m.clock.Now()))))
+	}
+	if selected := len(m.selection()); selected > 0 || m.visual {
+		status := fmt.Sprintf("● %d selected", selected)
+		if m.visual {
+			status += " (visual)"
+		}
+		header += "   " + tuiMarkedStyle.Render(status)
+	}
 	return tuiTitleStyle.Render("⚡ Lithium") + "\n" + header + "\n\n"
 }
 
+// renderSyncStatus describes the replica sync state for the tab header
+func (m tuiModel) renderSyncStatus() string {
+	if !m.db.SyncEnabled() {
+		return ""
+	}
+
+	switch {
+	case m.sync.syncing:
+		return tuiDoneStyle.Render("🔄 syncing...")
+	case m.sync.err != nil:
+		return errorStyle.Render("⚠️ sync failed")
+	case m.sync.lastSync.IsZero():
+		return ""
+	}
+
+	since := m.clock.Now().Sub(m.sync.lastSync)
+	var ago string
+	switch {
+	case since < time.Minute:
+		ago = "just now"
+	case since < time.Hour:
+		ago = fmt.Sprintf("%dm ago", int(since.Minutes()))
+	default:
+		ago = fmt.Sprintf("%dh ago", int(since.Hours()))
+	}
+
+	return tuiDoneStyle.Render(fmt.Sprintf("🔄 synced %s", ago))
+}
+
 func (m tuiModel) viewAdd() string {
 	var s strings.Builder
 
@@ -613,9 +1591,18 @@ func (m tuiModel) viewAdd() string {
 	}
 	s.WriteString(fmt.Sprintf("%s%s\n", schedLabel, schedValue))
 
+	// Estimate field with cursor indicator
+	estimateLabel := tuiLabelStyle.Render("Estimate: ")
+	estimateValue := tuiInputStyle.Render(m.inputEstimate)
+	if m.inputField == 4 {
+		estimateValue += tuiInputStyle.Render("█") // Cursor
+	}
+	s.WriteString(fmt.Sprintf("%s%s\n", estimateLabel, estimateValue))
+
 	s.WriteString(tuiHelpStyle.Render("\nTab: switch fields, Enter: save, Esc: cancel"))
 	s.WriteString(tuiHelpStyle.Render("\nDue Date: today, tomorrow, 2024-12-25"))
 	s.WriteString(tuiHelpStyle.Render("\nScheduled: today 2pm-4pm, Monday 9am for 2 hours"))
+	s.WriteString(tuiHelpStyle.Render("\nEstimate: 45m, 2h, 1h30m"))
 
 	return tuiContainerStyle.Render(s.String())
 }
@@ -658,9 +1645,28 @@ func (m tuiModel) viewEdit() string {
 	}
 	s.WriteString(fmt.Sprintf("%s%s\n", schedLabel, schedValue))
 
+	// Estimate field with cursor indicator
+	estimateLabel := tuiLabelStyle.Render("Estimate: ")
+	estimateValue := tuiInputStyle.Render(m.inputEstimate)
+	if m.inputField == 4 {
+		estimateValue += tuiInputStyle.Render("█") // Cursor
+	}
+	s.WriteString(fmt.Sprintf("%s%s\n", estimateLabel, estimateValue))
+
+	// Reminders are managed with `li remind`, so they are shown read-only
+	if len(m.reminders) > 0 {
+		s.WriteString("\n")
+		s.WriteString(tuiLabelStyle.Render("Reminders:"))
+		s.WriteString("\n")
+		for _, reminder := range m.reminders {
+			s.WriteString(fmt.Sprintf("  ⏰ %s\n", FormatReminder(reminder)))
+		}
+	}
+
 	s.WriteString(tuiHelpStyle.Render("\nTab: switch fields, Enter: save, Esc: cancel"))
 	s.WriteString(tuiHelpStyle.Render("\nDue Date: today, tomorrow, 2024-12-25"))
 	s.WriteString(tuiHelpStyle.Render("\nScheduled: today 2pm-4pm, Monday 9am for 2 hours"))
+	s.WriteString(tuiHelpStyle.Render("\nEstimate: 45m, 2h, 1h30m"))
 
 	return tuiContainerStyle.Render(s.String())
 }
@@ -674,10 +1680,7 @@ func (m tuiModel) viewInbox() string {
 		s.WriteString("No unscheduled todos. All todos are scheduled!\n")
 	} else {
 		for i, todo := range m.todos {
-			cursor := " "
-			if m.cursor == i {
-				cursor = ">"
-			}
+			cursor := m.rowCursor(i)
 
 			status := "[ ]"
 			title := todo.Title
@@ -687,6 +1690,9 @@ func (m tuiModel) viewInbox() string {
 			}
 
 			line := fmt.Sprintf("%s %s %s", cursor, status, title)
+			if m.timer != nil && m.timer.TodoID == todo.ID {
+				line += " ⏱"
+			}
 			if todo.Description != "" {
 				desc := todo.Description
 				if todo.Done {
@@ -699,6 +1705,8 @@ func (m tuiModel) viewInbox() string {
 
 			if m.cursor == i {
 				line = tuiSelectedStyle.Render(line)
+			} else if m.isSelected(i) {
+				line = tuiMarkedStyle.Render(line)
 			}
 
 			s.WriteString(line)
@@ -709,6 +1717,74 @@ func (m tuiModel) viewInbox() string {
 	return tuiContainerStyle.Render(s.String())
 }
 
+// viewSmart lists the todos of a smart view along with when each is due
+// or scheduled
+func (m tuiModel) viewSmart(emptyMessage string) string {
+	var s strings.Builder
+
+	s.WriteString(m.renderTabHeader())
+
+	if len(m.todos) == 0 {
+		s.WriteString(emptyMessage + "\n")
+		return tuiContainerStyle.Render(s.String())
+	}
+
+	now := m.clock.Now()
+	for i, todo := range m.todos {
+		cursor := m.rowCursor(i)
+
+		// Saved filters can list finished todos too
+		checkbox := "[ ]"
+		if todo.Done {
+			checkbox = "[x]"
+		}
+		line := fmt.Sprintf("%s %s %s", cursor, checkbox, todo.Title)
+		if m.timer != nil && m.timer.TodoID == todo.ID {
+			line += " ⏱"
+		}
+		switch {
+		case m.cursor == i:
+			line = tuiSelectedStyle.Render(line)
+		case m.isSelected(i):
+			line = tuiMarkedStyle.Render(line)
+		case todo.Done:
+			line = tuiDoneStyle.Render(line)
+		}
+
+		var when []string
+		if todo.ScheduledStart != nil {
+			when = append(when, strings.TrimPrefix(FormatTimeBlock(todo.ScheduledStart, todo.ScheduledEnd, todo.AllDay), "Scheduled: "))
+		}
+		if todo.DueDate != nil {
+			when = append(when, "due "+FormatDueDate(todo.DueDate, m.clock))
+		}
+		whenStyle := descStyle
+		if isOverdue(todo, now) {
+			whenStyle = overdueStyle
+		}
+		if len(when) > 0 {
+			line += " " + whenStyle.Render(strings.Join(when, ", "))
+		}
+
+		s.WriteString(line)
+		s.WriteString("\n")
+	}
+
+	return tuiContainerStyle.Render(s.String())
+}
+
+// filterEmptyMessage explains an empty saved filter tab
+func (m tuiModel) filterEmptyMessage() string {
+	if m.filterIndex >= len(m.savedFilters) {
+		return "This filter has been deleted."
+	}
+	saved := m.savedFilters[m.filterIndex]
+	if _, err := CompileFilter(saved.Query, m.clock.Now()); err != nil {
+		return errorStyle.Render(fmt.Sprintf("Filter %s doesn't compile: %v", saved.Name, err))
+	}
+	return fmt.Sprintf("No todos match %q.", saved.Query)
+}
+
 func (m tuiModel) viewToday() string {
 	var s strings.Builder
 
@@ -717,11 +1793,9 @@ func (m tuiModel) viewToday() string {
 	if len(m.todos) == 0 {
 		s.WriteString("No todos scheduled for today.\n")
 	} else {
+		overlapping := overlappingIDs(m.todos)
 		for i, todo := range m.todos {
-			cursor := " "
-			if m.cursor == i {
-				cursor = ">"
-			}
+			cursor := m.rowCursor(i)
 
 			status := "[ ]"
 			title := todo.Title
@@ -731,14 +1805,23 @@ func (m tuiModel) viewToday() string {
 			}
 
 			line := fmt.Sprintf("%s %s %s", cursor, status, title)
+			if m.timer != nil && m.timer.TodoID == todo.ID {
+				line += " ⏱"
+			}
 
 			// Show time if scheduled
 			if todo.ScheduledStart != nil {
 				timeStr := todo.ScheduledStart.Format("15:04")
-				if todo.ScheduledEnd != nil {
+				if todo.AllDay {
+					timeStr = "all day"
+				} else if todo.ScheduledEnd != nil {
 					timeStr += "-" + todo.ScheduledEnd.Format("15:04")
 				}
-				line += fmt.Sprintf(" [%s]", timeStr)
+				if overlapping[todo.ID] {
+					line += overlapStyle.Render(fmt.Sprintf(" [%s overlaps]", timeStr))
+				} else {
+					line += fmt.Sprintf(" [%s]", timeStr)
+				}
 			}
 
 			if todo.Description != "" {
@@ -753,6 +1836,8 @@ func (m tuiModel) viewToday() string {
 
 			if m.cursor == i {
 				line = tuiSelectedStyle.Render(line)
+			} else if m.isSelected(i) {
+				line = tuiMarkedStyle.Render(line)
 			}
 
 			s.WriteString(line)
@@ -778,11 +1863,125 @@ func (m tuiModel) viewCalendar() string {
 		s.WriteString(calendarStr)
 	}
 
+	s.WriteString(tuiHelpStyle.Render("h/l: previous/next • y: year • m: month • w: week • d: day • g: week grid • n: week numbers"))
+
 	s.WriteString("\n")
 
 	return tuiContainerStyle.Render(s.String())
 }
 
+func (m tuiModel) viewAgenda() string {
+	var s strings.Builder
+
+	s.WriteString(m.renderTabHeader())
+
+	agenda, err := BuildAgenda(m.db, m.clock, m.agendaDays)
+	if err != nil {
+		s.WriteString("Error loading agenda")
+	} else {
+		s.WriteString(agenda.Render(m.clock))
+	}
+
+	s.WriteString(tuiHelpStyle.Render("+/-: more or fewer days"))
+
+	return tuiContainerStyle.Render(s.String())
+}
+
+func (m tuiModel) viewConflicts() string {
+	var s strings.Builder
+
+	s.WriteString(m.renderTabHeader())
+
+	if len(m.conflicts) == 0 {
+		s.WriteString("No sync conflicts. Everything merged cleanly!\n")
+		return tuiContainerStyle.Render(s.String())
+	}
+
+	for i, conflict := range m.conflicts {
+		cursor := " "
+		if m.cursor == i {
+			cursor = ">"
+		}
+
+		title := conflict.TodoTitle
+		if conflict.TodoID == 0 {
+			title = "(deleted todo)"
+		}
+
+		line := fmt.Sprintf("%s %s · %s", cursor, title, conflict.Field)
+		if m.cursor == i {
+			line = tuiSelectedStyle.Render(line)
+		}
+		s.WriteString(line)
+		s.WriteString("\n")
+
+		local := "    local:  " + FormatFieldValue(conflict.Field, conflict.LocalValue, m.clock.Now().Location())
+		remote := "    remote: " + FormatFieldValue(conflict.Field, conflict.RemoteValue, m.clock.Now().Location())
+		if conflict.Winner == conflictLocal {
+			local += " (kept)"
+		} else {
+			remote += " (kept)"
+		}
+		s.WriteString(descStyle.Render(local))
+		s.WriteString("\n")
+		s.WriteString(descStyle.Render(remote))
+		s.WriteString("\n")
+	}
+
+	s.WriteString(tuiHelpStyle.Render("l: keep local, r: keep remote"))
+
+	return tuiContainerStyle.Render(s.String())
+}
+
+// viewBatch prompts for the tags, block or project of a batch action
+func (m tuiModel) viewBatch() string {
+	var s strings.Builder
+
+	count := "1 todo"
+	if len(m.batchTodos) != 1 {
+		count = fmt.Sprintf("%d todos", len(m.batchTodos))
+	}
+
+	var title, hint string
+	switch m.batchAction {
+	case batchTag:
+		title = "🏷️ Tag " + count
+		hint = "+tag adds a tag, -tag removes one, e.g. +urgent -later"
+	case batchSchedule:
+		title = "📅 Reschedule " + count
+		hint = "A time block like 'tomorrow 2pm-3pm' or 'fri all day'; leave empty to unschedule"
+	case batchProject:
+		title = "📁 Move " + count + " to a project"
+		hint = "A project name; leave empty to remove the project"
+	}
+
+	s.WriteString(tuiTitleStyle.Render(title))
+	s.WriteString("\n\n")
+
+	for _, todo := range m.batchTodos {
+		s.WriteString(descStyle.Render("  • " + todo.Title))
+		s.WriteString("\n")
+	}
+	s.WriteString("\n")
+
+	inputLine := tuiInputStyle.Render(m.input)
+	inputLine += tuiInputStyle.Render("█") // Cursor
+	s.WriteString(fmt.Sprintf("> %s\n", inputLine))
+
+	if m.batchErr != nil {
+		s.WriteString("\n")
+		s.WriteString(errorStyle.Render(fmt.Sprintf("⚠️ %v", m.batchErr)))
+		s.WriteString("\n")
+	}
+
+	s.WriteString("\n")
+	s.WriteString(tuiHelpStyle.Render(hint))
+	s.WriteString("\n")
+	s.WriteString(tuiHelpStyle.Render("Enter: apply • Esc: cancel"))
+
+	return tuiContainerStyle.Render(s.String())
+}
+
 func (m tuiModel) viewCapture() string {
 	var s strings.Builder
 
@@ -797,6 +1996,11 @@ func (m tuiModel) viewCapture() string {
 	inputLine += tuiInputStyle.Render("█") // Cursor
 	s.WriteString(fmt.Sprintf("> %s\n", inputLine))
 
+	if strings.TrimSpace(m.input) != "" {
+		s.WriteString("\n")
+		s.WriteString(m.renderCapturePreview())
+	}
+
 	s.WriteString("\n")
 	s.WriteString(tuiHelpStyle.Render("Enter: save todo and start next"))
 	s.WriteString("\n")
@@ -804,13 +2008,71 @@ func (m tuiModel) viewCapture() string {
 	s.WriteString("\n")
 	s.WriteString(tuiHelpStyle.Render("Example: 'Buy milk -- get the organic kind'"))
 	s.WriteString("\n")
+	s.WriteString(tuiHelpStyle.Render("Smart add: #tag +project !high due:fri @ tomorrow 2pm-3pm"))
+	s.WriteString("\n")
 	s.WriteString(tuiHelpStyle.Render("Esc: return to inbox"))
 
 	return tuiContainerStyle.Render(s.String())
 }
 
-func RunTUI(db *DB) error {
-	p := tea.NewProgram(NewTuiModel(db), tea.WithAltScreen())
+// renderCapturePreview shows how the capture line will be saved
+func (m tuiModel) renderCapturePreview() string {
+	var s strings.Builder
+
+	parsed, desc, err := m.parseCapture()
+	if err != nil {
+		s.WriteString(errorStyle.Render(err.Error()))
+		s.WriteString("\n")
+		return s.String()
+	}
+
+	if parsed.Title == "" {
+		s.WriteString(errorStyle.Render("A title is required"))
+		s.WriteString("\n")
+		return s.String()
+	}
+
+	todo := parsed.Todo(desc)
+	s.WriteString(tuiLabelStyle.Render("Title: ") + todo.Title + "\n")
+	if todo.Description != "" {
+		s.WriteString(tuiLabelStyle.Render("Description: ") + todo.Description + "\n")
+	}
+	if todo.Project != "" {
+		s.WriteString(tuiLabelStyle.Render("Project: ") + todo.Project + "\n")
+	}
+	if len(todo.Tags) > 0 {
+		s.WriteString(tuiLabelStyle.Render("Tags: ") + strings.Join(todo.Tags, ", ") + "\n")
+	}
+	if todo.Priority != PriorityNone {
+		s.WriteString(tuiLabelStyle.Render("Priority: ") + stylePriority(todo.Priority) + "\n")
+	}
+	if todo.DueDate != nil {
+		s.WriteString(tuiLabelStyle.Render("Due: ") + activeLocale.ShortWeekday(todo.DueDate.Weekday(), 3) + " " + activeLocale.DateTime(*todo.DueDate) + "\n")
+	}
+	if todo.ScheduledStart != nil {
+		s.WriteString(tuiLabelStyle.Render("Scheduled: ") + strings.TrimPrefix(FormatTimeBlock(todo.ScheduledStart, todo.ScheduledEnd, todo.AllDay), "Scheduled: ") + "\n")
+	}
+
+	return s.String()
+}
+
+func RunTUI(db Store, clock Clock, options CalendarOptions, syncInterval time.Duration, focusOptions FocusOptions) error {
+	p := tea.NewProgram(NewTuiModel(db, clock, options, syncInterval, focusOptions), tea.WithAltScreen())
+	_, err := p.Run()
+	return err
+}
+
+// RunFocus opens the TUI straight into focus mode on todo, quitting when
+// focus mode is left
+func RunFocus(db Store, clock Clock, options CalendarOptions, syncInterval time.Duration, focusOptions FocusOptions, todo Todo) error {
+	m := NewTuiModel(db, clock, options, syncInterval, focusOptions)
+	if m.err != nil {
+		return m.err
+	}
+	m.focusOnly = true
+	m.startFocus(todo)
+
+	p := tea.NewProgram(m, tea.WithAltScreen())
 	_, err := p.Run()
 	return err
 }
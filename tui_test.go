@@ -0,0 +1,407 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newTestTui opens the TUI over store
+func newTestTui(store Store) tuiModel {
+	return NewTuiModel(store, NewFixedClock(testNow), DefaultCalendarOptions(), 0, DefaultFocusOptions())
+}
+
+// keyMsg builds the key message bubbletea sends for a key name
+func keyMsg(name string) tea.KeyMsg {
+	switch name {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case "backspace":
+		return tea.KeyMsg{Type: tea.KeyBackspace}
+	case "tab":
+		return tea.KeyMsg{Type: tea.KeyTab}
+	case " ":
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(name)}
+}
+
+// press sends keys to the model one at a time
+func press(m tuiModel, keys ...string) tuiModel {
+	for _, k := range keys {
+		next, _ := m.Update(keyMsg(k))
+		m = next.(tuiModel)
+	}
+	return m
+}
+
+// typeText sends each character of text as a key press
+func typeText(m tuiModel, text string) tuiModel {
+	for _, r := range text {
+		m = press(m, string(r))
+	}
+	return m
+}
+
+func TestTuiSwitchesViews(t *testing.T) {
+	store := newTestStore()
+	addScheduled(t, store, "Buy milk", nil, nil)
+	start, end := testNow, testNow.Add(15*time.Minute)
+	addScheduled(t, store, "Standup", &start, &end)
+	m := newTestTui(store)
+
+	if m.state != tuiTodayView || !slices.Equal(titles(m.todos), []string{"Standup"}) {
+		t.Fatalf("opened on state %v with %q, want today's blocks", m.state, titles(m.todos))
+	}
+
+	tests := []struct {
+		key   string
+		state tuiState
+		todos []string
+	}{
+		{key: "i", state: tuiInboxView, todos: []string{"Buy milk"}},
+		{key: "c", state: tuiCalendarView},
+		{key: "!", state: tuiConflictsView},
+		{key: "t", state: tuiTodayView, todos: []string{"Standup"}},
+	}
+	for _, tt := range tests {
+		m = press(m, tt.key)
+		if m.state != tt.state {
+			t.Errorf("%q: got state %v, want %v", tt.key, m.state, tt.state)
+		}
+		if tt.todos != nil && !slices.Equal(titles(m.todos), tt.todos) {
+			t.Errorf("%q: listed %q, want %q", tt.key, titles(m.todos), tt.todos)
+		}
+	}
+
+	if _, cmd := m.Update(keyMsg("q")); cmd == nil {
+		t.Error("q didn't quit")
+	}
+}
+
+func TestTuiListKeys(t *testing.T) {
+	store := newTestStore()
+	for _, title := range []string{"One", "Two", "Three"} {
+		addScheduled(t, store, title, nil, nil)
+	}
+	m := press(newTestTui(store), "i")
+
+	// Inbox is newest first: Three, Two, One
+	m = press(m, "j", "j", "j", "k")
+	if m.cursor != 1 {
+		t.Fatalf("cursor at %d, want 1 after moving past the end and back", m.cursor)
+	}
+
+	m = press(m, "enter")
+	if todo, _ := store.GetTodo(2); !todo.Done {
+		t.Error("enter didn't toggle the todo under the cursor")
+	}
+
+	m = press(m, "d")
+	if _, err := store.GetTodo(2); err == nil {
+		t.Error("d didn't delete the todo under the cursor")
+	}
+	if !slices.Equal(titles(m.todos), []string{"Three", "One"}) {
+		t.Errorf("list shows %q after deleting", titles(m.todos))
+	}
+}
+
+func TestTuiAddsAndEditsTodos(t *testing.T) {
+	store := newTestStore()
+	m := press(newTestTui(store), "i", "n")
+	if m.state != tuiAddView {
+		t.Fatalf("n opened state %v, want the add form", m.state)
+	}
+
+	m = typeText(m, "Write report")
+	m = press(m, "tab")
+	m = typeText(m, "Quarterly")
+	m = press(m, "tab", "tab")
+	m = typeText(m, "2030-01-15 2pm-3pm")
+	m = press(m, "enter")
+	if m.state != tuiInboxView {
+		t.Errorf("state %v after adding, want back in the inbox", m.state)
+	}
+
+	todo, err := store.GetTodo(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if todo.Title != "Write report" || todo.Description != "Quarterly" || !todo.ScheduledStart.Equal(*at(2030, 1, 15, 14, 0)) {
+		t.Errorf("added %+v", todo)
+	}
+
+	// Editing starts from the todo's title
+	addScheduled(t, store, "Buy milk", nil, nil)
+	m = press(m, "i", "e")
+	if m.state != tuiEditView || m.input != "Buy milk" {
+		t.Fatalf("e opened state %v with %q", m.state, m.input)
+	}
+	m = typeText(m, "!")
+	m = press(m, "enter")
+	if todo, _ := store.GetTodo(2); todo.Title != "Buy milk!" {
+		t.Errorf("edited title %q", todo.Title)
+	}
+}
+
+func TestTuiCaptureAddsTodos(t *testing.T) {
+	store := newTestStore()
+	m := press(newTestTui(store), "x")
+	if m.state != tuiCaptureView {
+		t.Fatalf("x opened state %v, want capture", m.state)
+	}
+	m = typeText(m, "Call mom -- about the trip")
+	m = press(m, "enter")
+	m = typeText(m, "Pay rent")
+	m = press(m, "enter")
+
+	// Blank lines add nothing
+	m = press(m, " ", "enter")
+
+	m = press(m, "esc")
+	if m.state != tuiInboxView || !slices.Equal(titles(m.todos), []string{"Pay rent", "Call mom"}) {
+		t.Errorf("state %v listing %q after capture", m.state, titles(m.todos))
+	}
+	if todo, _ := store.GetTodo(1); todo.Description != "about the trip" {
+		t.Errorf("captured %+v", todo)
+	}
+}
+
+func TestTuiCalendarKeys(t *testing.T) {
+	m := press(newTestTui(newTestStore()), "c")
+	start := m.calendar.GetDate()
+
+	tests := []struct {
+		keys  []string
+		view  CalendarView
+		date  time.Time
+		title string
+	}{
+		{keys: []string{"l"}, view: MonthView, date: start.AddDate(0, 1, 0), title: start.AddDate(0, 1, 0).Format("January 2006")},
+		{keys: []string{"h", "h"}, view: MonthView, date: start.AddDate(0, -1, 0), title: start.AddDate(0, -1, 0).Format("January 2006")},
+		{keys: []string{"l", "w"}, view: WeekView, date: start, title: "Week of"},
+		{keys: []string{"l"}, view: WeekView, date: start.AddDate(0, 0, 7)},
+		{keys: []string{"d"}, view: DayView, date: start.AddDate(0, 0, 7), title: start.AddDate(0, 0, 7).Format("Monday, Jan 2, 2006")},
+		{keys: []string{"h"}, view: DayView, date: start.AddDate(0, 0, 6)},
+		{keys: []string{"g"}, view: WeekView, date: start.AddDate(0, 0, 6), title: "Week of"},
+		{keys: []string{"m"}, view: MonthView, date: start.AddDate(0, 0, 6)},
+		{keys: []string{"n"}, view: MonthView, date: start.AddDate(0, 0, 6), title: "Wk"},
+		{keys: []string{"y"}, view: YearView, date: start.AddDate(0, 0, 6), title: start.AddDate(0, 0, 6).Format("📅 2006")},
+		{keys: []string{"l"}, view: YearView, date: start.AddDate(1, 0, 6), title: start.AddDate(1, 0, 6).Format("📅 2006")},
+	}
+	for _, tt := range tests {
+		m = press(m, tt.keys...)
+		if got := m.calendar.GetView(); got != tt.view {
+			t.Errorf("%q: view %v, want %v", tt.keys, got, tt.view)
+		}
+		if got := m.calendar.GetDate(); got.Format("2006-01-02") != tt.date.Format("2006-01-02") {
+			t.Errorf("%q: at %v, want %v", tt.keys, got, tt.date)
+		}
+		if view := m.View(); tt.title != "" && !strings.Contains(view, tt.title) {
+			t.Errorf("%q: view missing %q:\n%s", tt.keys, tt.title, view)
+		}
+	}
+	if !m.calendar.Grid() {
+		t.Error("g didn't switch the week to a time grid")
+	}
+}
+
+func TestTuiAgendaKeys(t *testing.T) {
+	store := newTestStore()
+	addAgendaTodos(t, store)
+	m := press(newTestTui(store), "a")
+	if m.state != tuiAgendaView {
+		t.Fatalf("a opened state %v, want the agenda", m.state)
+	}
+	assertContains(t, m.View(), "Oct 19 - Oct 25, 2026", "Renew passport", "Pay rent")
+
+	m = press(m, "+", "+")
+	if m.agendaDays != defaultAgendaDays+2 {
+		t.Errorf("showing %d days after +, want %d", m.agendaDays, defaultAgendaDays+2)
+	}
+	for range defaultAgendaDays + 5 {
+		m = press(m, "-")
+	}
+	if m.agendaDays != 1 {
+		t.Errorf("showing %d days, want at least one", m.agendaDays)
+	}
+	assertContains(t, m.View(), "Agenda: Oct 19, 2026")
+}
+
+func TestTuiSelectionKeys(t *testing.T) {
+	store := newTestStore()
+	clock := NewFixedClock(testNow)
+	addTodos(t, store, clock, "Buy milk", "Call mom", "Water plants")
+	m := press(newTestTui(store), "i")
+
+	m = press(m, " ", " ")
+	if len(m.marked) != 2 || m.cursor != 2 {
+		t.Fatalf("space twice marked %d todos with the cursor at %d, want 2 at row 2", len(m.marked), m.cursor)
+	}
+	m = press(m, "esc")
+	if len(m.selection()) != 0 {
+		t.Error("esc should clear the selection")
+	}
+
+	m = press(m, "v", "k")
+	if got := len(m.selection()); got != 2 {
+		t.Errorf("visual range selects %d todos, want 2", got)
+	}
+	m = press(m, "v", "enter")
+	for _, id := range []int{1, 2, 3} {
+		todo, _ := store.GetTodo(id)
+		want := todo.Title != m.todos[0].Title
+		if todo.Done != want {
+			t.Errorf("%q done = %v after enter, want %v", todo.Title, todo.Done, want)
+		}
+	}
+
+	m = press(m, " ", "t")
+	if len(m.marked) != 0 {
+		t.Error("switching views should clear the marks")
+	}
+
+	m = press(m, "i", "V", "d")
+	if todos, _ := store.GetAllTodos(); len(todos) != 0 {
+		t.Errorf("V then d left %v", titles(todos))
+	}
+}
+
+func TestTuiBatchActions(t *testing.T) {
+	store := newTestStore()
+	clock := NewFixedClock(testNow)
+	addTodos(t, store, clock, "Buy milk +home", "Call mom #phone")
+	m := press(newTestTui(store), "i", "V", "#")
+	if m.state != tuiBatchView {
+		t.Fatalf("# opened state %v, want the batch prompt", m.state)
+	}
+	m = press(typeText(m, "work"), "enter")
+	if m.state != tuiBatchView || m.batchErr == nil {
+		t.Fatal("a tag without + or - should keep the prompt open with an error")
+	}
+	// Leaving the prompt keeps the selection
+	m = press(m, "esc")
+	m = press(typeText(press(m, "#"), "+errand -phone"), "enter")
+	m = press(typeText(press(m, "V", "m"), "work"), "enter")
+	m = press(typeText(press(m, "V", "r"), "tomorrow 2pm-3pm"), "enter")
+	if m.state != tuiInboxView || len(m.selection()) != 0 {
+		t.Errorf("the batch prompt should close and clear the selection")
+	}
+
+	for _, id := range []int{1, 2} {
+		todo, _ := store.GetTodo(id)
+		if !slices.Equal(todo.Tags, []string{"errand"}) {
+			t.Errorf("%q tags = %v, want [errand]", todo.Title, todo.Tags)
+		}
+		if todo.Project != "work" {
+			t.Errorf("%q project = %q, want work", todo.Title, todo.Project)
+		}
+		if todo.ScheduledStart == nil || !todo.ScheduledStart.Equal(*at(2026, 10, 20, 14, 0)) {
+			t.Errorf("%q scheduled at %v, want tomorrow 2pm", todo.Title, todo.ScheduledStart)
+		}
+	}
+}
+
+// newSyncTui opens the TUI over store, syncing every interval
+func newSyncTui(store Store, interval time.Duration) tuiModel {
+	return NewTuiModel(store, NewFixedClock(testNow), DefaultCalendarOptions(), interval, DefaultFocusOptions())
+}
+
+// runSync plays a sync command through the model the way the tea runtime
+// would, returning the command that follows it
+func runSync(t *testing.T, m tuiModel, cmd tea.Cmd) (tuiModel, tea.Cmd) {
+	t.Helper()
+	if cmd == nil {
+		t.Fatal("no sync command")
+	}
+	msg, ok := cmd().(syncResultMsg)
+	if !ok {
+		t.Fatal("sync command didn't report a result")
+	}
+	next, cmd := m.Update(msg)
+	return next.(tuiModel), cmd
+}
+
+func TestTuiSyncsOnEachInterval(t *testing.T) {
+	db := newTestDB(t)
+	syncer := &fakeSyncer{frames: 2}
+	db.syncer = syncer
+	m := newSyncTui(db, time.Millisecond)
+
+	// Starting up syncs straight away
+	m, next := runSync(t, m, m.Init())
+	if m.sync.err != nil || m.sync.lastSync.IsZero() || m.sync.lastResult.FramesSynced != 2 {
+		t.Fatalf("got status %+v after the first sync", m.sync)
+	}
+
+	// then again whenever the interval comes round
+	for want := 2; want <= 3; want++ {
+		tick, ok := next().(syncTickMsg)
+		if !ok {
+			t.Fatal("sync result didn't schedule the next sync")
+		}
+		updated, cmd := m.Update(tick)
+		m = updated.(tuiModel)
+		if !m.sync.syncing || !strings.Contains(m.renderSyncStatus(), "syncing") {
+			t.Errorf("tick %d: status %q, want syncing", want, m.renderSyncStatus())
+		}
+		m, next = runSync(t, m, cmd)
+		if syncer.calls != want || m.sync.lastResult.FrameNo != 2*want {
+			t.Errorf("tick %d: %d syncs, frame %d", want, syncer.calls, m.sync.lastResult.FrameNo)
+		}
+	}
+}
+
+func TestTuiSyncIntervalZeroSyncsOnce(t *testing.T) {
+	db := newTestDB(t)
+	db.syncer = &fakeSyncer{frames: 1}
+	m := newSyncTui(db, 0)
+
+	if _, next := runSync(t, m, m.Init()); next != nil {
+		t.Error("scheduled another sync with periodic sync disabled")
+	}
+}
+
+func TestTuiSyncPicksUpRemoteChanges(t *testing.T) {
+	db := newTestDB(t)
+	db.syncer = &fakeSyncer{frames: 1}
+	m := press(newSyncTui(db, 0), "i")
+
+	// Stands in for a row the replica pulled from the primary
+	if err := db.AddTodo(Todo{Title: "From my laptop"}); err != nil {
+		t.Fatal(err)
+	}
+	m, _ = runSync(t, m, m.syncCmd())
+	if got := titles(m.todos); !slices.Equal(got, []string{"From my laptop"}) {
+		t.Errorf("got %v after syncing new frames, want the pulled todo", got)
+	}
+}
+
+func TestTuiShowsSyncErrors(t *testing.T) {
+	db := newTestDB(t)
+	syncer := &fakeSyncer{err: errors.New("connection refused")}
+	db.syncer = syncer
+	m := newSyncTui(db, time.Millisecond)
+
+	m, next := runSync(t, m, m.Init())
+	if m.sync.err == nil || !strings.Contains(m.renderSyncStatus(), "sync failed") {
+		t.Errorf("status %q, want the failure shown", m.renderSyncStatus())
+	}
+	if next == nil {
+		t.Fatal("a failed sync stopped periodic syncing")
+	}
+
+	// The next sync that gets through clears the error
+	syncer.err = nil
+	updated, cmd := m.Update(next().(syncTickMsg))
+	m, _ = runSync(t, updated.(tuiModel), cmd)
+	if m.sync.err != nil || !strings.Contains(m.renderSyncStatus(), "synced just now") {
+		t.Errorf("status %q after recovering, want synced", m.renderSyncStatus())
+	}
+}